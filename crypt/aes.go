@@ -18,6 +18,8 @@ import (
 // The key argument should be the AES key, either 16, 24, or 32 bytes to select AES-128, AES-192, or AES-256
 // In ECB and CBC mode, If the original plaintext lengths are not a multiple of the block size,padding would have to be
 // added when encrypting,here we use PKCS7Padding, blockSize is 16. For more padding algorithms, see padding.go
+// SIV(Synthetic Initialization Vector, RFC 5297) mode is deterministic and authenticated, the key must be 32, 48 or 64
+// bytes, see aes_siv.go
 
 // AESEncrypt Encrypts data with AES algorithm in specify mode.
 // Recommended in combination with base64,such as: Base64Encode(AESEncrypt(plaintext,key,am))
@@ -29,6 +31,8 @@ func AESEncrypt(plaintext, key []byte, am AesMode) ([]byte, error) {
 		return aesECBEncrypt(plaintext, key)
 	case AesModeCFB, AesModeCTR, AesModeOFB:
 		return aesStreamEncrypt(plaintext, key, am)
+	case AesModeSIV:
+		return AESSIVEncrypt(plaintext, key)
 	default:
 		return nil, errors.New(sErrAesModeInvalid)
 	}
@@ -44,6 +48,8 @@ func AESDecrypt(ciphertext, key []byte, am AesMode) ([]byte, error) {
 		return aesECBDecrypt(ciphertext, key)
 	case AesModeCFB, AesModeCTR, AesModeOFB:
 		return aesStreamDecrypt(ciphertext, key, am)
+	case AesModeSIV:
+		return AESSIVDecrypt(ciphertext, key)
 	default:
 		return nil, errors.New(sErrAesModeInvalid)
 	}
@@ -225,3 +231,13 @@ func aesStreamDecrypt(ciphertext, key []byte, am AesMode) ([]byte, error) {
 	stream.XORKeyStream(ciphertext, ciphertext)
 	return ciphertext, nil
 }
+
+// newAESCipher return the AES block cipher of key, an error is returned if the key is not 16, 24 or 32 bytes
+func newAESCipher(key []byte) (cipher.Block, error) {
+	switch len(key) {
+	case 16, 24, 32:
+		return aes.NewCipher(key)
+	default:
+		return nil, errors.New(sErrKeySizeInvalid)
+	}
+}
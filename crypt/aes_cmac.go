@@ -0,0 +1,130 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"hash"
+)
+
+// CMAC(Cipher-based Message Authentication Code, also known as OMAC1) is a MAC built on a block cipher,
+// see NIST SP 800-38B and RFC 4493(AES-CMAC).
+// Two subkeys are derived from L = AES(K, 0^128): K1 = L<<1 and K2 = K1<<1, each shift XOR 0x87 into the last byte if
+// the shifted-out bit is 1(doubling in GF(2^128)). The message is processed in CBC mode with a zero IV, the last block
+// is XORed with K1 if it is full, otherwise it is padded with 0x80 and zeros and XORed with K2. The tag is the last
+// CBC output, 16 bytes.
+
+// cmacRb the constant of the doubling in GF(2^128)
+const cmacRb = 0x87
+
+// cmac implement hash.Hash for CMAC of a 128-bit block cipher
+type cmac struct {
+	block  cipher.Block
+	k1, k2 [aes.BlockSize]byte
+	x      [aes.BlockSize]byte // the CBC chaining value
+	buf    [aes.BlockSize]byte // the pending data, the last block is kept until Sum
+	n      int
+}
+
+// NewAESCMAC return a hash.Hash computing the AES-CMAC with the key, the key should be 16, 24 or 32 bytes
+func NewAESCMAC(key []byte) (hash.Hash, error) {
+	block, err := newAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return newCMAC(block), nil
+}
+
+// AESCMAC return the 16-byte AES-CMAC of data with the key, the key should be 16, 24 or 32 bytes
+func AESCMAC(data, key []byte) ([]byte, error) {
+	h, err := NewAESCMAC(key)
+	if err != nil {
+		return nil, err
+	}
+	h.Write(data)
+	return h.Sum(nil), nil
+}
+
+// AESCMACVerify return whether mac is the AES-CMAC of data with the key, in constant time.
+// The invalid key return false
+func AESCMACVerify(data, mac, key []byte) bool {
+	sum, err := AESCMAC(data, key)
+	if err != nil {
+		return false
+	}
+	return subtle.ConstantTimeCompare(sum, mac) == 1
+}
+
+// newCMAC return a CMAC of block, the block size must be 16
+func newCMAC(block cipher.Block) *cmac {
+	c := &cmac{block: block}
+	block.Encrypt(c.k1[:], c.k1[:])
+	gfDouble(&c.k1, &c.k1)
+	gfDouble(&c.k2, &c.k1)
+	return c
+}
+
+// gfDouble set dst = src * x in GF(2^128), in constant time
+func gfDouble(dst, src *[aes.BlockSize]byte) {
+	msb := src[0] >> 7
+	for i := 0; i < aes.BlockSize-1; i++ {
+		dst[i] = src[i]<<1 | src[i+1]>>7
+	}
+	dst[aes.BlockSize-1] = src[aes.BlockSize-1]<<1 ^ byte(subtle.ConstantTimeSelect(int(msb), cmacRb, 0))
+}
+
+// Write implement hash.Hash, it never returns an error
+func (c *cmac) Write(p []byte) (int, error) {
+	n := len(p)
+	for len(p) > 0 {
+		// The buffered block is processed only when more data comes, so that the last block is left for Sum
+		if c.n == aes.BlockSize {
+			xorBytes(c.x[:], c.buf[:])
+			c.block.Encrypt(c.x[:], c.x[:])
+			c.n = 0
+		}
+		m := copy(c.buf[c.n:], p)
+		c.n += m
+		p = p[m:]
+	}
+	return n, nil
+}
+
+// Sum implement hash.Hash, append the tag to b, the state is not changed
+func (c *cmac) Sum(b []byte) []byte {
+	x := c.x
+	var last [aes.BlockSize]byte
+	copy(last[:], c.buf[:c.n])
+	if c.n == aes.BlockSize {
+		xorBytes(last[:], c.k1[:])
+	} else {
+		last[c.n] = 0x80
+		xorBytes(last[:], c.k2[:])
+	}
+	xorBytes(x[:], last[:])
+	c.block.Encrypt(x[:], x[:])
+	return append(b, x[:]...)
+}
+
+// Reset implement hash.Hash
+func (c *cmac) Reset() {
+	c.x = [aes.BlockSize]byte{}
+	c.n = 0
+}
+
+// Size implement hash.Hash
+func (c *cmac) Size() int {
+	return aes.BlockSize
+}
+
+// BlockSize implement hash.Hash
+func (c *cmac) BlockSize() int {
+	return aes.BlockSize
+}
+
+// xorBytes set dst[i] ^= src[i] for i in [0,len(dst))
+func xorBytes(dst, src []byte) {
+	for i := range dst {
+		dst[i] ^= src[i]
+	}
+}
@@ -0,0 +1,60 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestAESCMAC(t *testing.T) {
+	// RFC 4493 section 4
+	key, _ := hex.DecodeString("2b7e151628aed2a6abf7158809cf4f3c")
+	msg, _ := hex.DecodeString("6bc1bee22e409f96e93d7e117393172aae2d8a571e03ac9c9eb76fac45af8e5130c81c46a35ce411" +
+		"e5fbc1191a0a52eff69f2445df4f9b17ad2b417be66c3710")
+	tests := []struct {
+		name string
+		len  int
+		want string
+	}{
+		{name: "Empty", len: 0, want: "bb1d6929e95937287fa37d129b756746"},
+		{name: "16Bytes", len: 16, want: "070a16b46b4d4144f79bdd9dd04a287c"},
+		{name: "40Bytes", len: 40, want: "dfa66747de9ae63030ca32611497c827"},
+		{name: "64Bytes", len: 64, want: "51f0bebf7e3b9d92fc49741779363cfe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := AESCMAC(msg[:tt.len], key)
+			if err != nil || hex.EncodeToString(got) != tt.want {
+				t.Errorf("AESCMAC() = %x, %v, want %v", got, err, tt.want)
+			}
+			want, _ := hex.DecodeString(tt.want)
+			if !AESCMACVerify(msg[:tt.len], want, key) {
+				t.Errorf("AESCMACVerify() = false, want true")
+			}
+			if AESCMACVerify(msg[:tt.len], want[:15], key) {
+				t.Errorf("AESCMACVerify() truncated = true, want false")
+			}
+
+			// write byte by byte, Sum does not change the state
+			h, _ := NewAESCMAC(key)
+			for i := 0; i < tt.len; i++ {
+				h.Write(msg[i : i+1])
+				h.Sum(nil)
+			}
+			if got := hex.EncodeToString(h.Sum(nil)); got != tt.want {
+				t.Errorf("NewAESCMAC() Sum = %v, want %v", got, tt.want)
+			}
+			h.Reset()
+			h.Write(msg[:tt.len])
+			if got := hex.EncodeToString(h.Sum(nil)); got != tt.want {
+				t.Errorf("NewAESCMAC() Sum after Reset = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := AESCMAC(msg, invalidKey); err == nil || err.Error() != sErrKeySizeInvalid {
+		t.Errorf("AESCMAC() invalid key error = %v, want %v", err, sErrKeySizeInvalid)
+	}
+	if AESCMACVerify(msg, msg[:16], invalidKey) {
+		t.Errorf("AESCMACVerify() invalid key = true, want false")
+	}
+}
@@ -0,0 +1,164 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+)
+
+// AES Key Wrap(AES-KW) encrypts a key with a key-encryption key(KEK), it is used by the HSM and KMS to deliver keys,
+// see RFC 3394 and NIST SP 800-38F. The KEK is an AES key of 16, 24 or 32 bytes.
+// The key to wrap is n 64-bit blocks(n >= 2), the wrapping runs 6*n rounds of AES over the integrity check register A
+// (initialized to the IV 0xA6A6A6A6A6A6A6A6) and the blocks, the result is 8 bytes longer than the input. When
+// unwrapping, the recovered A must be equal to the IV, otherwise the wrapped key is corrupted or the KEK is wrong.
+// AES Key Wrap with Padding(AES-KWP, RFC 5649) accepts the key of any length from 1 byte: the alternative IV is
+// 0xA65959A6 || the length of the key(32-bit big-endian), the key is padded with zeros to a multiple of 8 bytes, and a
+// padded key of 8 bytes is encrypted in one AES block.
+
+const keyWrapBlockSize = 8
+
+// keyWrapIV the default IV of RFC 3394
+var keyWrapIV = []byte{0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6, 0xA6}
+
+// keyWrapPadIV the high 32 bits of the alternative IV of RFC 5649
+var keyWrapPadIV = []byte{0xA6, 0x59, 0x59, 0xA6}
+
+// AESKeyWrap Wraps key with the key-encryption key kek by AES-KW(RFC 3394).
+// The length of key must be a multiple of 8 and at least 16, the result is 8 bytes longer than key
+func AESKeyWrap(key, kek []byte) ([]byte, error) {
+	block, err := newAESCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) < 2*keyWrapBlockSize || len(key)%keyWrapBlockSize != 0 {
+		return nil, errors.New(sErrDataLenInvalid)
+	}
+	return keyWrap(block, keyWrapIV, key), nil
+}
+
+// AESKeyUnwrap Unwraps the result of AESKeyWrap with the key-encryption key kek, an error is returned if the
+// integrity check fails
+func AESKeyUnwrap(wrapped, kek []byte) ([]byte, error) {
+	block, err := newAESCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 3*keyWrapBlockSize || len(wrapped)%keyWrapBlockSize != 0 {
+		return nil, errors.New(sErrDataLenInvalid)
+	}
+	a, key := keyUnwrap(block, wrapped)
+	if subtle.ConstantTimeCompare(a, keyWrapIV) != 1 {
+		return nil, errors.New(sErrKeyUnwrapFailed)
+	}
+	return key, nil
+}
+
+// AESKeyWrapPad Wraps key of any length with the key-encryption key kek by AES-KWP(RFC 5649).
+// The length of key must be greater than 0, the result is the length of key rounded up to a multiple of 8 plus 8
+func AESKeyWrapPad(key, kek []byte) ([]byte, error) {
+	block, err := newAESCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(key) == 0 || uint64(len(key)) > 1<<32-1 {
+		return nil, errors.New(sErrDataLenInvalid)
+	}
+
+	iv := make([]byte, keyWrapBlockSize)
+	copy(iv, keyWrapPadIV)
+	binary.BigEndian.PutUint32(iv[4:], uint32(len(key)))
+	padded := make([]byte, (len(key)+keyWrapBlockSize-1)/keyWrapBlockSize*keyWrapBlockSize)
+	copy(padded, key)
+
+	if len(padded) == keyWrapBlockSize {
+		out := make([]byte, aes.BlockSize)
+		copy(out, iv)
+		copy(out[keyWrapBlockSize:], padded)
+		block.Encrypt(out, out)
+		return out, nil
+	}
+	return keyWrap(block, iv, padded), nil
+}
+
+// AESKeyUnwrapPad Unwraps the result of AESKeyWrapPad with the key-encryption key kek, an error is returned if the
+// integrity check fails
+func AESKeyUnwrapPad(wrapped, kek []byte) ([]byte, error) {
+	block, err := newAESCipher(kek)
+	if err != nil {
+		return nil, err
+	}
+	if len(wrapped) < 2*keyWrapBlockSize || len(wrapped)%keyWrapBlockSize != 0 {
+		return nil, errors.New(sErrDataLenInvalid)
+	}
+
+	var a, padded []byte
+	if len(wrapped) == aes.BlockSize {
+		out := make([]byte, aes.BlockSize)
+		block.Decrypt(out, wrapped)
+		a, padded = out[:keyWrapBlockSize], out[keyWrapBlockSize:]
+	} else {
+		a, padded = keyUnwrap(block, wrapped)
+	}
+
+	// Check the IV, the length of the key and the zero padding
+	mli := binary.BigEndian.Uint32(a[4:])
+	if subtle.ConstantTimeCompare(a[:4], keyWrapPadIV) != 1 || int(mli) <= len(padded)-keyWrapBlockSize ||
+		int(mli) > len(padded) {
+		return nil, errors.New(sErrKeyUnwrapFailed)
+	}
+	for _, c := range padded[mli:] {
+		if c != 0 {
+			return nil, errors.New(sErrKeyUnwrapFailed)
+		}
+	}
+	return padded[:mli], nil
+}
+
+// keyWrap the wrapping process W of RFC 3394 with the initial value iv, len(plaintext) must be a multiple of 8
+func keyWrap(block cipher.Block, iv, plaintext []byte) []byte {
+	n := len(plaintext) / keyWrapBlockSize
+	out := make([]byte, keyWrapBlockSize+len(plaintext))
+	copy(out, iv)
+	copy(out[keyWrapBlockSize:], plaintext)
+
+	var b [aes.BlockSize]byte
+	copy(b[:keyWrapBlockSize], iv)
+	for j := 0; j < 6; j++ {
+		for i := 1; i <= n; i++ {
+			r := out[i*keyWrapBlockSize : (i+1)*keyWrapBlockSize]
+			// B = AES(K, A | R[i]), A = MSB(64, B) ^ t, R[i] = LSB(64, B)
+			copy(b[keyWrapBlockSize:], r)
+			block.Encrypt(b[:], b[:])
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:keyWrapBlockSize], binary.BigEndian.Uint64(b[:keyWrapBlockSize])^t)
+			copy(r, b[keyWrapBlockSize:])
+		}
+	}
+	copy(out, b[:keyWrapBlockSize])
+	return out
+}
+
+// keyUnwrap the unwrapping process W^-1 of RFC 3394, return the integrity check register A and the plaintext,
+// len(ciphertext) must be a multiple of 8 and at least 24
+func keyUnwrap(block cipher.Block, ciphertext []byte) ([]byte, []byte) {
+	n := len(ciphertext)/keyWrapBlockSize - 1
+	out := make([]byte, len(ciphertext)-keyWrapBlockSize)
+	copy(out, ciphertext[keyWrapBlockSize:])
+
+	var b [aes.BlockSize]byte
+	copy(b[:keyWrapBlockSize], ciphertext[:keyWrapBlockSize])
+	for j := 5; j >= 0; j-- {
+		for i := n; i >= 1; i-- {
+			r := out[(i-1)*keyWrapBlockSize : i*keyWrapBlockSize]
+			// B = AES-1(K, (A ^ t) | R[i]), A = MSB(64, B), R[i] = LSB(64, B)
+			t := uint64(n*j + i)
+			binary.BigEndian.PutUint64(b[:keyWrapBlockSize], binary.BigEndian.Uint64(b[:keyWrapBlockSize])^t)
+			copy(b[keyWrapBlockSize:], r)
+			block.Decrypt(b[:], b[:])
+			copy(r, b[keyWrapBlockSize:])
+		}
+	}
+	return b[:keyWrapBlockSize], out
+}
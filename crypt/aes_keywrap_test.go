@@ -0,0 +1,162 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestAESKeyWrap(t *testing.T) {
+	tests := []struct {
+		name    string
+		kek     string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "RFC3394_4.1",
+			kek:  "000102030405060708090A0B0C0D0E0F",
+			key:  "00112233445566778899AABBCCDDEEFF",
+			want: "1fa68b0a8112b447aef34bd8fb5a7b829d3e862371d2cfe5",
+		},
+		{
+			name: "RFC3394_4.2",
+			kek:  "000102030405060708090A0B0C0D0E0F1011121314151617",
+			key:  "00112233445566778899AABBCCDDEEFF",
+			want: "96778b25ae6ca435f92b5b97c050aed2468ab8a17ad84e5d",
+		},
+		{
+			name: "RFC3394_4.6",
+			kek:  "000102030405060708090A0B0C0D0E0F101112131415161718191A1B1C1D1E1F",
+			key:  "00112233445566778899AABBCCDDEEFF000102030405060708090A0B0C0D0E0F",
+			want: "28c9f404c4b810f4cbccb35cfb87f8263f5786e2d80ed326cbc7f0e71a99f43bfb988b9b7a02dd21",
+		},
+		{
+			name:    "KeyTooShort",
+			kek:     "000102030405060708090A0B0C0D0E0F",
+			key:     "0011223344556677",
+			wantErr: true,
+		},
+		{
+			name:    "KeyNotMultipleOf8",
+			kek:     "000102030405060708090A0B0C0D0E0F",
+			key:     "00112233445566778899AABBCCDDEEFF00",
+			wantErr: true,
+		},
+		{
+			name:    "KekInvalidLen",
+			kek:     "000102030405060708090A0B0C0D",
+			key:     "00112233445566778899AABBCCDDEEFF",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			kek, _ := hex.DecodeString(tt.kek)
+			key, _ := hex.DecodeString(tt.key)
+			got, err := AESKeyWrap(key, kek)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AESKeyWrap() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("AESKeyWrap() = %x, want %v", got, tt.want)
+			}
+			unwrapped, err := AESKeyUnwrap(got, kek)
+			if err != nil || !bytes.Equal(unwrapped, key) {
+				t.Errorf("AESKeyUnwrap() = %x, %v, want %x", unwrapped, err, key)
+			}
+			got[len(got)-1] ^= 1
+			if _, err = AESKeyUnwrap(got, kek); err == nil {
+				t.Errorf("AESKeyUnwrap() corrupted error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestAESKeyWrapPad(t *testing.T) {
+	kek, _ := hex.DecodeString("5840df6e29b02af1ab493b705bf16ea1ae8338f4dcc176a8")
+	tests := []struct {
+		name    string
+		key     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "RFC5649_20Bytes",
+			key:  "c37b7e6492584340bed12207808941155068f738",
+			want: "138bdeaa9b8fa7fc61f97742e72248ee5ae6ae5360d1ae6a5f54f373fa543b6a",
+		},
+		{
+			name: "RFC5649_7Bytes",
+			key:  "466f7250617369",
+			want: "afbeb0f07dfbf5419200f2ccb50bb24f",
+		},
+		{
+			name:    "KeyEmpty",
+			key:     "",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _ := hex.DecodeString(tt.key)
+			got, err := AESKeyWrapPad(key, kek)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AESKeyWrapPad() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("AESKeyWrapPad() = %x, want %v", got, tt.want)
+			}
+			unwrapped, err := AESKeyUnwrapPad(got, kek)
+			if err != nil || !bytes.Equal(unwrapped, key) {
+				t.Errorf("AESKeyUnwrapPad() = %x, %v, want %x", unwrapped, err, key)
+			}
+			got[0] ^= 1
+			if _, err = AESKeyUnwrapPad(got, kek); err == nil {
+				t.Errorf("AESKeyUnwrapPad() corrupted error = nil, want error")
+			}
+		})
+	}
+
+	// every length from 1 to 40 bytes
+	for n := 1; n <= 40; n++ {
+		key := bytes.Repeat([]byte{byte(n)}, n)
+		wrapped, err := AESKeyWrapPad(key, commonKey16)
+		if err != nil || len(wrapped) != (n+7)/8*8+8 {
+			t.Fatalf("AESKeyWrapPad() len %d = %d, %v", n, len(wrapped), err)
+		}
+		if got, err := AESKeyUnwrapPad(wrapped, commonKey16); err != nil || !bytes.Equal(got, key) {
+			t.Errorf("AESKeyUnwrapPad() len %d = %x, %v, want %x", n, got, err, key)
+		}
+	}
+
+	// The result of AES-KW is rejected by AES-KWP because of the different IV
+	wrapped, _ := AESKeyWrap(commonKey16, commonKey16)
+	if _, err := AESKeyUnwrapPad(wrapped, commonKey16); err == nil {
+		t.Errorf("AESKeyUnwrapPad() of AES-KW error = nil, want error")
+	}
+}
+
+func TestAESKeyWrap_InvalidKek(t *testing.T) {
+	kek := make([]byte, 20)
+	key := make([]byte, 16)
+	if _, err := AESKeyWrap(key, kek); err == nil || err.Error() != sErrKeySizeInvalid {
+		t.Errorf("AESKeyWrap() error = %v, want %v", err, sErrKeySizeInvalid)
+	}
+	if _, err := AESKeyUnwrap(make([]byte, 24), kek); err == nil || err.Error() != sErrKeySizeInvalid {
+		t.Errorf("AESKeyUnwrap() error = %v, want %v", err, sErrKeySizeInvalid)
+	}
+	if _, err := AESKeyWrapPad(key, kek); err == nil || err.Error() != sErrKeySizeInvalid {
+		t.Errorf("AESKeyWrapPad() error = %v, want %v", err, sErrKeySizeInvalid)
+	}
+	if _, err := AESKeyUnwrapPad(make([]byte, 24), kek); err == nil || err.Error() != sErrKeySizeInvalid {
+		t.Errorf("AESKeyUnwrapPad() error = %v, want %v", err, sErrKeySizeInvalid)
+	}
+}
@@ -0,0 +1,114 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/subtle"
+	"errors"
+)
+
+// AES-SIV(Synthetic Initialization Vector) is a deterministic authenticated encryption mode, see RFC 5297.
+// The same plaintext and associated data under the same key always produce the same ciphertext, so the encrypted
+// columns can be looked up by equality, and the repeated nonce does not break the confidentiality other than
+// revealing the equal messages. It is also a good choice for key wrapping.
+// The key is split into two halves: K1 for the S2V(a CMAC based PRF over a vector of strings) and K2 for the CTR
+// mode, so the key is 32, 48 or 64 bytes for AES-128、AES-192 or AES-256.
+//  V = S2V(K1, AD1, ..., ADn, P)
+//  C = CTR(K2, V with bit 63 and 31 cleared, P)
+// The result is V(16 bytes) || C, the decryption recomputes V from the decrypted plaintext and compares it.
+// To add randomness, put a nonce as the last associated data.
+
+// AESSIVEncrypt Encrypts data with AES-SIV(RFC 5297) deterministically, additionalData are authenticated but not
+// encrypted, they are treated as a vector of strings, the order matters. The result is 16 bytes longer than plaintext.
+// The key must be 32, 48 or 64 bytes
+func AESSIVEncrypt(plaintext, key []byte, additionalData ...[]byte) ([]byte, error) {
+	mac, ctr, err := aesSIVCiphers(key)
+	if err != nil {
+		return nil, err
+	}
+
+	v := s2v(mac, additionalData, plaintext)
+	out := make([]byte, aes.BlockSize+len(plaintext))
+	copy(out, v[:])
+	aesSIVCTR(ctr, &v, out[aes.BlockSize:], plaintext)
+	return out, nil
+}
+
+// AESSIVDecrypt Decrypts the result of AESSIVEncrypt, additionalData must be the same as encryption
+func AESSIVDecrypt(ciphertext, key []byte, additionalData ...[]byte) ([]byte, error) {
+	mac, ctr, err := aesSIVCiphers(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(ciphertext) < aes.BlockSize {
+		return nil, errors.New(sErrDataInvalid)
+	}
+
+	var v [aes.BlockSize]byte
+	copy(v[:], ciphertext)
+	plaintext := make([]byte, len(ciphertext)-aes.BlockSize)
+	aesSIVCTR(ctr, &v, plaintext, ciphertext[aes.BlockSize:])
+
+	t := s2v(mac, additionalData, plaintext)
+	if subtle.ConstantTimeCompare(t[:], v[:]) != 1 {
+		return nil, errors.New(sErrAuthFailed)
+	}
+	return plaintext, nil
+}
+
+// aesSIVCiphers return the block ciphers of K1 and K2
+func aesSIVCiphers(key []byte) (cipher.Block, cipher.Block, error) {
+	if len(key) != 32 && len(key) != 48 && len(key) != 64 {
+		return nil, nil, errors.New(sErrKeySizeInvalid)
+	}
+	mac, err := aes.NewCipher(key[:len(key)/2])
+	if err != nil {
+		return nil, nil, err
+	}
+	ctr, err := aes.NewCipher(key[len(key)/2:])
+	if err != nil {
+		return nil, nil, err
+	}
+	return mac, ctr, nil
+}
+
+// aesSIVCTR XOR src with the CTR keystream of the synthetic IV v and write the result to dst
+func aesSIVCTR(block cipher.Block, v *[aes.BlockSize]byte, dst, src []byte) {
+	iv := *v
+	iv[8] &= 0x7F
+	iv[12] &= 0x7F
+	cipher.NewCTR(block, iv[:]).XORKeyStream(dst, src)
+}
+
+// s2v return S2V(K, AD1, ..., ADn, last) of RFC 5297
+func s2v(block cipher.Block, ad [][]byte, last []byte) [aes.BlockSize]byte {
+	c := newCMAC(block)
+	var d, t [aes.BlockSize]byte
+	c.Write(d[:])
+	c.Sum(d[:0])
+
+	for _, s := range ad {
+		c.Reset()
+		c.Write(s)
+		gfDouble(&d, &d)
+		xorBytes(d[:], c.Sum(t[:0]))
+	}
+
+	c.Reset()
+	if len(last) >= aes.BlockSize {
+		// T = Sn xorend D
+		c.Write(last[:len(last)-aes.BlockSize])
+		copy(t[:], last[len(last)-aes.BlockSize:])
+		xorBytes(t[:], d[:])
+		c.Write(t[:])
+	} else {
+		// T = dbl(D) xor pad(Sn)
+		gfDouble(&t, &d)
+		xorBytes(t[:len(last)], last)
+		t[len(last)] ^= 0x80
+		c.Write(t[:])
+	}
+	var v [aes.BlockSize]byte
+	c.Sum(v[:0])
+	return v
+}
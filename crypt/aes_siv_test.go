@@ -0,0 +1,102 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestAESSIVEncrypt(t *testing.T) {
+	hexList := func(list ...string) [][]byte {
+		res := make([][]byte, len(list))
+		for i, s := range list {
+			res[i], _ = hex.DecodeString(s)
+		}
+		return res
+	}
+	tests := []struct {
+		name      string
+		key       string
+		ad        [][]byte
+		plaintext string
+		want      string
+		wantErr   bool
+	}{
+		{
+			name:      "RFC5297_A.1",
+			key:       "fffefdfcfbfaf9f8f7f6f5f4f3f2f1f0f0f1f2f3f4f5f6f7f8f9fafbfcfdfeff",
+			ad:        hexList("101112131415161718191a1b1c1d1e1f2021222324252627"),
+			plaintext: "112233445566778899aabbccddee",
+			want:      "85632d07c6e8f37f950acd320a2ecc9340c02b9690c4dc04daef7f6afe5c",
+		},
+		{
+			name: "RFC5297_A.2",
+			key:  "7f7e7d7c7b7a79787776757473727170404142434445464748494a4b4c4d4e4f",
+			ad: hexList("00112233445566778899aabbccddeeffdeaddadadeaddadaffeeddccbbaa99887766554433221100",
+				"102030405060708090a0", "09f911029d74e35bd84156c5635688c0"),
+			plaintext: "7468697320697320736f6d6520706c61696e7465787420746f20656e6372797074207573696e67205349562d414553",
+			want: "7bdb6e3b432667eb06f4d14bff2fbd0fcb900f2fddbe404326601965c889bf17dba77ceb094fa663b7a3f748ba8af8" +
+				"29ea64ad544a272e9c485b62a3fd5c0d",
+		},
+		{
+			name:    "KeyInvalidLen",
+			key:     "000102030405060708090a0b0c0d0e0f",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _ := hex.DecodeString(tt.key)
+			plaintext, _ := hex.DecodeString(tt.plaintext)
+			got, err := AESSIVEncrypt(plaintext, key, tt.ad...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("AESSIVEncrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if hex.EncodeToString(got) != tt.want {
+				t.Errorf("AESSIVEncrypt() = %x, want %v", got, tt.want)
+			}
+			decrypted, err := AESSIVDecrypt(got, key, tt.ad...)
+			if err != nil || !bytes.Equal(decrypted, plaintext) {
+				t.Errorf("AESSIVDecrypt() = %x, %v, want %x", decrypted, err, plaintext)
+			}
+			if _, err = AESSIVDecrypt(got, key); err == nil {
+				t.Errorf("AESSIVDecrypt() without associated data error = nil, want error")
+			}
+			got[len(got)-1] ^= 1
+			if _, err = AESSIVDecrypt(got, key, tt.ad...); err == nil {
+				t.Errorf("AESSIVDecrypt() tampered error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestAESEncryptSIV(t *testing.T) {
+	key64 := append(append([]byte{}, commonKey32...), commonKey32...)
+	for _, key := range [][]byte{commonKey32, key64[:48], key64} {
+		for _, data := range [][]byte{{}, commonOriginData[:15], commonOriginData[:16], commonOriginData} {
+			first, err := AESEncrypt(data, key, AesModeSIV)
+			if err != nil {
+				t.Fatalf("AESEncrypt() error = %v", err)
+			}
+			// deterministic: the same plaintext produce the same ciphertext
+			second, _ := AESEncrypt(data, key, AesModeSIV)
+			if !bytes.Equal(first, second) {
+				t.Errorf("AESEncrypt() = %x and %x, want equal", first, second)
+			}
+			got, err := AESDecrypt(first, key, AesModeSIV)
+			if err != nil || !bytes.Equal(got, data) {
+				t.Errorf("AESDecrypt() = %v, %v, want %v", got, err, data)
+			}
+		}
+	}
+
+	if _, err := AESEncrypt(commonOriginData, commonKey16, AesModeSIV); err == nil {
+		t.Errorf("AESEncrypt() key of 16 bytes error = nil, want error")
+	}
+	if _, err := AESDecrypt(commonOriginData[:15], commonKey32, AesModeSIV); err == nil {
+		t.Errorf("AESDecrypt() short data error = nil, want error")
+	}
+}
@@ -0,0 +1,84 @@
+package crypt
+
+import (
+	"encoding/ascii85"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// The Ascii85 encodes every 4 bytes as 5 characters of '!' to 'u', it is used by PostScript and PDF. Ascii85Encode
+// returns the result without the delimiters, Ascii85Decode accepts the result with or without the delimiters <~ ~>.
+// The Z85 of ZeroMQ(RFC 32) uses an alphabet safe in the source codes, it requires the length of the data to be a
+// multiple of 4 and does not have the 'z' abbreviation of Ascii85.
+
+const (
+	z85Alphabet       = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ.-:+=^!/*?&<>()[]{}@%$#"
+	ascii85StartDelim = "<~"
+	ascii85EndDelim   = "~>"
+)
+
+// Ascii85Encode the Ascii85 encode without the delimiters
+func Ascii85Encode(data []byte) string {
+	buf := make([]byte, ascii85.MaxEncodedLen(len(data)))
+	n := ascii85.Encode(buf, data)
+	return string(buf[:n])
+}
+
+// Ascii85Decode the Ascii85 decode, the delimiters <~ ~> and the white spaces are ignored
+func Ascii85Decode(str string) ([]byte, error) {
+	str = strings.TrimSpace(str)
+	if strings.HasPrefix(str, ascii85StartDelim) && strings.HasSuffix(str, ascii85EndDelim) {
+		str = str[len(ascii85StartDelim) : len(str)-len(ascii85EndDelim)]
+	}
+	buf := make([]byte, 4*len(str))
+	n, _, err := ascii85.Decode(buf, []byte(str), true)
+	if err != nil {
+		var corrupt ascii85.CorruptInputError
+		if errors.As(err, &corrupt) {
+			return nil, fmt.Errorf("%s at offset %d", sErrAscii85Invalid, int64(corrupt))
+		}
+		return nil, errors.New(sErrAscii85Invalid)
+	}
+	return buf[:n], nil
+}
+
+// Z85Encode the Z85 encode, the length of data must be a multiple of 4
+func Z85Encode(data []byte) (string, error) {
+	if len(data)%4 != 0 {
+		return "", errors.New(sErrZ85Invalid)
+	}
+	res := make([]byte, len(data)/4*5)
+	for i := 0; i < len(data); i += 4 {
+		v := binary.BigEndian.Uint32(data[i:])
+		for j := 4; j >= 0; j-- {
+			res[i/4*5+j] = z85Alphabet[v%85]
+			v /= 85
+		}
+	}
+	return string(res), nil
+}
+
+// Z85Decode the Z85 decode, the length of str must be a multiple of 5
+func Z85Decode(str string) ([]byte, error) {
+	if len(str)%5 != 0 {
+		return nil, errors.New(sErrZ85Invalid)
+	}
+	res := make([]byte, len(str)/5*4)
+	for i := 0; i < len(str); i += 5 {
+		var v uint64
+		for j := i; j < i+5; j++ {
+			idx := baseIndex(z85Alphabet, str[j])
+			if idx < 0 {
+				return nil, fmt.Errorf("%s at offset %d", sErrZ85Invalid, j)
+			}
+			v = v*85 + uint64(idx)
+		}
+		if v > 1<<32-1 {
+			return nil, fmt.Errorf("%s at offset %d", sErrZ85Invalid, i)
+		}
+		binary.BigEndian.PutUint32(res[i/5*4:], uint32(v))
+	}
+	return res, nil
+}
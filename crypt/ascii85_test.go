@@ -0,0 +1,61 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestAscii85(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "Empty", data: []byte{}, want: ""},
+		{name: "Hello", data: []byte("Hello, World!"), want: "87cURD_*#4DfTZ)+T"},
+		{name: "Zeros", data: []byte("\x00\x00\x00\x00abc"), want: "z@:E^"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Ascii85Encode(tt.data); got != tt.want {
+				t.Errorf("Ascii85Encode() = %v, want %v", got, tt.want)
+			}
+			if got, err := Ascii85Decode(tt.want); err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("Ascii85Decode() = %v, error = %v", got, err)
+			}
+		})
+	}
+	if got, err := Ascii85Decode(" <~87cURD_*#4\nDfTZ)+T~>\n"); err != nil || string(got) != "Hello, World!" {
+		t.Errorf("Ascii85Decode() with the delimiters = %q, error = %v", got, err)
+	}
+	if _, err := Ascii85Decode("87cU{"); err == nil || err.Error() != sErrAscii85Invalid+" at offset 4" {
+		t.Errorf("Ascii85Decode() error = %v, want %v at offset 4", err, sErrAscii85Invalid)
+	}
+}
+
+func TestZ85(t *testing.T) {
+	data, _ := hex.DecodeString("864FD26FB559F75B")
+	got, err := Z85Encode(data)
+	if err != nil || got != "HelloWorld" {
+		t.Errorf("Z85Encode() = %v, error = %v", got, err)
+	}
+	if decoded, err := Z85Decode("HelloWorld"); err != nil || !bytes.Equal(decoded, data) {
+		t.Errorf("Z85Decode() = %x, error = %v", decoded, err)
+	}
+
+	max := []byte{0xff, 0xff, 0xff, 0xff}
+	encoded, _ := Z85Encode(max)
+	if decoded, err := Z85Decode(encoded); err != nil || !bytes.Equal(decoded, max) {
+		t.Errorf("Z85Decode(%q) = %x, error = %v", encoded, decoded, err)
+	}
+
+	if _, err := Z85Encode([]byte("abc")); err == nil {
+		t.Errorf("Z85Encode() of 3 bytes should return an error")
+	}
+	for _, s := range []string{"Hell~", "Hello Worl", "Hell", "#####"} {
+		if _, err := Z85Decode(s); err == nil {
+			t.Errorf("Z85Decode(%q) should return an error", s)
+		}
+	}
+}
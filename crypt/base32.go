@@ -0,0 +1,161 @@
+package crypt
+
+import (
+	"encoding/base32"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Base32Encode base32 encode of RFC 4648, with the padding '='
+func Base32Encode(data []byte) string {
+	return base32.StdEncoding.EncodeToString(data)
+}
+
+// Base32Decode base32 decode of RFC 4648
+func Base32Decode(str string) ([]byte, error) {
+	data, err := base32.StdEncoding.DecodeString(str)
+	if err != nil {
+		var corrupt base32.CorruptInputError
+		if errors.As(err, &corrupt) {
+			return nil, fmt.Errorf("%s at offset %d", sErrBase32Invalid, int64(corrupt))
+		}
+		return nil, errors.New(sErrBase32Invalid)
+	}
+	return data, nil
+}
+
+// The Crockford's Base32 uses the digits and the uppercase letters except I、L、O and U, so it is easy to read and
+// type. The decoding is case-insensitive, I and L are decoded as 1, O is decoded as 0, and the hyphens are ignored,
+// such as: "0123-ABCD". The bytes are encoded as a bit stream without padding, every 5 bits is a symbol and the
+// last symbol is padded with zero bits. The check symbol is the value of the data as a big-endian integer mod 37, as
+// the spec defines, it is one of the 32 symbols or *~$=U, so an error of a single symbol or the swap of two adjacent
+// symbols is detected.
+
+// crockfordCheckAlphabet the check symbols of the Crockford's Base32
+const crockfordCheckAlphabet = crockfordAlphabet + "*~$=U"
+
+// Base32CrockfordEncode the Crockford's Base32 encode
+func Base32CrockfordEncode(data []byte) string {
+	res := make([]byte, 0, (len(data)*8+4)/5)
+	var acc uint
+	var bitCnt uint
+	for _, b := range data {
+		acc = acc<<8 | uint(b)
+		for bitCnt += 8; bitCnt >= 5; {
+			bitCnt -= 5
+			res = append(res, crockfordAlphabet[acc>>bitCnt&0x1F])
+		}
+	}
+	if bitCnt > 0 {
+		res = append(res, crockfordAlphabet[acc<<(5-bitCnt)&0x1F])
+	}
+	return string(res)
+}
+
+// Base32CrockfordDecode the Crockford's Base32 decode
+func Base32CrockfordDecode(str string) ([]byte, error) {
+	values, err := crockfordValues(str, false)
+	if err != nil {
+		return nil, err
+	}
+	return crockfordDecodeValues(values)
+}
+
+// Base32CrockfordEncodeCheck the Crockford's Base32 encode with a check symbol at the end
+func Base32CrockfordEncodeCheck(data []byte) string {
+	mod := crockfordCheckValue(data)
+	return Base32CrockfordEncode(data) + crockfordCheckAlphabet[mod:mod+1]
+}
+
+// Base32CrockfordDecodeCheck the Crockford's Base32 decode of the result of Base32CrockfordEncodeCheck, an error is
+// returned if the check symbol does not match
+func Base32CrockfordDecodeCheck(str string) ([]byte, error) {
+	values, err := crockfordValues(str, true)
+	if err != nil {
+		return nil, err
+	}
+	if len(values) == 0 {
+		return nil, errors.New(sErrBase32CheckMismatch)
+	}
+	check := values[len(values)-1]
+	data, err := crockfordDecodeValues(values[:len(values)-1])
+	if err != nil {
+		return nil, err
+	}
+	if crockfordCheckValue(data) != int(check) {
+		return nil, errors.New(sErrBase32CheckMismatch)
+	}
+	return data, nil
+}
+
+// crockfordCheckValue return the value of data as a big-endian integer mod 37
+func crockfordCheckValue(data []byte) int {
+	var mod int
+	for _, b := range data {
+		mod = (mod*256 + int(b)) % 37
+	}
+	return mod
+}
+
+// crockfordValues return the values of the symbols of str, the hyphens are skipped. If check is true, the last
+// symbol can be a check symbol
+func crockfordValues(str string, check bool) ([]byte, error) {
+	values := make([]byte, 0, len(str))
+	last := strings.LastIndexFunc(str, func(r rune) bool { return r != '-' })
+	for i := 0; i < len(str); i++ {
+		c := str[i]
+		var v byte
+		switch c {
+		case '-':
+			continue
+		case 'I', 'i', 'L', 'l':
+			v = 1
+		case 'O', 'o':
+			v = 0
+		default:
+			v = crockfordDecodeMap[c]
+			if v == 0xFF && check && i == last {
+				// The check symbols *~$=U
+				if idx := strings.IndexByte(crockfordCheckAlphabet[32:], upperASCII(c)); idx >= 0 {
+					v = byte(32 + idx)
+				}
+			}
+			if v == 0xFF {
+				return nil, fmt.Errorf("%s at offset %d", sErrBase32Invalid, i)
+			}
+		}
+		values = append(values, v)
+	}
+	return values, nil
+}
+
+// crockfordDecodeValues return the bytes of the 5-bit values, the padding bits must be zero
+func crockfordDecodeValues(values []byte) ([]byte, error) {
+	// The padding bits are less than 5
+	if len(values)*5%8 >= 5 {
+		return nil, errors.New(sErrBase32Invalid)
+	}
+	res := make([]byte, 0, len(values)*5/8)
+	var acc uint
+	var bitCnt uint
+	for _, v := range values {
+		acc = acc<<5 | uint(v)
+		if bitCnt += 5; bitCnt >= 8 {
+			bitCnt -= 8
+			res = append(res, byte(acc>>bitCnt))
+		}
+	}
+	if acc&(1<<bitCnt-1) != 0 {
+		return nil, errors.New(sErrBase32Invalid)
+	}
+	return res, nil
+}
+
+// upperASCII return the uppercase of the ASCII letter c, other characters are returned as is
+func upperASCII(c byte) byte {
+	if c >= 'a' && c <= 'z' {
+		return c - 'a' + 'A'
+	}
+	return c
+}
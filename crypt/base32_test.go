@@ -0,0 +1,98 @@
+package crypt
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestBase32(t *testing.T) {
+	data := []byte("foobar")
+	if got := Base32Encode(data); got != "MZXW6YTBOI======" {
+		t.Errorf("Base32Encode() = %v, want %v", got, "MZXW6YTBOI======")
+	}
+	if got, err := Base32Decode("MZXW6YTBOI======"); err != nil || !bytes.Equal(got, data) {
+		t.Errorf("Base32Decode() = %v, error = %v", got, err)
+	}
+	if _, err := Base32Decode("MZXW6YTBOI"); err == nil {
+		t.Errorf("Base32Decode() without the padding should return an error")
+	}
+	if _, err := Base32Decode("MZXW6Y1BOI======"); err == nil || err.Error() != sErrBase32Invalid+" at offset 6" {
+		t.Errorf("Base32Decode() error = %v, want %v at offset 6", err, sErrBase32Invalid)
+	}
+}
+
+func TestBase32Crockford(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		want      string
+		wantCheck string
+	}{
+		{name: "Empty", data: []byte{}, want: "", wantCheck: "0"},
+		{name: "OneByte", data: []byte("f"), want: "CR", wantCheck: "CRW"},
+		{name: "Foobar", data: []byte("foobar"), want: "CSQPYRK1E8", wantCheck: "CSQPYRK1E86"},
+		{name: "LeadingZero", data: []byte{0x00, 0xff}, want: "03ZG", wantCheck: "03ZG~"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Base32CrockfordEncode(tt.data); got != tt.want {
+				t.Errorf("Base32CrockfordEncode() = %v, want %v", got, tt.want)
+			}
+			if got, err := Base32CrockfordDecode(tt.want); err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("Base32CrockfordDecode() = %v, error = %v", got, err)
+			}
+			if tt.wantCheck == "" {
+				return
+			}
+			if got := Base32CrockfordEncodeCheck(tt.data); got != tt.wantCheck {
+				t.Errorf("Base32CrockfordEncodeCheck() = %v, want %v", got, tt.wantCheck)
+			}
+			if got, err := Base32CrockfordDecodeCheck(tt.wantCheck); err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("Base32CrockfordDecodeCheck() = %v, error = %v", got, err)
+			}
+		})
+	}
+
+	// Case-insensitive, I L O are the aliases, the hyphens are ignored
+	if got, err := Base32CrockfordDecode("csqp-yrkl-e8"); err != nil || string(got) != "foobar" {
+		t.Errorf("Base32CrockfordDecode() = %q, error = %v", got, err)
+	}
+	if got, err := Base32CrockfordDecode("o3zg"); err != nil || !bytes.Equal(got, []byte{0x00, 0xff}) {
+		t.Errorf("Base32CrockfordDecode() = %v, error = %v", got, err)
+	}
+
+	invalid := []string{"CSQPYRK1EU", "C", "CS", "CT", "CSQ*"}
+	for _, s := range invalid {
+		if _, err := Base32CrockfordDecode(s); err == nil {
+			t.Errorf("Base32CrockfordDecode(%q) should return an error", s)
+		}
+	}
+
+	// All the check symbols round trip
+	seen := make(map[byte]bool)
+	for i := 0; len(seen) < 37 && i < 100000; i++ {
+		data := []byte{byte(i >> 8), byte(i)}
+		encoded := Base32CrockfordEncodeCheck(data)
+		seen[encoded[len(encoded)-1]] = true
+		if got, err := Base32CrockfordDecodeCheck(encoded); err != nil || !bytes.Equal(got, data) {
+			t.Fatalf("Base32CrockfordDecodeCheck(%q) = %v, error = %v", encoded, got, err)
+		}
+	}
+	if len(seen) != 37 {
+		t.Errorf("%d check symbols are used, want 37", len(seen))
+	}
+	// The check symbol is the value of the data mod 37, 1234 mod 37 = 13, the same as the integer encoders
+	if got := Base32CrockfordEncodeCheck([]byte{0x04, 0xd2}); got[len(got)-1] != 'D' {
+		t.Errorf("Base32CrockfordEncodeCheck(1234) = %v, want the check symbol D", got)
+	}
+	if got, err := Base32CrockfordDecodeCheck("crw"); err != nil || string(got) != "f" {
+		t.Errorf("Base32CrockfordDecodeCheck() = %q, error = %v", got, err)
+	}
+
+	mismatch := []string{"", "CRX", "RCW", "CSQPYRK1E96", "CSQPYRKE186"}
+	for _, s := range mismatch {
+		if _, err := Base32CrockfordDecodeCheck(s); err == nil {
+			t.Errorf("Base32CrockfordDecodeCheck(%q) should return an error", s)
+		}
+	}
+}
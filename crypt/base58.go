@@ -0,0 +1,82 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+)
+
+// The Base58 uses the Bitcoin alphabet, it drops 0、O、I and l which are ambiguous, and + and / which are not safe in
+// the URLs. The bytes are encoded as a big-endian number, every leading zero byte is encoded as a '1'.
+// The Base58Check appends the first 4 bytes of the double SHA-256 of the version and the payload as a checksum,
+// such as the Bitcoin addresses.
+
+const (
+	base58Alphabet      = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+	base58ChecksumBytes = 4
+)
+
+// Base58Encode the Base58 encode of the Bitcoin alphabet
+func Base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	digits := convertBase(data, 256, 58)
+	res := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		res[i] = base58Alphabet[0]
+	}
+	for i, d := range digits {
+		res[zeros+i] = base58Alphabet[d]
+	}
+	return string(res)
+}
+
+// Base58Decode the Base58 decode of the Bitcoin alphabet
+func Base58Decode(str string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(str) && str[zeros] == base58Alphabet[0] {
+		zeros++
+	}
+	digits := make([]byte, len(str)-zeros)
+	for i := zeros; i < len(str); i++ {
+		idx := baseIndex(base58Alphabet, str[i])
+		if idx < 0 {
+			return nil, fmt.Errorf("%s at offset %d", sErrBase58Invalid, i)
+		}
+		digits[i-zeros] = byte(idx)
+	}
+	return append(make([]byte, zeros), convertBase(digits, 58, 256)...), nil
+}
+
+// Base58CheckEncode the Base58Check encode of the version and the payload
+func Base58CheckEncode(version byte, payload []byte) string {
+	data := make([]byte, 0, 1+len(payload)+base58ChecksumBytes)
+	data = append(data, version)
+	data = append(data, payload...)
+	data = append(data, base58Checksum(data)...)
+	return Base58Encode(data)
+}
+
+// Base58CheckDecode the Base58Check decode, return the version and the payload, an error is returned if the checksum
+// does not match
+func Base58CheckDecode(str string) (byte, []byte, error) {
+	data, err := Base58Decode(str)
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(data) < 1+base58ChecksumBytes {
+		return 0, nil, errors.New(sErrBase58Invalid)
+	}
+	n := len(data) - base58ChecksumBytes
+	if !bytes.Equal(base58Checksum(data[:n]), data[n:]) {
+		return 0, nil, errors.New(sErrBase58Checksum)
+	}
+	return data[0], data[1:n], nil
+}
+
+// base58Checksum return the first 4 bytes of the double SHA-256 of data
+func base58Checksum(data []byte) []byte {
+	return HashBytes(HashBytes(data, HtSha256), HtSha256)[:base58ChecksumBytes]
+}
@@ -0,0 +1,60 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestBase58(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "Empty", data: []byte{}, want: ""},
+		{name: "Hello", data: []byte("Hello World!"), want: "2NEpo7TZRRrLZSi2U"},
+		{name: "LeadingZeros", data: []byte{0x00, 0x00, 0x28, 0x7f, 0xb4, 0xcd}, want: "11233QC4"},
+		{name: "Zeros", data: []byte{0x00, 0x00}, want: "11"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Base58Encode(tt.data); got != tt.want {
+				t.Errorf("Base58Encode() = %v, want %v", got, tt.want)
+			}
+			if got, err := Base58Decode(tt.want); err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("Base58Decode() = %v, error = %v", got, err)
+			}
+		})
+	}
+
+	for _, s := range []string{"0abc", "Olive", "Il", "abc+"} {
+		if _, err := Base58Decode(s); err == nil {
+			t.Errorf("Base58Decode(%q) should return an error", s)
+		}
+	}
+}
+
+func TestBase58Check(t *testing.T) {
+	payload, _ := hex.DecodeString("010966776006953D5567439E5E39F86A0D273BEE")
+	const address = "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM"
+	if got := Base58CheckEncode(0, payload); got != address {
+		t.Errorf("Base58CheckEncode() = %v, want %v", got, address)
+	}
+	version, got, err := Base58CheckDecode(address)
+	if err != nil || version != 0 || !bytes.Equal(got, payload) {
+		t.Errorf("Base58CheckDecode() = %v, %v, error = %v", version, got, err)
+	}
+
+	version, got, err = Base58CheckDecode(Base58CheckEncode(0x80, nil))
+	if err != nil || version != 0x80 || len(got) != 0 {
+		t.Errorf("Base58CheckDecode() = %v, %v, error = %v", version, got, err)
+	}
+
+	invalid := []string{"", "1", "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjvN", "26UwLL9Risc3QfPqBUvKofHmBQ7wMtjvM", "16UwLL9Risc3QfPqBUvKofHmBQ7wMtjv0"}
+	for _, s := range invalid {
+		if _, _, err := Base58CheckDecode(s); err == nil {
+			t.Errorf("Base58CheckDecode(%q) should return an error", s)
+		}
+	}
+}
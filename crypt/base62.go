@@ -0,0 +1,79 @@
+package crypt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// The Base62 uses the alphabet 0-9A-Za-z, the same as KSUID, the result has only the letters and the digits, so it
+// is safe in the URLs and the file names. The bytes are encoded as a big-endian number, every leading zero byte is
+// encoded as a '0'. Base62EncodeUint64 encodes a number, such as the id of a short link.
+
+// Base62Encode the Base62 encode
+func Base62Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+	digits := convertBase(data, 256, 62)
+	res := make([]byte, zeros+len(digits))
+	for i := 0; i < zeros; i++ {
+		res[i] = base62Alphabet[0]
+	}
+	for i, d := range digits {
+		res[zeros+i] = base62Alphabet[d]
+	}
+	return string(res)
+}
+
+// Base62Decode the Base62 decode
+func Base62Decode(str string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(str) && str[zeros] == base62Alphabet[0] {
+		zeros++
+	}
+	digits := make([]byte, len(str)-zeros)
+	for i := zeros; i < len(str); i++ {
+		idx := baseIndex(base62Alphabet, str[i])
+		if idx < 0 {
+			return nil, fmt.Errorf("%s at offset %d", sErrBase62Invalid, i)
+		}
+		digits[i-zeros] = byte(idx)
+	}
+	return append(make([]byte, zeros), convertBase(digits, 62, 256)...), nil
+}
+
+// Base62EncodeUint64 the Base62 encode of n, 0 is encoded as "0"
+func Base62EncodeUint64(n uint64) string {
+	if n == 0 {
+		return base62Alphabet[:1]
+	}
+	var buf [11]byte
+	i := len(buf)
+	for n > 0 {
+		i--
+		buf[i] = base62Alphabet[n%62]
+		n /= 62
+	}
+	return string(buf[i:])
+}
+
+// Base62DecodeUint64 the Base62 decode of the result of Base62EncodeUint64, an error is returned if str is empty、
+// invalid or overflows uint64
+func Base62DecodeUint64(str string) (uint64, error) {
+	if str == "" {
+		return 0, errors.New(sErrBase62Invalid)
+	}
+	var n uint64
+	for i := 0; i < len(str); i++ {
+		idx := baseIndex(base62Alphabet, str[i])
+		if idx < 0 {
+			return 0, fmt.Errorf("%s at offset %d", sErrBase62Invalid, i)
+		}
+		if n > (1<<64-1-uint64(idx))/62 {
+			return 0, errors.New(sErrBase62Invalid)
+		}
+		n = n*62 + uint64(idx)
+	}
+	return n, nil
+}
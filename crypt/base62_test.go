@@ -0,0 +1,58 @@
+package crypt
+
+import (
+	"bytes"
+	"math"
+	"testing"
+)
+
+func TestBase62(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		want string
+	}{
+		{name: "Empty", data: []byte{}, want: ""},
+		{name: "Hello", data: []byte("Hello World!"), want: "T8dgcjRGkZ3aysdN"},
+		{name: "LeadingZeros", data: []byte{0x00, 0x00, 0x3d}, want: "00z"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Base62Encode(tt.data); got != tt.want {
+				t.Errorf("Base62Encode() = %v, want %v", got, tt.want)
+			}
+			if got, err := Base62Decode(tt.want); err != nil || !bytes.Equal(got, tt.data) {
+				t.Errorf("Base62Decode() = %v, error = %v", got, err)
+			}
+		})
+	}
+	if _, err := Base62Decode("abc-"); err == nil {
+		t.Errorf("Base62Decode() should return an error")
+	}
+}
+
+func TestBase62Uint64(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want string
+	}{
+		{0, "0"},
+		{61, "z"},
+		{62, "10"},
+		{123456789, "8M0kX"},
+		{math.MaxUint64, "LygHa16AHYF"},
+	}
+	for _, tt := range tests {
+		if got := Base62EncodeUint64(tt.n); got != tt.want {
+			t.Errorf("Base62EncodeUint64(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+		if got, err := Base62DecodeUint64(tt.want); err != nil || got != tt.n {
+			t.Errorf("Base62DecodeUint64(%q) = %v, error = %v", tt.want, got, err)
+		}
+	}
+	for _, s := range []string{"", "LygHa16AHYG", "zzzzzzzzzzzz", "a_b"} {
+		if _, err := Base62DecodeUint64(s); err == nil {
+			t.Errorf("Base62DecodeUint64(%q) should return an error", s)
+		}
+	}
+}
@@ -2,8 +2,21 @@ package crypt
 
 import (
 	"encoding/base64"
+	"errors"
+	"io"
+	"strings"
 )
 
+// The Raw* functions encode and decode without the padding '=', such as the JWT. Base64DecodeLenient decodes the
+// data of unknown variant, it accepts both the standard and the url-safe alphabets, with or without the padding, and
+// ignores the white spaces. Base64MimeEncode breaks the result into the lines of 76 characters by CRLF(RFC 2045).
+// The large files are encoded and decoded on the fly by NewBase64Encoder and NewBase64Decoder, such as:
+//  enc, _ := NewBase64Encoder(dst, Base64ModeMime)
+//  io.Copy(enc, src)
+//  enc.Close()
+
+const base64MimeLineLen = 76
+
 // Base64Encode base64 encode
 func Base64Encode(data []byte) string {
 	return base64.StdEncoding.EncodeToString(data)
@@ -24,3 +37,157 @@ func Base64UrlEncode(data []byte) string {
 func Base64UrlDecode(str string) ([]byte, error) {
 	return base64.URLEncoding.DecodeString(str)
 }
+
+// Base64RawEncode base64 encode without the padding
+func Base64RawEncode(data []byte) string {
+	return base64.RawStdEncoding.EncodeToString(data)
+}
+
+// Base64RawDecode base64 decode without the padding
+func Base64RawDecode(str string) ([]byte, error) {
+	return base64.RawStdEncoding.DecodeString(str)
+}
+
+// Base64RawUrlEncode url-safe base64 encode without the padding
+func Base64RawUrlEncode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// Base64RawUrlDecode url-safe base64 decode without the padding
+func Base64RawUrlDecode(str string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(str)
+}
+
+// Base64DecodeLenient base64 decode of the standard or the url-safe alphabet, the padding is optional and the white
+// spaces are ignored
+func Base64DecodeLenient(str string) ([]byte, error) {
+	str = strings.Map(func(r rune) rune {
+		switch r {
+		case ' ', '\t', '\r', '\n':
+			return -1
+		case '-':
+			return '+'
+		case '_':
+			return '/'
+		}
+		return r
+	}, str)
+	return base64.RawStdEncoding.DecodeString(strings.TrimRight(str, "="))
+}
+
+// Base64MimeEncode base64 encode, the result is broken into the lines of 76 characters by CRLF, there is no line
+// break at the end
+func Base64MimeEncode(data []byte) string {
+	var sb strings.Builder
+	enc := base64.NewEncoder(base64.StdEncoding, &base64LineWriter{w: &sb})
+	_, _ = enc.Write(data)
+	_ = enc.Close()
+	return sb.String()
+}
+
+// NewBase64Encoder return a writer encoding the data written to w in mode, Close must be called to flush the last
+// partial block, it does not close w
+func NewBase64Encoder(w io.Writer, mode Base64Mode) (io.WriteCloser, error) {
+	if mode == Base64ModeMime {
+		return base64.NewEncoder(base64.StdEncoding, &base64LineWriter{w: w}), nil
+	}
+	enc, err := base64Encoding(mode)
+	if err != nil {
+		return nil, err
+	}
+	return base64.NewEncoder(enc, w), nil
+}
+
+// NewBase64Decoder return a reader decoding the data read from r in mode, the line breaks are ignored
+func NewBase64Decoder(r io.Reader, mode Base64Mode) (io.Reader, error) {
+	if mode == Base64ModeMime {
+		mode = Base64ModeStd
+	}
+	enc, err := base64Encoding(mode)
+	if err != nil {
+		return nil, err
+	}
+	return base64.NewDecoder(enc, r), nil
+}
+
+// NewBase64LenientDecoder return a reader decoding the data read from r like Base64DecodeLenient, all the padding
+// characters are dropped, so the concatenated padded data is not supported
+func NewBase64LenientDecoder(r io.Reader) io.Reader {
+	return base64.NewDecoder(base64.RawStdEncoding, &base64LenientReader{r: r})
+}
+
+// base64Encoding return the encoding of mode
+func base64Encoding(mode Base64Mode) (*base64.Encoding, error) {
+	switch mode {
+	case Base64ModeStd:
+		return base64.StdEncoding, nil
+	case Base64ModeUrl:
+		return base64.URLEncoding, nil
+	case Base64ModeRawStd:
+		return base64.RawStdEncoding, nil
+	case Base64ModeRawUrl:
+		return base64.RawURLEncoding, nil
+	default:
+		return nil, errors.New(sErrBase64ModeInvalid)
+	}
+}
+
+// base64LineWriter insert a CRLF every 76 characters written to w
+type base64LineWriter struct {
+	w   io.Writer
+	col int
+}
+
+// Write write p to w, a CRLF is written before the next character of a full line
+func (l *base64LineWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		if l.col == base64MimeLineLen {
+			if _, err := io.WriteString(l.w, "\r\n"); err != nil {
+				return written, err
+			}
+			l.col = 0
+		}
+		n := base64MimeLineLen - l.col
+		if n > len(p) {
+			n = len(p)
+		}
+		n, err := l.w.Write(p[:n])
+		written += n
+		l.col += n
+		if err != nil {
+			return written, err
+		}
+		p = p[n:]
+	}
+	return written, nil
+}
+
+// base64LenientReader drop the white spaces and the padding read from r, and map the url-safe alphabet to the
+// standard alphabet
+type base64LenientReader struct {
+	r io.Reader
+}
+
+// Read read the filtered data into p
+func (l *base64LenientReader) Read(p []byte) (int, error) {
+	for {
+		n, err := l.r.Read(p)
+		k := 0
+		for _, c := range p[:n] {
+			switch c {
+			case ' ', '\t', '\r', '\n', '=':
+				continue
+			case '-':
+				c = '+'
+			case '_':
+				c = '/'
+			}
+			p[k] = c
+			k++
+		}
+		if k > 0 || err != nil {
+			return k, err
+		}
+	}
+}
@@ -1,7 +1,10 @@
 package crypt
 
 import (
+	"bytes"
+	"io/ioutil"
 	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -114,3 +117,126 @@ func TestBase64UrlEncode(t *testing.T) {
 		})
 	}
 }
+
+func TestBase64Raw(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0xbf, 0x01}
+	tests := []struct {
+		name   string
+		encode func([]byte) string
+		decode func(string) ([]byte, error)
+		want   string
+	}{
+		{"RawStd", Base64RawEncode, Base64RawDecode, "+/+/AQ"},
+		{"RawUrl", Base64RawUrlEncode, Base64RawUrlDecode, "-_-_AQ"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.encode(data); got != tt.want {
+				t.Errorf("encode() = %v, want %v", got, tt.want)
+			}
+			if got, err := tt.decode(tt.want); err != nil || !reflect.DeepEqual(got, data) {
+				t.Errorf("decode() = %v, error = %v", got, err)
+			}
+			if _, err := tt.decode(tt.want + "=="); err == nil {
+				t.Errorf("decode() with the padding should return an error")
+			}
+		})
+	}
+}
+
+func TestBase64DecodeLenient(t *testing.T) {
+	data := []byte{0xfb, 0xff, 0xbf, 0x01}
+	for _, s := range []string{"+/+/AQ==", "+/+/AQ", "-_-_AQ==", "-_-_AQ", "+/-_AQ=", " +/+/\r\nAQ==\n"} {
+		if got, err := Base64DecodeLenient(s); err != nil || !reflect.DeepEqual(got, data) {
+			t.Errorf("Base64DecodeLenient(%q) = %v, error = %v", s, got, err)
+		}
+	}
+	for _, s := range []string{"+/+/A", "+/+/AQ*=", "+/=+/AQ"} {
+		if _, err := Base64DecodeLenient(s); err == nil {
+			t.Errorf("Base64DecodeLenient(%q) should return an error", s)
+		}
+	}
+}
+
+func TestBase64MimeEncode(t *testing.T) {
+	data := bytes.Repeat([]byte("0123456789"), 12)
+	got := Base64MimeEncode(data)
+	lines := strings.Split(got, "\r\n")
+	if len(lines) != 3 || len(lines[0]) != 76 || len(lines[1]) != 76 || len(lines[2]) != 8 {
+		t.Fatalf("Base64MimeEncode() = %q", got)
+	}
+	if strings.Join(lines, "") != Base64Encode(data) {
+		t.Errorf("Base64MimeEncode() = %q, want the lines of %q", got, Base64Encode(data))
+	}
+	if got := Base64MimeEncode(data[:57]); strings.Contains(got, "\r\n") || len(got) != 76 {
+		t.Errorf("Base64MimeEncode() of a full line = %q", got)
+	}
+	if decoded, err := Base64Decode(got); err != nil || !reflect.DeepEqual(decoded, data) {
+		t.Errorf("Base64Decode() = %v, error = %v", decoded, err)
+	}
+}
+
+func TestBase64Stream(t *testing.T) {
+	data := make([]byte, 10000)
+	for i := range data {
+		data[i] = byte(i * 7)
+	}
+	tests := []struct {
+		name string
+		mode Base64Mode
+		want string
+	}{
+		{"Std", Base64ModeStd, Base64Encode(data)},
+		{"Url", Base64ModeUrl, Base64UrlEncode(data)},
+		{"RawStd", Base64ModeRawStd, Base64RawEncode(data)},
+		{"RawUrl", Base64ModeRawUrl, Base64RawUrlEncode(data)},
+		{"Mime", Base64ModeMime, Base64MimeEncode(data)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			enc, err := NewBase64Encoder(&buf, tt.mode)
+			if err != nil {
+				t.Fatalf("NewBase64Encoder() error = %v", err)
+			}
+			// Write in the chunks of different sizes
+			for i := 0; i < len(data); i += 333 {
+				end := i + 333
+				if end > len(data) {
+					end = len(data)
+				}
+				if _, err := enc.Write(data[i:end]); err != nil {
+					t.Fatalf("Write() error = %v", err)
+				}
+			}
+			if err := enc.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+			if buf.String() != tt.want {
+				t.Fatalf("the encoded stream is different from the encoded data")
+			}
+
+			dec, err := NewBase64Decoder(strings.NewReader(tt.want), tt.mode)
+			if err != nil {
+				t.Fatalf("NewBase64Decoder() error = %v", err)
+			}
+			if got, err := ioutil.ReadAll(dec); err != nil || !bytes.Equal(got, data) {
+				t.Errorf("the decoded stream is different from the data, error = %v", err)
+			}
+			if got, err := ioutil.ReadAll(NewBase64LenientDecoder(strings.NewReader(tt.want))); err != nil ||
+				!bytes.Equal(got, data) {
+				t.Errorf("the lenient decoded stream is different from the data, error = %v", err)
+			}
+		})
+	}
+
+	if _, err := NewBase64Encoder(ioutil.Discard, Base64Mode(100)); err == nil {
+		t.Errorf("NewBase64Encoder() with an invalid mode should return an error")
+	}
+	if _, err := NewBase64Decoder(strings.NewReader(""), Base64Mode(100)); err == nil {
+		t.Errorf("NewBase64Decoder() with an invalid mode should return an error")
+	}
+	if _, err := ioutil.ReadAll(NewBase64LenientDecoder(strings.NewReader("+/+/A*=="))); err == nil {
+		t.Errorf("the lenient decoder of invalid data should return an error")
+	}
+}
@@ -0,0 +1,428 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// A Bloom filter is a space-efficient probabilistic set: Test may return a false positive but never a false negative.
+// For n expected items and the false positive rate p, the optimal number of bits is m = -n*ln(p)/(ln2)^2 and the
+// optimal number of hash functions is k = m/n*ln2.
+// The k bit positions are generated by double hashing(Kirsch and Mitzenmacher): g_i(x) = h1(x) + i*h2(x) mod m,
+// h1 and h2 come from HashUInt64 with HtFnvA64 and HtCrc64ECMA.
+// A counting Bloom filter replaces each bit with a counter, so that the items can be removed.
+//
+// The filters are not safe for concurrent use. MarshalBinary returns a stable binary encoding which can be persisted
+// with the functions of the file package, such as: file.WriteFile4BufIO(path, string(data)), or shipped between
+// nodes and restored by UnmarshalBinary. The encoding is:
+//  magic(2 bytes: "BF" or "CB") | version(1 byte) | m(8 bytes) | k(8 bytes) | bits or counters
+// all integers are in big-endian byte order. m is at most 2^40 and k is at most 64, the headers out of range are
+// rejected, so a crafted header can not cause a huge allocation or an endless loop.
+
+const (
+	bloomVersion     = 1
+	bloomHeaderLen   = 19
+	bloomMaxCount    = math.MaxUint8
+	bloomDefaultRate = 0.01
+	bloomMaxM        = 1 << 40
+	bloomMaxK        = 64
+)
+
+var (
+	bloomMagic         = [2]byte{'B', 'F'}
+	countingBloomMagic = [2]byte{'C', 'B'}
+)
+
+// EstimateBloomParams return the optimal number of bits m and hash functions k for n expected items and the false
+// positive rate fp. If n is 0, the default value of 1 is used; if fp is not in (0,1), the default value of 0.01 is used.
+// m is at most 2^40 and k is at most 64
+func EstimateBloomParams(n uint64, fp float64) (m, k uint64) {
+	if n == 0 {
+		n = 1
+	}
+	if !(fp > 0 && fp < 1) {
+		fp = bloomDefaultRate
+	}
+	m = uint64(math.Ceil(-float64(n) * math.Log(fp) / (math.Ln2 * math.Ln2)))
+	k = uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	return clampBloomParams(m, k)
+}
+
+// clampBloomParams clamp m to [1, 2^40] and k to [1, 64]
+func clampBloomParams(m, k uint64) (uint64, uint64) {
+	if m == 0 {
+		m = 1
+	} else if m > bloomMaxM {
+		m = bloomMaxM
+	}
+	if k == 0 {
+		k = 1
+	} else if k > bloomMaxK {
+		k = bloomMaxK
+	}
+	return m, k
+}
+
+// bloomLocations return the k bit positions of data in [0,m)
+func bloomLocations(data []byte, m, k uint64, locs []uint64) []uint64 {
+	h1 := mix64(HashUInt64(data, HtFnvA64))
+	h2 := mix64(HashUInt64(data, HtCrc64ECMA))
+	if h2%m == 0 {
+		// Otherwise all the k positions are the same
+		h2 = 1
+	}
+	locs = locs[:0]
+	for i := uint64(0); i < k; i++ {
+		locs = append(locs, (h1+i*h2)%m)
+	}
+	return locs
+}
+
+// BloomFilter a Bloom filter
+type BloomFilter struct {
+	m    uint64
+	k    uint64
+	bits []uint64
+}
+
+// NewBloomFilter return a Bloom filter sized for n expected items and the false positive rate fp,
+// see EstimateBloomParams
+func NewBloomFilter(n uint64, fp float64) *BloomFilter {
+	return NewBloomFilterWithSize(EstimateBloomParams(n, fp))
+}
+
+// NewBloomFilterWithSize return a Bloom filter with m bits and k hash functions, m is in [1, 2^40] and k is in [1, 64]
+func NewBloomFilterWithSize(m, k uint64) *BloomFilter {
+	m, k = clampBloomParams(m, k)
+	return &BloomFilter{m: m, k: k, bits: make([]uint64, (m+63)/64)}
+}
+
+// Cap return the number of bits m
+func (bf *BloomFilter) Cap() uint64 {
+	return bf.m
+}
+
+// K return the number of hash functions k
+func (bf *BloomFilter) K() uint64 {
+	return bf.k
+}
+
+// Add add data to the filter
+func (bf *BloomFilter) Add(data []byte) {
+	var buf [16]uint64
+	for _, loc := range bloomLocations(data, bf.m, bf.k, buf[:0]) {
+		bf.bits[loc>>6] |= 1 << (loc & 63)
+	}
+}
+
+// AddString add the string to the filter
+func (bf *BloomFilter) AddString(s string) {
+	bf.Add([]byte(s))
+}
+
+// Test return false if data is definitely not in the filter, true if data is probably in the filter
+func (bf *BloomFilter) Test(data []byte) bool {
+	var buf [16]uint64
+	for _, loc := range bloomLocations(data, bf.m, bf.k, buf[:0]) {
+		if bf.bits[loc>>6]&(1<<(loc&63)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString like Test, but test a string
+func (bf *BloomFilter) TestString(s string) bool {
+	return bf.Test([]byte(s))
+}
+
+// TestAndAdd add data to the filter and return whether data was probably in the filter before adding
+func (bf *BloomFilter) TestAndAdd(data []byte) bool {
+	var buf [16]uint64
+	present := true
+	for _, loc := range bloomLocations(data, bf.m, bf.k, buf[:0]) {
+		if bf.bits[loc>>6]&(1<<(loc&63)) == 0 {
+			present = false
+			bf.bits[loc>>6] |= 1 << (loc & 63)
+		}
+	}
+	return present
+}
+
+// EstimateCount return the estimated number of items in the filter: -m/k * ln(1 - X/m), X is the number of set bits
+func (bf *BloomFilter) EstimateCount() uint64 {
+	x := 0
+	for _, w := range bf.bits {
+		x += bits.OnesCount64(w)
+	}
+	if uint64(x) >= bf.m {
+		return math.MaxUint64
+	}
+	return uint64(math.Round(-float64(bf.m) / float64(bf.k) * math.Log(1-float64(x)/float64(bf.m))))
+}
+
+// Clear remove all items from the filter
+func (bf *BloomFilter) Clear() {
+	for i := range bf.bits {
+		bf.bits[i] = 0
+	}
+}
+
+// Union merge other into bf, after that bf contains the items of both filters.
+// The filters must have the same m and k
+func (bf *BloomFilter) Union(other *BloomFilter) error {
+	if bf.m != other.m || bf.k != other.k {
+		return errors.New(sErrBloomNotCompatible)
+	}
+	for i := range bf.bits {
+		bf.bits[i] |= other.bits[i]
+	}
+	return nil
+}
+
+// Intersect intersect bf with other, after that bf probably contains the items which are in both filters.
+// The filters must have the same m and k
+func (bf *BloomFilter) Intersect(other *BloomFilter) error {
+	if bf.m != other.m || bf.k != other.k {
+		return errors.New(sErrBloomNotCompatible)
+	}
+	for i := range bf.bits {
+		bf.bits[i] &= other.bits[i]
+	}
+	return nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler
+func (bf *BloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, bloomHeaderLen+len(bf.bits)*8)
+	putBloomHeader(data, bloomMagic, bf.m, bf.k)
+	for i, w := range bf.bits {
+		binary.BigEndian.PutUint64(data[bloomHeaderLen+i*8:], w)
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler
+func (bf *BloomFilter) UnmarshalBinary(data []byte) error {
+	m, k, err := getBloomHeader(data, bloomMagic)
+	if err != nil {
+		return err
+	}
+	data = data[bloomHeaderLen:]
+	if uint64(len(data)) != (m+63)/64*8 {
+		return errors.New(sErrDataInvalid)
+	}
+
+	words := make([]uint64, (m+63)/64)
+	for i := range words {
+		words[i] = binary.BigEndian.Uint64(data[i*8:])
+	}
+	bf.m, bf.k, bf.bits = m, k, words
+	return nil
+}
+
+// WriteTo implement io.WriterTo, write the binary encoding of the filter to w
+func (bf *BloomFilter) WriteTo(w io.Writer) (int64, error) {
+	data, _ := bf.MarshalBinary()
+	n, err := w.Write(data)
+	return int64(n), err
+}
+
+// ReadFrom implement io.ReaderFrom, read the binary encoding of the filter from r
+func (bf *BloomFilter) ReadFrom(r io.Reader) (int64, error) {
+	header := make([]byte, bloomHeaderLen)
+	n, err := io.ReadFull(r, header)
+	if err != nil {
+		return int64(n), err
+	}
+	m, _, err := getBloomHeader(header, bloomMagic)
+	if err != nil {
+		return int64(n), err
+	}
+
+	// The buffer grows with the data actually read, not with the size in the header
+	buf := bytes.NewBuffer(header)
+	nn, err := io.CopyN(buf, r, int64((m+63)/64*8))
+	if err == io.EOF {
+		return int64(n) + nn, errors.New(sErrDataInvalid)
+	} else if err != nil {
+		return int64(n) + nn, err
+	}
+	return int64(n) + nn, bf.UnmarshalBinary(buf.Bytes())
+}
+
+// CountingBloomFilter a counting Bloom filter with 8-bit counters, the counters saturate at 255 and the saturated
+// counters are never decremented, so that Remove never introduces false negatives
+type CountingBloomFilter struct {
+	m        uint64
+	k        uint64
+	counters []uint8
+}
+
+// NewCountingBloomFilter return a counting Bloom filter sized for n expected items and the false positive rate fp,
+// see EstimateBloomParams
+func NewCountingBloomFilter(n uint64, fp float64) *CountingBloomFilter {
+	return NewCountingBloomFilterWithSize(EstimateBloomParams(n, fp))
+}
+
+// NewCountingBloomFilterWithSize return a counting Bloom filter with m counters and k hash functions,
+// m is in [1, 2^40] and k is in [1, 64]
+func NewCountingBloomFilterWithSize(m, k uint64) *CountingBloomFilter {
+	m, k = clampBloomParams(m, k)
+	return &CountingBloomFilter{m: m, k: k, counters: make([]uint8, m)}
+}
+
+// Cap return the number of counters m
+func (cbf *CountingBloomFilter) Cap() uint64 {
+	return cbf.m
+}
+
+// K return the number of hash functions k
+func (cbf *CountingBloomFilter) K() uint64 {
+	return cbf.k
+}
+
+// Add add data to the filter
+func (cbf *CountingBloomFilter) Add(data []byte) {
+	var buf [16]uint64
+	for _, loc := range bloomLocations(data, cbf.m, cbf.k, buf[:0]) {
+		if cbf.counters[loc] < bloomMaxCount {
+			cbf.counters[loc]++
+		}
+	}
+}
+
+// AddString add the string to the filter
+func (cbf *CountingBloomFilter) AddString(s string) {
+	cbf.Add([]byte(s))
+}
+
+// Remove remove data from the filter, if data is definitely not in the filter, return false and do nothing.
+// Only remove the data which has been added, otherwise other items may be removed too
+func (cbf *CountingBloomFilter) Remove(data []byte) bool {
+	var buf [16]uint64
+	locs := bloomLocations(data, cbf.m, cbf.k, buf[:0])
+	for _, loc := range locs {
+		if cbf.counters[loc] == 0 {
+			return false
+		}
+	}
+	for _, loc := range locs {
+		if cbf.counters[loc] < bloomMaxCount {
+			cbf.counters[loc]--
+		}
+	}
+	return true
+}
+
+// RemoveString remove the string from the filter, see Remove
+func (cbf *CountingBloomFilter) RemoveString(s string) bool {
+	return cbf.Remove([]byte(s))
+}
+
+// Test return false if data is definitely not in the filter, true if data is probably in the filter
+func (cbf *CountingBloomFilter) Test(data []byte) bool {
+	var buf [16]uint64
+	for _, loc := range bloomLocations(data, cbf.m, cbf.k, buf[:0]) {
+		if cbf.counters[loc] == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// TestString like Test, but test a string
+func (cbf *CountingBloomFilter) TestString(s string) bool {
+	return cbf.Test([]byte(s))
+}
+
+// Clear remove all items from the filter
+func (cbf *CountingBloomFilter) Clear() {
+	for i := range cbf.counters {
+		cbf.counters[i] = 0
+	}
+}
+
+// Union merge other into cbf by adding the counters, the filters must have the same m and k
+func (cbf *CountingBloomFilter) Union(other *CountingBloomFilter) error {
+	if cbf.m != other.m || cbf.k != other.k {
+		return errors.New(sErrBloomNotCompatible)
+	}
+	for i, c := range other.counters {
+		if sum := int(cbf.counters[i]) + int(c); sum < bloomMaxCount {
+			cbf.counters[i] = uint8(sum)
+		} else {
+			cbf.counters[i] = bloomMaxCount
+		}
+	}
+	return nil
+}
+
+// Intersect intersect cbf with other by keeping the minimum counters, the filters must have the same m and k
+func (cbf *CountingBloomFilter) Intersect(other *CountingBloomFilter) error {
+	if cbf.m != other.m || cbf.k != other.k {
+		return errors.New(sErrBloomNotCompatible)
+	}
+	for i, c := range other.counters {
+		if c < cbf.counters[i] {
+			cbf.counters[i] = c
+		}
+	}
+	return nil
+}
+
+// BloomFilter return a Bloom filter with the same items, the set bits are the non-zero counters
+func (cbf *CountingBloomFilter) BloomFilter() *BloomFilter {
+	bf := NewBloomFilterWithSize(cbf.m, cbf.k)
+	for i, c := range cbf.counters {
+		if c > 0 {
+			bf.bits[i>>6] |= 1 << (uint(i) & 63)
+		}
+	}
+	return bf
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler
+func (cbf *CountingBloomFilter) MarshalBinary() ([]byte, error) {
+	data := make([]byte, bloomHeaderLen, bloomHeaderLen+len(cbf.counters))
+	putBloomHeader(data, countingBloomMagic, cbf.m, cbf.k)
+	return append(data, cbf.counters...), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler
+func (cbf *CountingBloomFilter) UnmarshalBinary(data []byte) error {
+	m, k, err := getBloomHeader(data, countingBloomMagic)
+	if err != nil {
+		return err
+	}
+	data = data[bloomHeaderLen:]
+	if uint64(len(data)) != m {
+		return errors.New(sErrDataInvalid)
+	}
+	cbf.m, cbf.k, cbf.counters = m, k, append([]uint8(nil), data...)
+	return nil
+}
+
+// putBloomHeader write the header of the binary encoding to data
+func putBloomHeader(data []byte, magic [2]byte, m, k uint64) {
+	data[0], data[1], data[2] = magic[0], magic[1], bloomVersion
+	binary.BigEndian.PutUint64(data[3:], m)
+	binary.BigEndian.PutUint64(data[11:], k)
+}
+
+// getBloomHeader parse the header of the binary encoding
+func getBloomHeader(data []byte, magic [2]byte) (m, k uint64, err error) {
+	if len(data) < bloomHeaderLen || data[0] != magic[0] || data[1] != magic[1] || data[2] != bloomVersion {
+		return 0, 0, errors.New(sErrDataInvalid)
+	}
+	m = binary.BigEndian.Uint64(data[3:])
+	k = binary.BigEndian.Uint64(data[11:])
+	if m == 0 || m > bloomMaxM || k == 0 || k > bloomMaxK {
+		return 0, 0, errors.New(sErrDataInvalid)
+	}
+	return m, k, nil
+}
@@ -0,0 +1,290 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/tzdq/go-utils/file"
+)
+
+func TestEstimateBloomParams(t *testing.T) {
+	tests := []struct {
+		name  string
+		n     uint64
+		fp    float64
+		wantM uint64
+		wantK uint64
+	}{
+		{"1000#0.01", 1000, 0.01, 9586, 7},
+		{"1000000#0.001", 1000000, 0.001, 14377588, 10},
+		{"FpInvalid", 1000, 0, 9586, 7},
+		{"NZero", 0, 0.5, 2, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			m, k := EstimateBloomParams(tt.n, tt.fp)
+			if m != tt.wantM || k != tt.wantK {
+				t.Errorf("EstimateBloomParams() = %v, %v, want %v, %v", m, k, tt.wantM, tt.wantK)
+			}
+		})
+	}
+}
+
+func TestBloomLocations(t *testing.T) {
+	// Find data whose h2 is even, so h2%2 == 0 and the probes would all be the same
+	for i := 0; ; i++ {
+		data := []byte(strconv.Itoa(i))
+		if mix64(HashUInt64(data, HtCrc64ECMA))%2 != 0 {
+			continue
+		}
+		if locs := bloomLocations(data, 2, 2, nil); locs[0] == locs[1] {
+			t.Errorf("bloomLocations(%q) = %v, want 2 different positions", data, locs)
+		}
+		return
+	}
+}
+
+func TestBloomFilter(t *testing.T) {
+	bf := NewBloomFilter(10000, 0.01)
+	for i := 0; i < 10000; i++ {
+		bf.AddString("event-" + strconv.Itoa(i))
+	}
+	for i := 0; i < 10000; i++ {
+		if !bf.TestString("event-" + strconv.Itoa(i)) {
+			t.Fatalf("TestString(event-%d) = false", i)
+		}
+	}
+
+	fp := 0
+	for i := 10000; i < 20000; i++ {
+		if bf.TestString("event-" + strconv.Itoa(i)) {
+			fp++
+		}
+	}
+	if fp > 150 {
+		t.Errorf("false positive = %d/10000, expect about 100", fp)
+	}
+	if cnt := bf.EstimateCount(); cnt < 9800 || cnt > 10200 {
+		t.Errorf("EstimateCount() = %d, want about 10000", cnt)
+	}
+
+	if bf.TestAndAdd([]byte("new-event")) {
+		t.Errorf("TestAndAdd() = true for new item")
+	}
+	if !bf.TestAndAdd([]byte("new-event")) {
+		t.Errorf("TestAndAdd() = false for existing item")
+	}
+	bf.Clear()
+	if bf.TestString("event-1") {
+		t.Errorf("TestString() = true after Clear")
+	}
+}
+
+func TestBloomFilter_UnionIntersect(t *testing.T) {
+	a, b := NewBloomFilter(1000, 0.001), NewBloomFilter(1000, 0.001)
+	a.AddString("a")
+	a.AddString("both")
+	b.AddString("b")
+	b.AddString("both")
+
+	u := NewBloomFilter(1000, 0.001)
+	_ = u.Union(a)
+	if err := u.Union(b); err != nil {
+		t.Fatalf("Union() error = %v", err)
+	}
+	if !u.TestString("a") || !u.TestString("b") || !u.TestString("both") {
+		t.Errorf("Union() missing items")
+	}
+
+	if err := a.Intersect(b); err != nil {
+		t.Fatalf("Intersect() error = %v", err)
+	}
+	if a.TestString("a") || a.TestString("b") || !a.TestString("both") {
+		t.Errorf("Intersect() wrong items")
+	}
+
+	if err := a.Union(NewBloomFilter(10, 0.1)); err == nil {
+		t.Errorf("Union() expect error for different size")
+	}
+	if err := a.Intersect(NewBloomFilter(10, 0.1)); err == nil {
+		t.Errorf("Intersect() expect error for different size")
+	}
+}
+
+func TestBloomFilter_Binary(t *testing.T) {
+	bf := NewBloomFilter(100, 0.01)
+	bf.AddString("hello")
+	data, _ := bf.MarshalBinary()
+
+	path := filepath.Join(t.TempDir(), "bloom.bin")
+	if err := file.WriteFile4BufIO(path, string(data)); err != nil {
+		t.Fatalf("WriteFile4BufIO() error = %v", err)
+	}
+	read, err := file.ReadFile(path)
+	if err != nil {
+		t.Fatalf("ReadFile() error = %v", err)
+	}
+
+	got := &BloomFilter{}
+	if err = got.UnmarshalBinary(read); err != nil {
+		t.Fatalf("UnmarshalBinary() error = %v", err)
+	}
+	if got.Cap() != bf.Cap() || got.K() != bf.K() || !got.TestString("hello") || got.TestString("world") {
+		t.Errorf("UnmarshalBinary() = %v, want %v", got, bf)
+	}
+
+	var buf bytes.Buffer
+	if _, err = bf.WriteTo(&buf); err != nil {
+		t.Fatalf("WriteTo() error = %v", err)
+	}
+	got = &BloomFilter{}
+	if n, err := got.ReadFrom(&buf); err != nil || n != int64(len(data)) || !got.TestString("hello") {
+		t.Errorf("ReadFrom() = %v, %v", n, err)
+	}
+
+	for _, invalid := range [][]byte{nil, data[:bloomHeaderLen], data[:len(data)-1], append([]byte("CB"), data[2:]...)} {
+		if err = got.UnmarshalBinary(invalid); err == nil {
+			t.Errorf("UnmarshalBinary(%v) expect error", invalid)
+		}
+	}
+}
+
+func TestBloomFilter_BinaryHeader(t *testing.T) {
+	header := func(magic string, m, k uint64) []byte {
+		data := make([]byte, bloomHeaderLen)
+		copy(data, magic)
+		data[2] = bloomVersion
+		binary.BigEndian.PutUint64(data[3:], m)
+		binary.BigEndian.PutUint64(data[11:], k)
+		return data
+	}
+	tests := []struct {
+		name string
+		m, k uint64
+	}{
+		{name: "MaxUint64M", m: math.MaxUint64, k: 7},
+		{name: "OversizedM", m: bloomMaxM + 1, k: 7},
+		{name: "MaxM", m: bloomMaxM, k: 7},
+		{name: "ZeroM", m: 0, k: 7},
+		{name: "MaxUint64K", m: 64, k: math.MaxUint64},
+		{name: "HugeK", m: 64, k: 1 << 63},
+		{name: "OversizedK", m: 64, k: bloomMaxK + 1},
+		{name: "ZeroK", m: 64, k: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bf := header("BF", tt.m, tt.k)
+			if err := (&BloomFilter{}).UnmarshalBinary(bf); err == nil {
+				t.Errorf("BloomFilter.UnmarshalBinary() expect error")
+			}
+			if _, err := (&BloomFilter{}).ReadFrom(bytes.NewReader(bf)); err == nil {
+				t.Errorf("BloomFilter.ReadFrom() expect error")
+			}
+			if err := (&CountingBloomFilter{}).UnmarshalBinary(header("CB", tt.m, tt.k)); err == nil {
+				t.Errorf("CountingBloomFilter.UnmarshalBinary() expect error")
+			}
+		})
+	}
+
+	// The truncated headers and payloads
+	valid := append(header("BF", 128, 3), make([]byte, 16)...)
+	for i := 0; i < len(valid); i++ {
+		if err := (&BloomFilter{}).UnmarshalBinary(valid[:i]); err == nil {
+			t.Errorf("UnmarshalBinary() of %d bytes expect error", i)
+		}
+		if _, err := (&BloomFilter{}).ReadFrom(bytes.NewReader(valid[:i])); err == nil {
+			t.Errorf("ReadFrom() of %d bytes expect error", i)
+		}
+	}
+	if _, err := (&BloomFilter{}).ReadFrom(bytes.NewReader(valid)); err != nil {
+		t.Errorf("ReadFrom() error = %v", err)
+	}
+	counting := append(header("CB", 128, 3), make([]byte, 128)...)
+	for _, data := range [][]byte{counting[:bloomHeaderLen-1], counting[:len(counting)-1], append(counting, 0)} {
+		if err := (&CountingBloomFilter{}).UnmarshalBinary(data); err == nil {
+			t.Errorf("CountingBloomFilter.UnmarshalBinary() of %d bytes expect error", len(data))
+		}
+	}
+
+	// The constructors never produce a header which can not be decoded
+	if m, k := EstimateBloomParams(10, 1e-300); m > bloomMaxM || k != bloomMaxK {
+		t.Errorf("EstimateBloomParams() = %v, %v", m, k)
+	}
+	bf := NewBloomFilterWithSize(1024, math.MaxUint64)
+	data, _ := bf.MarshalBinary()
+	if bf.K() != bloomMaxK || (&BloomFilter{}).UnmarshalBinary(data) != nil {
+		t.Errorf("NewBloomFilterWithSize() K() = %v", bf.K())
+	}
+}
+
+func TestCountingBloomFilter(t *testing.T) {
+	cbf := NewCountingBloomFilter(1000, 0.01)
+	for i := 0; i < 1000; i++ {
+		cbf.AddString("event-" + strconv.Itoa(i))
+	}
+	for i := 0; i < 500; i++ {
+		if !cbf.RemoveString("event-" + strconv.Itoa(i)) {
+			t.Fatalf("RemoveString(event-%d) = false", i)
+		}
+	}
+	for i := 500; i < 1000; i++ {
+		if !cbf.TestString("event-" + strconv.Itoa(i)) {
+			t.Fatalf("TestString(event-%d) = false", i)
+		}
+	}
+	removed := 0
+	for i := 0; i < 500; i++ {
+		if !cbf.TestString("event-" + strconv.Itoa(i)) {
+			removed++
+		}
+	}
+	if removed < 480 {
+		t.Errorf("removed = %d/500", removed)
+	}
+	if cbf.RemoveString("not-exist") && cbf.TestString("not-exist") {
+		t.Errorf("RemoveString() = true for missing item")
+	}
+
+	bf := cbf.BloomFilter()
+	if !bf.TestString("event-999") {
+		t.Errorf("BloomFilter() missing items")
+	}
+
+	data, _ := cbf.MarshalBinary()
+	got := &CountingBloomFilter{}
+	if err := got.UnmarshalBinary(data); err != nil || !got.TestString("event-999") {
+		t.Errorf("UnmarshalBinary() error = %v", err)
+	}
+	if err := got.UnmarshalBinary(data[:len(data)-1]); err == nil {
+		t.Errorf("UnmarshalBinary() expect error")
+	}
+}
+
+func TestCountingBloomFilter_UnionIntersect(t *testing.T) {
+	a, b := NewCountingBloomFilterWithSize(1000, 5), NewCountingBloomFilterWithSize(1000, 5)
+	a.AddString("a")
+	a.AddString("both")
+	b.AddString("b")
+	b.AddString("both")
+
+	if err := a.Union(b); err != nil {
+		t.Fatalf("Union() error = %v", err)
+	}
+	if !a.RemoveString("both") || !a.TestString("both") {
+		t.Errorf("Union() should add the counters")
+	}
+
+	if err := a.Intersect(b); err != nil {
+		t.Fatalf("Intersect() error = %v", err)
+	}
+	if a.TestString("a") || !a.TestString("b") {
+		t.Errorf("Intersect() wrong items")
+	}
+	if err := a.Union(NewCountingBloomFilterWithSize(10, 5)); err == nil {
+		t.Errorf("Union() expect error for different size")
+	}
+}
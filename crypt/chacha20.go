@@ -0,0 +1,107 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// ChaCha20 is a stream cipher designed by D. J. Bernstein, see RFC 8439 section 2.3 and 2.4.
+// The 16-word state is: 4 constant words("expand 32-byte k"), 8 key words, 1 block counter word and 3 nonce words,
+// all words are little-endian. A 64-byte keystream block is the state after 20 rounds(10 column rounds and 10
+// diagonal rounds) added to the original state. With a 32-bit counter, at most 2^32 blocks(256GB) can be produced
+// under one (key, nonce).
+// HChaCha20 derives a subkey from a key and a 16-byte nonce, it is used to extend the nonce of ChaCha20 to 24 bytes
+// (XChaCha20), see draft-irtf-cfrg-xchacha section 2.2.
+
+const (
+	chachaKeySize    = 32
+	chachaNonceSize  = 12
+	xchachaNonceSize = 24
+	chachaBlockSize  = 64
+)
+
+// chachaConstants "expand 32-byte k"
+var chachaConstants = [4]uint32{0x61707865, 0x3320646e, 0x79622d32, 0x6b206574}
+
+// chachaQuarterRound the ChaCha quarter round
+func chachaQuarterRound(a, b, c, d uint32) (uint32, uint32, uint32, uint32) {
+	a += b
+	d = bits.RotateLeft32(d^a, 16)
+	c += d
+	b = bits.RotateLeft32(b^c, 12)
+	a += b
+	d = bits.RotateLeft32(d^a, 8)
+	c += d
+	b = bits.RotateLeft32(b^c, 7)
+	return a, b, c, d
+}
+
+// chachaRounds apply the 20 rounds to the state
+func chachaRounds(x *[16]uint32) {
+	for i := 0; i < 10; i++ {
+		// column rounds
+		x[0], x[4], x[8], x[12] = chachaQuarterRound(x[0], x[4], x[8], x[12])
+		x[1], x[5], x[9], x[13] = chachaQuarterRound(x[1], x[5], x[9], x[13])
+		x[2], x[6], x[10], x[14] = chachaQuarterRound(x[2], x[6], x[10], x[14])
+		x[3], x[7], x[11], x[15] = chachaQuarterRound(x[3], x[7], x[11], x[15])
+		// diagonal rounds
+		x[0], x[5], x[10], x[15] = chachaQuarterRound(x[0], x[5], x[10], x[15])
+		x[1], x[6], x[11], x[12] = chachaQuarterRound(x[1], x[6], x[11], x[12])
+		x[2], x[7], x[8], x[13] = chachaQuarterRound(x[2], x[7], x[8], x[13])
+		x[3], x[4], x[9], x[14] = chachaQuarterRound(x[3], x[4], x[9], x[14])
+	}
+}
+
+// chachaInitState return the initial state of key and the 16 bytes input(counter and nonce, or the HChaCha20 nonce)
+func chachaInitState(key *[chachaKeySize]byte, input []byte) [16]uint32 {
+	var s [16]uint32
+	copy(s[:4], chachaConstants[:])
+	for i := 0; i < 8; i++ {
+		s[4+i] = binary.LittleEndian.Uint32(key[i*4:])
+	}
+	for i := 0; i < 4; i++ {
+		s[12+i] = binary.LittleEndian.Uint32(input[i*4:])
+	}
+	return s
+}
+
+// chacha20XORKeyStream XOR src with the ChaCha20 keystream of (key, nonce) starting at block counter, and write the
+// result to dst. dst and src may overlap entirely or not at all, the caller must ensure the counter does not
+// overflow
+func chacha20XORKeyStream(dst, src []byte, key *[chachaKeySize]byte, nonce []byte, counter uint32) {
+	var input [16]byte
+	binary.LittleEndian.PutUint32(input[0:4], counter)
+	copy(input[4:], nonce)
+	state := chachaInitState(key, input[:])
+
+	var block [chachaBlockSize]byte
+	for len(src) > 0 {
+		x := state
+		chachaRounds(&x)
+		for i := range x {
+			binary.LittleEndian.PutUint32(block[i*4:], x[i]+state[i])
+		}
+		n := len(src)
+		if n > chachaBlockSize {
+			n = chachaBlockSize
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ block[i]
+		}
+		dst, src = dst[n:], src[n:]
+		state[12]++
+	}
+}
+
+// hChaCha20 return the subkey derived from key and the 16-byte nonce
+func hChaCha20(key *[chachaKeySize]byte, nonce []byte) [chachaKeySize]byte {
+	x := chachaInitState(key, nonce)
+	chachaRounds(&x)
+
+	var subkey [chachaKeySize]byte
+	for i := 0; i < 4; i++ {
+		binary.LittleEndian.PutUint32(subkey[i*4:], x[i])
+		binary.LittleEndian.PutUint32(subkey[16+i*4:], x[12+i])
+	}
+	return subkey
+}
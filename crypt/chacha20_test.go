@@ -0,0 +1,68 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestChaCha20XORKeyStream(t *testing.T) {
+	key := [chachaKeySize]byte{}
+	for i := range key {
+		key[i] = byte(i)
+	}
+	plaintext := []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the future, " +
+		"sunscreen would be it.")
+	tests := []struct {
+		name    string
+		nonce   string
+		counter uint32
+		src     []byte
+		want    string
+	}{
+		{
+			name:    "RFC8439_2.4.2",
+			nonce:   "000000000000004a00000000",
+			counter: 1,
+			src:     plaintext,
+			want: "6e2e359a2568f98041ba0728dd0d6981e97e7aec1d4360c20a27afccfd9fae0bf91b65c5524733ab8f593dabcd62b3" +
+				"571639d624e65152ab8f530c359f0861d807ca0dbf500d6a6156a38e088a22b65e52bc514d16ccf806818ce91ab7793736" +
+				"5af90bbf74a35be6b40b8eedf2785e42874d",
+		},
+		{
+			name:    "RFC8439_2.3.2",
+			nonce:   "000000090000004a00000000",
+			counter: 1,
+			src:     make([]byte, 64),
+			want: "10f1e7e4d13b5915500fdd1fa32071c4c7d1f4c733c068030422aa9ac3d46c4ed2826446079faa0914c2d705d98b02a2" +
+				"b5129cd1de164eb9cbd083e8a2503c4e",
+		},
+		{
+			name:  "Empty",
+			nonce: "000000000000000000000000",
+			src:   []byte{},
+			want:  "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			nonce, _ := hex.DecodeString(tt.nonce)
+			dst := make([]byte, len(tt.src))
+			chacha20XORKeyStream(dst, tt.src, &key, nonce, tt.counter)
+			if got := hex.EncodeToString(dst); got != tt.want {
+				t.Errorf("chacha20XORKeyStream() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHChaCha20(t *testing.T) {
+	key := [chachaKeySize]byte{}
+	for i := range key {
+		key[i] = byte(i)
+	}
+	nonce, _ := hex.DecodeString("000000090000004a0000000031415927")
+	want := "82413b4227b27bfed30e42508a877d73a0f9e4d58a74a853c12ec41326d3ecdc"
+	if got := hChaCha20(&key, nonce); hex.EncodeToString(got[:]) != want {
+		t.Errorf("hChaCha20() = %x, want %v", got, want)
+	}
+}
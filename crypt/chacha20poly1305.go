@@ -0,0 +1,219 @@
+package crypt
+
+import (
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// ChaCha20-Poly1305 is an AEAD(Authenticated Encryption with Associated Data) algorithm, see RFC 8439 section 2.8.
+// It is fast in software and constant time without special CPU instructions, a good choice on the hosts without
+// AES instructions, such as many ARM processors.
+// The key is 32 bytes, the nonce is 12 bytes and the tag is 16 bytes: the one-time Poly1305 key is the first 32
+// bytes of the ChaCha20 keystream block 0, the plaintext is encrypted from block 1, and the tag authenticates
+//  AAD || pad16(AAD) || ciphertext || pad16(ciphertext) || len(AAD)(8 bytes LE) || len(ciphertext)(8 bytes LE)
+// A nonce must never be reused with the same key. The 12-byte nonce is too short to be generated randomly for a large
+// number of messages(the collision probability reaches 2^-32 after about 2^32 messages), in that case use
+// XChaCha20-Poly1305, whose 24-byte nonce is safe to be generated randomly.
+// XChaCha20-Poly1305 derives a subkey by HChaCha20(key, nonce[0:16]) and encrypts with ChaCha20-Poly1305 under the
+// subkey and the nonce 0x00000000 || nonce[16:24], see draft-irtf-cfrg-xchacha.
+// ChaCha20 and Poly1305 are implemented here instead of importing golang.org/x/crypto, because the module has no
+// dependencies and supports go 1.16, while every release of x/crypto requires go 1.17 or later. Neither of them
+// branches on or indexes by secret data, and the tests check them against the vectors of RFC 8439.
+
+// chacha20Poly1305MaxSize the maximum plaintext size of one message, limited by the 32-bit block counter
+const chacha20Poly1305MaxSize = (1<<32 - 1) * chachaBlockSize
+
+// chacha20Poly1305 implement cipher.AEAD for ChaCha20-Poly1305 and XChaCha20-Poly1305
+type chacha20Poly1305 struct {
+	key      [chachaKeySize]byte
+	extended bool
+}
+
+// NewChaCha20Poly1305 return a ChaCha20-Poly1305 AEAD with the 32-byte key, the nonce is 12 bytes
+func NewChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if len(key) != chachaKeySize {
+		return nil, errors.New(sErrKeySizeInvalid)
+	}
+	c := &chacha20Poly1305{}
+	copy(c.key[:], key)
+	return c, nil
+}
+
+// NewXChaCha20Poly1305 return a XChaCha20-Poly1305 AEAD with the 32-byte key, the nonce is 24 bytes and it is safe
+// to be generated randomly
+func NewXChaCha20Poly1305(key []byte) (cipher.AEAD, error) {
+	if len(key) != chachaKeySize {
+		return nil, errors.New(sErrKeySizeInvalid)
+	}
+	c := &chacha20Poly1305{extended: true}
+	copy(c.key[:], key)
+	return c, nil
+}
+
+// NonceSize implement cipher.AEAD
+func (c *chacha20Poly1305) NonceSize() int {
+	if c.extended {
+		return xchachaNonceSize
+	}
+	return chachaNonceSize
+}
+
+// Overhead implement cipher.AEAD
+func (c *chacha20Poly1305) Overhead() int {
+	return poly1305TagSize
+}
+
+// Seal implement cipher.AEAD, it panics if the length of nonce is invalid or the plaintext is too large
+func (c *chacha20Poly1305) Seal(dst, nonce, plaintext, additionalData []byte) []byte {
+	if len(nonce) != c.NonceSize() {
+		panic("crypt: invalid chacha20poly1305 nonce length")
+	}
+	if uint64(len(plaintext)) > chacha20Poly1305MaxSize {
+		panic("crypt: chacha20poly1305 plaintext too large")
+	}
+	key, nonce := c.subkey(nonce)
+
+	ret, out := sliceForAppend(dst, len(plaintext)+poly1305TagSize)
+	ciphertext, tag := out[:len(plaintext)], out[len(plaintext):]
+	chacha20XORKeyStream(ciphertext, plaintext, &key, nonce, 1)
+
+	var sum [poly1305TagSize]byte
+	chacha20Poly1305Mac(&sum, &key, nonce, additionalData, ciphertext)
+	copy(tag, sum[:])
+	return ret
+}
+
+// Open implement cipher.AEAD, it panics if the length of nonce is invalid
+func (c *chacha20Poly1305) Open(dst, nonce, ciphertext, additionalData []byte) ([]byte, error) {
+	if len(nonce) != c.NonceSize() {
+		panic("crypt: invalid chacha20poly1305 nonce length")
+	}
+	if len(ciphertext) < poly1305TagSize || uint64(len(ciphertext)-poly1305TagSize) > chacha20Poly1305MaxSize {
+		return nil, errors.New(sErrAuthFailed)
+	}
+	key, nonce := c.subkey(nonce)
+	tag := ciphertext[len(ciphertext)-poly1305TagSize:]
+	ciphertext = ciphertext[:len(ciphertext)-poly1305TagSize]
+
+	var sum [poly1305TagSize]byte
+	chacha20Poly1305Mac(&sum, &key, nonce, additionalData, ciphertext)
+	if subtle.ConstantTimeCompare(sum[:], tag) != 1 {
+		return nil, errors.New(sErrAuthFailed)
+	}
+
+	ret, out := sliceForAppend(dst, len(ciphertext))
+	chacha20XORKeyStream(out, ciphertext, &key, nonce, 1)
+	return ret, nil
+}
+
+// subkey return the ChaCha20 key and 12-byte nonce, for XChaCha20 they are derived by HChaCha20
+func (c *chacha20Poly1305) subkey(nonce []byte) ([chachaKeySize]byte, []byte) {
+	if !c.extended {
+		return c.key, nonce
+	}
+	key := hChaCha20(&c.key, nonce[:16])
+	n := make([]byte, chachaNonceSize)
+	copy(n[4:], nonce[16:])
+	return key, n
+}
+
+// chacha20Poly1305Mac compute the Poly1305 tag of the additional data and ciphertext
+func chacha20Poly1305Mac(out *[poly1305TagSize]byte, key *[chachaKeySize]byte, nonce, ad, ciphertext []byte) {
+	var polyKey [32]byte
+	chacha20XORKeyStream(polyKey[:], polyKey[:], key, nonce, 0)
+
+	var pad [16]byte
+	p := newPoly1305(&polyKey)
+	p.Write(ad)
+	if r := len(ad) % 16; r != 0 {
+		p.Write(pad[:16-r])
+	}
+	p.Write(ciphertext)
+	if r := len(ciphertext) % 16; r != 0 {
+		p.Write(pad[:16-r])
+	}
+	var lens [16]byte
+	binary.LittleEndian.PutUint64(lens[0:8], uint64(len(ad)))
+	binary.LittleEndian.PutUint64(lens[8:16], uint64(len(ciphertext)))
+	p.Write(lens[:])
+	p.Sum(out)
+}
+
+// ChaCha20Poly1305Encrypt Encrypts data with ChaCha20-Poly1305 and a random nonce, additionalData is authenticated
+// but not encrypted, it can be nil. The result is nonce(12 bytes) || ciphertext || tag(16 bytes).
+// The key must be 32 bytes. Because the nonce is random, encrypt at most 2^32 messages with the same key, otherwise
+// use XChaCha20Poly1305Encrypt
+// Recommended in combination with base64,such as: Base64Encode(ChaCha20Poly1305Encrypt(plaintext,key,nil))
+func ChaCha20Poly1305Encrypt(plaintext, key, additionalData []byte) ([]byte, error) {
+	aead, err := NewChaCha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+	return aeadEncrypt(aead, plaintext, additionalData)
+}
+
+// ChaCha20Poly1305Decrypt Decrypts the result of ChaCha20Poly1305Encrypt, additionalData must be the same as
+// encryption
+func ChaCha20Poly1305Decrypt(ciphertext, key, additionalData []byte) ([]byte, error) {
+	aead, err := NewChaCha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+	return aeadDecrypt(aead, ciphertext, additionalData)
+}
+
+// XChaCha20Poly1305Encrypt Encrypts data with XChaCha20-Poly1305 and a random nonce, additionalData is authenticated
+// but not encrypted, it can be nil. The result is nonce(24 bytes) || ciphertext || tag(16 bytes).
+// The key must be 32 bytes
+func XChaCha20Poly1305Encrypt(plaintext, key, additionalData []byte) ([]byte, error) {
+	aead, err := NewXChaCha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+	return aeadEncrypt(aead, plaintext, additionalData)
+}
+
+// XChaCha20Poly1305Decrypt Decrypts the result of XChaCha20Poly1305Encrypt, additionalData must be the same as
+// encryption
+func XChaCha20Poly1305Decrypt(ciphertext, key, additionalData []byte) ([]byte, error) {
+	aead, err := NewXChaCha20Poly1305(key)
+	if err != nil {
+		return nil, err
+	}
+	return aeadDecrypt(aead, ciphertext, additionalData)
+}
+
+// aeadEncrypt encrypt plaintext with a random nonce and prepend the nonce to the result
+func aeadEncrypt(aead cipher.AEAD, plaintext, additionalData []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	nonce := make([]byte, nonceSize, nonceSize+len(plaintext)+aead.Overhead())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+	return aead.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+// aeadDecrypt decrypt the result of aeadEncrypt
+func aeadDecrypt(aead cipher.AEAD, ciphertext, additionalData []byte) ([]byte, error) {
+	nonceSize := aead.NonceSize()
+	if len(ciphertext) < nonceSize+aead.Overhead() {
+		return nil, errors.New(sErrDataInvalid)
+	}
+	return aead.Open(nil, ciphertext[:nonceSize], ciphertext[nonceSize:], additionalData)
+}
+
+// sliceForAppend extend in by n bytes, return the whole slice and the extended part
+func sliceForAppend(in []byte, n int) (head, tail []byte) {
+	if total := len(in) + n; cap(in) >= total {
+		head = in[:total]
+	} else {
+		head = make([]byte, total)
+		copy(head, in)
+	}
+	tail = head[len(in):]
+	return
+}
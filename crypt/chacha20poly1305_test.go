@@ -0,0 +1,129 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+var chachaSunscreenTest = []byte("Ladies and Gentlemen of the class of '99: If I could offer you only one tip for the " +
+	"future, sunscreen would be it.")
+
+func TestChaCha20Poly1305Seal(t *testing.T) {
+	key, _ := hex.DecodeString("808182838485868788898a8b8c8d8e8f909192939495969798999a9b9c9d9e9f")
+	aad, _ := hex.DecodeString("50515253c0c1c2c3c4c5c6c7")
+	tests := []struct {
+		name     string
+		extended bool
+		nonce    string
+		want     string
+	}{
+		{
+			name:  "RFC8439_2.8.2",
+			nonce: "070000004041424344454647",
+			want: "d31a8d34648e60db7b86afbc53ef7ec2a4aded51296e08fea9e2b5a736ee62d63dbea45e8ca9671282fafb69da92728b" +
+				"1a71de0a9e060b2905d6a5b67ecd3b3692ddbd7f2d778b8c9803aee328091b58fab324e4fad675945585808b4831d7bc3f" +
+				"f4def08e4b7a9de576d26586cec64b6116" + "1ae10b594f09e26a7e902ecbd0600691",
+		},
+		{
+			name:     "XChaCha_A.3.1",
+			extended: true,
+			nonce:    "404142434445464748494a4b4c4d4e4f5051525354555657",
+			want: "bd6d179d3e83d43b9576579493c0e939572a1700252bfaccbed2902c21396cbb731c7f1b0b4aa6440bf3a82f4eda7e39" +
+				"ae64c6708c54c216cb96b72e1213b4522f8c9ba40db5d945b11b69b982c1bb9e3f3fac2bc369488f76b2383565d3fff9" +
+				"21f9664c97637da9768812f615c68b13b52e" + "c0875924c1c7987947deafd8780acf49",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			newAEAD := NewChaCha20Poly1305
+			if tt.extended {
+				newAEAD = NewXChaCha20Poly1305
+			}
+			aead, err := newAEAD(key)
+			if err != nil {
+				t.Fatalf("new aead error = %v", err)
+			}
+			nonce, _ := hex.DecodeString(tt.nonce)
+			ciphertext := aead.Seal(nil, nonce, chachaSunscreenTest, aad)
+			if got := hex.EncodeToString(ciphertext); got != tt.want {
+				t.Errorf("Seal() = %v, want %v", got, tt.want)
+			}
+			plaintext, err := aead.Open(nil, nonce, ciphertext, aad)
+			if err != nil || !bytes.Equal(plaintext, chachaSunscreenTest) {
+				t.Errorf("Open() = %s, %v, want %s", plaintext, err, chachaSunscreenTest)
+			}
+			ciphertext[0] ^= 1
+			if _, err = aead.Open(nil, nonce, ciphertext, aad); err == nil {
+				t.Errorf("Open() tampered ciphertext error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestChaCha20Poly1305Encrypt(t *testing.T) {
+	type args struct {
+		data []byte
+		key  []byte
+		aad  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "KeyInvalidLen",
+			args:    args{data: commonOriginData, key: commonKey16},
+			wantErr: true,
+		},
+		{
+			name: "DataEmpty",
+			args: args{data: []byte{}, key: commonKey32},
+		},
+		{
+			name: "Normal",
+			args: args{data: commonOriginData, key: commonKey32},
+		},
+		{
+			name: "AdditionalData",
+			args: args{data: commonOriginData, key: commonKey32, aad: []byte("header")},
+		},
+	}
+	encrypts := []struct {
+		name      string
+		encrypt   func(plaintext, key, additionalData []byte) ([]byte, error)
+		decrypt   func(ciphertext, key, additionalData []byte) ([]byte, error)
+		nonceSize int
+	}{
+		{"ChaCha20", ChaCha20Poly1305Encrypt, ChaCha20Poly1305Decrypt, chachaNonceSize},
+		{"XChaCha20", XChaCha20Poly1305Encrypt, XChaCha20Poly1305Decrypt, xchachaNonceSize},
+	}
+	for _, e := range encrypts {
+		for _, tt := range tests {
+			t.Run(e.name+tt.name, func(t *testing.T) {
+				ciphertext, err := e.encrypt(tt.args.data, tt.args.key, tt.args.aad)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				if len(ciphertext) != e.nonceSize+len(tt.args.data)+poly1305TagSize {
+					t.Errorf("Encrypt() len = %v, want %v", len(ciphertext),
+						e.nonceSize+len(tt.args.data)+poly1305TagSize)
+				}
+				got, err := e.decrypt(ciphertext, tt.args.key, tt.args.aad)
+				if err != nil || !bytes.Equal(got, tt.args.data) {
+					t.Errorf("Decrypt() = %v, %v, want %v", got, err, tt.args.data)
+				}
+				if _, err = e.decrypt(ciphertext, tt.args.key, []byte("other")); err == nil {
+					t.Errorf("Decrypt() with other additional data error = nil, want error")
+				}
+				if _, err = e.decrypt(ciphertext[:e.nonceSize+poly1305TagSize-1], tt.args.key, tt.args.aad); err == nil {
+					t.Errorf("Decrypt() short data error = nil, want error")
+				}
+			})
+		}
+	}
+}
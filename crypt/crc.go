@@ -0,0 +1,264 @@
+package crypt
+
+import (
+	"errors"
+	"hash"
+	"strings"
+)
+
+// Cyclic Redundancy Check (CRC) is described here with the Rocksoft model, every CRC algorithm is determined by
+// the following parameters:
+// Width : The width of the register in bits, support 1~64
+// Poly  : The generator polynomial in normal(MSB-first) form, the highest bit is omitted, e.g. 0x8005 for CRC-16
+// Init  : The initial value of the register, in normal form
+// RefIn : If true, each input byte is reflected(processed LSB-first) before processing
+// RefOut: If true, the final register value is reflected before XorOut
+// XorOut: The value XORed with the final register value
+// Check : The checksum of the ASCII string "123456789", only used to verify the parameters
+// The parameters of the common algorithms come from the CRC RevEng catalogue,
+// see https://reveng.sourceforge.io/crc-catalogue/all.htm
+
+// CrcParams parameters of a CRC algorithm in the Rocksoft model
+type CrcParams struct {
+	Name   string
+	Width  uint
+	Poly   uint64
+	Init   uint64
+	RefIn  bool
+	RefOut bool
+	XorOut uint64
+	Check  uint64
+}
+
+// catalogue of common CRC algorithms, it is unexported so the built-in engines can not be changed by the callers,
+// the functions below return copies of the parameters
+var (
+	crc8Params         = CrcParams{"CRC-8/SMBUS", 8, 0x07, 0x00, false, false, 0x00, 0xf4}
+	crc8MaximParams    = CrcParams{"CRC-8/MAXIM-DOW", 8, 0x31, 0x00, true, true, 0x00, 0xa1}
+	crc16ARCParams     = CrcParams{"CRC-16/ARC", 16, 0x8005, 0x0000, true, true, 0x0000, 0xbb3d}
+	crc16ModbusParams  = CrcParams{"CRC-16/MODBUS", 16, 0x8005, 0xffff, true, true, 0x0000, 0x4b37}
+	crc16USBParams     = CrcParams{"CRC-16/USB", 16, 0x8005, 0xffff, true, true, 0xffff, 0xb4c8}
+	crc16CCITTParams   = CrcParams{"CRC-16/CCITT-FALSE", 16, 0x1021, 0xffff, false, false, 0x0000, 0x29b1}
+	crc16KermitParams  = CrcParams{"CRC-16/KERMIT", 16, 0x1021, 0x0000, true, true, 0x0000, 0x2189}
+	crc16XModemParams  = CrcParams{"CRC-16/XMODEM", 16, 0x1021, 0x0000, false, false, 0x0000, 0x31c3}
+	crc16X25Params     = CrcParams{"CRC-16/X-25", 16, 0x1021, 0xffff, true, true, 0xffff, 0x906e}
+	crc32IEEEParams    = CrcParams{"CRC-32/ISO-HDLC", 32, 0x04c11db7, 0xffffffff, true, true, 0xffffffff, 0xcbf43926}
+	crc32CParams       = CrcParams{"CRC-32/ISCSI", 32, 0x1edc6f41, 0xffffffff, true, true, 0xffffffff, 0xe3069283}
+	crc32BZip2Params   = CrcParams{"CRC-32/BZIP2", 32, 0x04c11db7, 0xffffffff, false, false, 0xffffffff, 0xfc891918}
+	crc32MPEG2Params   = CrcParams{"CRC-32/MPEG-2", 32, 0x04c11db7, 0xffffffff, false, false, 0x00000000, 0x0376e6e7}
+	crc64ECMA182Params = CrcParams{"CRC-64/ECMA-182", 64, 0x42f0e1eba9ea3693, 0, false, false, 0, 0x6c40df5f0b497347}
+	crc64XZParams      = CrcParams{"CRC-64/XZ", 64, 0x42f0e1eba9ea3693, ^uint64(0), true, true, ^uint64(0), 0x995dc9bbdf1939fa}
+	crc64GoISOParams   = CrcParams{"CRC-64/GO-ISO", 64, 0x1b, ^uint64(0), true, true, ^uint64(0), 0xb90956c775a41001}
+	crcCatalogues      = []CrcParams{crc8Params, crc8MaximParams, crc16ARCParams, crc16ModbusParams, crc16USBParams,
+		crc16CCITTParams, crc16KermitParams, crc16XModemParams, crc16X25Params, crc32IEEEParams, crc32CParams,
+		crc32BZip2Params, crc32MPEG2Params, crc64ECMA182Params, crc64XZParams, crc64GoISOParams}
+)
+
+// Crc8 return the parameters of CRC-8/SMBUS
+func Crc8() CrcParams { return crc8Params }
+
+// Crc8Maxim return the parameters of CRC-8/MAXIM-DOW
+func Crc8Maxim() CrcParams { return crc8MaximParams }
+
+// Crc16ARC return the parameters of CRC-16/ARC
+func Crc16ARC() CrcParams { return crc16ARCParams }
+
+// Crc16Modbus return the parameters of CRC-16/MODBUS
+func Crc16Modbus() CrcParams { return crc16ModbusParams }
+
+// Crc16USB return the parameters of CRC-16/USB
+func Crc16USB() CrcParams { return crc16USBParams }
+
+// Crc16CCITT return the parameters of CRC-16/CCITT-FALSE
+func Crc16CCITT() CrcParams { return crc16CCITTParams }
+
+// Crc16Kermit return the parameters of CRC-16/KERMIT
+func Crc16Kermit() CrcParams { return crc16KermitParams }
+
+// Crc16XModem return the parameters of CRC-16/XMODEM
+func Crc16XModem() CrcParams { return crc16XModemParams }
+
+// Crc16X25 return the parameters of CRC-16/X-25
+func Crc16X25() CrcParams { return crc16X25Params }
+
+// Crc32IEEE return the parameters of CRC-32/ISO-HDLC
+func Crc32IEEE() CrcParams { return crc32IEEEParams }
+
+// Crc32C return the parameters of CRC-32/ISCSI
+func Crc32C() CrcParams { return crc32CParams }
+
+// Crc32BZip2 return the parameters of CRC-32/BZIP2
+func Crc32BZip2() CrcParams { return crc32BZip2Params }
+
+// Crc32MPEG2 return the parameters of CRC-32/MPEG-2
+func Crc32MPEG2() CrcParams { return crc32MPEG2Params }
+
+// Crc64ECMA182 return the parameters of CRC-64/ECMA-182
+func Crc64ECMA182() CrcParams { return crc64ECMA182Params }
+
+// Crc64XZ return the parameters of CRC-64/XZ
+func Crc64XZ() CrcParams { return crc64XZParams }
+
+// Crc64GoISO return the parameters of CRC-64/GO-ISO
+func Crc64GoISO() CrcParams { return crc64GoISOParams }
+
+// crc engines used by HashBytes、HashUInt32
+var (
+	crc16ModbusEngine = mustNewCrc(crc16ModbusParams)
+	crc16CCITTEngine  = mustNewCrc(crc16CCITTParams)
+	crc16XModemEngine = mustNewCrc(crc16XModemParams)
+)
+
+// CrcPreset return the parameters of the CRC algorithm in the catalogue by name, the name is case-insensitive,
+// such as: CrcPreset("crc-16/modbus")
+func CrcPreset(name string) (CrcParams, bool) {
+	for _, p := range crcCatalogues {
+		if strings.EqualFold(p.Name, name) {
+			return p, true
+		}
+	}
+	return CrcParams{}, false
+}
+
+// Crc a table driven CRC engine for the specified parameters, it is safe for concurrent use
+type Crc struct {
+	params CrcParams
+	mask   uint64
+	table  [256]uint64
+}
+
+// NewCrc return a CRC engine for the specified parameters, the width must be in the range of [1,64]
+func NewCrc(params CrcParams) (*Crc, error) {
+	if params.Width == 0 || params.Width > 64 {
+		return nil, errors.New(sErrCrcWidthInvalid)
+	}
+
+	c := &Crc{params: params, mask: ^uint64(0) >> (64 - params.Width)}
+	if params.RefIn {
+		// The register holds the reflected value and shifts to the right
+		poly := reflectBits(params.Poly&c.mask, params.Width)
+		for i := range c.table {
+			crc := uint64(i)
+			for j := 0; j < 8; j++ {
+				if crc&1 == 1 {
+					crc = (crc >> 1) ^ poly
+				} else {
+					crc >>= 1
+				}
+			}
+			c.table[i] = crc
+		}
+	} else {
+		// The register is aligned to the highest bit of uint64 and shifts to the left
+		poly := (params.Poly & c.mask) << (64 - params.Width)
+		for i := range c.table {
+			crc := uint64(i) << 56
+			for j := 0; j < 8; j++ {
+				if crc&(1<<63) != 0 {
+					crc = (crc << 1) ^ poly
+				} else {
+					crc <<= 1
+				}
+			}
+			c.table[i] = crc
+		}
+	}
+	return c, nil
+}
+
+// mustNewCrc like NewCrc but panics if the parameters are invalid, only used for the built-in parameters
+func mustNewCrc(params CrcParams) *Crc {
+	c, err := NewCrc(params)
+	if err != nil {
+		panic(err)
+	}
+	return c
+}
+
+// Params return the parameters of the CRC engine
+func (c *Crc) Params() CrcParams {
+	return c.params
+}
+
+// Checksum return the CRC checksum of data
+func (c *Crc) Checksum(data []byte) uint64 {
+	return c.finish(c.update(c.init(), data))
+}
+
+// New return a new hash.Hash64 computing the CRC checksum, the Sum method lays the value out in big-endian byte order
+// with (Width+7)/8 bytes
+func (c *Crc) New() hash.Hash64 {
+	d := &crcDigest{c: c}
+	d.Reset()
+	return d
+}
+
+// init return the initial value of the internal register
+func (c *Crc) init() uint64 {
+	if c.params.RefIn {
+		return reflectBits(c.params.Init&c.mask, c.params.Width)
+	}
+	return (c.params.Init & c.mask) << (64 - c.params.Width)
+}
+
+// update process data with the internal register
+func (c *Crc) update(crc uint64, data []byte) uint64 {
+	if c.params.RefIn {
+		for _, b := range data {
+			crc = c.table[byte(crc)^b] ^ (crc >> 8)
+		}
+	} else {
+		for _, b := range data {
+			crc = c.table[byte(crc>>56)^b] ^ (crc << 8)
+		}
+	}
+	return crc
+}
+
+// finish convert the internal register to the final checksum
+func (c *Crc) finish(crc uint64) uint64 {
+	if !c.params.RefIn {
+		crc >>= 64 - c.params.Width
+	}
+	if c.params.RefIn != c.params.RefOut {
+		crc = reflectBits(crc, c.params.Width)
+	}
+	return (crc ^ c.params.XorOut) & c.mask
+}
+
+// reflectBits reverse the low width bits of v
+func reflectBits(v uint64, width uint) uint64 {
+	var r uint64
+	for i := uint(0); i < width; i++ {
+		r = (r << 1) | (v & 1)
+		v >>= 1
+	}
+	return r
+}
+
+// crcDigest implement hash.Hash64 for Crc
+type crcDigest struct {
+	c   *Crc
+	crc uint64
+}
+
+func (d *crcDigest) Size() int { return int(d.c.params.Width+7) / 8 }
+
+func (d *crcDigest) BlockSize() int { return 1 }
+
+func (d *crcDigest) Reset() { d.crc = d.c.init() }
+
+func (d *crcDigest) Write(p []byte) (int, error) {
+	d.crc = d.c.update(d.crc, p)
+	return len(p), nil
+}
+
+func (d *crcDigest) Sum64() uint64 { return d.c.finish(d.crc) }
+
+func (d *crcDigest) Sum(in []byte) []byte {
+	s := d.Sum64()
+	for i := d.Size() - 1; i >= 0; i-- {
+		in = append(in, byte(s>>(uint(i)*8)))
+	}
+	return in
+}
@@ -0,0 +1,110 @@
+package crypt
+
+import (
+	"hash/crc32"
+	"hash/crc64"
+	"reflect"
+	"testing"
+)
+
+// "123456789"
+var crcCheckTest = []byte{49, 50, 51, 52, 53, 54, 55, 56, 57}
+
+func TestCrcChecksum(t *testing.T) {
+	for _, params := range crcCatalogues {
+		t.Run(params.Name, func(t *testing.T) {
+			c, err := NewCrc(params)
+			if err != nil {
+				t.Fatalf("NewCrc() error = %v", err)
+			}
+			if got := c.Checksum(crcCheckTest); got != params.Check {
+				t.Errorf("Checksum() = %#x, want %#x", got, params.Check)
+			}
+		})
+	}
+}
+
+func TestCrcChecksumStdlib(t *testing.T) {
+	tests := []struct {
+		name   string
+		params CrcParams
+		want   uint64
+	}{
+		{"Crc32IEEE", Crc32IEEE(), uint64(crc32.ChecksumIEEE(hashCommonTest))},
+		{"Crc32C", Crc32C(), uint64(crc32.Checksum(hashCommonTest, crc32.MakeTable(crc32.Castagnoli)))},
+		{"Crc64XZ", Crc64XZ(), crc64.Checksum(hashCommonTest, crc64.MakeTable(crc64.ECMA))},
+		{"Crc64GoISO", Crc64GoISO(), crc64.Checksum(hashCommonTest, crc64.MakeTable(crc64.ISO))},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustNewCrc(tt.params).Checksum(hashCommonTest); got != tt.want {
+				t.Errorf("Checksum() = %#x, want %#x", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCrcSmallWidth(t *testing.T) {
+	tests := []struct {
+		name   string
+		params CrcParams
+	}{
+		{"CRC-3/GSM", CrcParams{"CRC-3/GSM", 3, 0x3, 0x0, false, false, 0x7, 0x4}},
+		{"CRC-5/USB", CrcParams{"CRC-5/USB", 5, 0x05, 0x1f, true, true, 0x1f, 0x19}},
+		{"CRC-7/MMC", CrcParams{"CRC-7/MMC", 7, 0x09, 0x00, false, false, 0x00, 0x75}},
+		{"CRC-12/UMTS", CrcParams{"CRC-12/UMTS", 12, 0x80f, 0x000, false, true, 0x000, 0xdaf}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := mustNewCrc(tt.params).Checksum(crcCheckTest); got != tt.params.Check {
+				t.Errorf("Checksum() = %#x, want %#x", got, tt.params.Check)
+			}
+		})
+	}
+}
+
+func TestNewCrc(t *testing.T) {
+	tests := []struct {
+		name    string
+		width   uint
+		wantErr bool
+	}{
+		{"Width0", 0, true},
+		{"Width65", 65, true},
+		{"Width1", 1, false},
+		{"Width64", 64, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewCrc(CrcParams{Width: tt.width, Poly: 1})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewCrc() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestCrcNew(t *testing.T) {
+	h := mustNewCrc(Crc16Modbus()).New()
+	h.Write(crcCheckTest[:4])
+	h.Write(crcCheckTest[4:])
+	if got := h.Sum64(); got != Crc16Modbus().Check {
+		t.Errorf("Sum64() = %#x, want %#x", got, Crc16Modbus().Check)
+	}
+	if got := h.Sum(nil); !reflect.DeepEqual(got, []byte{0x4b, 0x37}) {
+		t.Errorf("Sum() = %v, want %v", got, []byte{0x4b, 0x37})
+	}
+	h.Reset()
+	if got := h.Sum64(); got != 0xffff {
+		t.Errorf("Sum64() after Reset = %#x, want %#x", got, 0xffff)
+	}
+}
+
+func TestCrcPreset(t *testing.T) {
+	if got, ok := CrcPreset("crc-16/modbus"); !ok || got != Crc16Modbus() {
+		t.Errorf("CrcPreset() = %v, %v, want %v", got, ok, Crc16Modbus())
+	}
+	if _, ok := CrcPreset("CRC-16/UNKNOWN"); ok {
+		t.Errorf("CrcPreset() found unknown preset")
+	}
+}
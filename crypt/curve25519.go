@@ -0,0 +1,226 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// X25519 is the Diffie-Hellman function on Curve25519, see RFC 7748.
+// The private key is 32 random bytes which are clamped(clear bit 0、1、2 and 255, set bit 254), the public key is
+// X25519(private, 9), and both sides get the same shared secret by X25519(own private, peer's public).
+// The scalar multiplication is the constant time Montgomery ladder on the u-coordinate. The field elements of
+// GF(2^255-19) are kept in five 51-bit limbs, the products are accumulated in 128 bits by bits.Mul64.
+
+const x25519Size = 32
+
+// x25519Basepoint the u-coordinate of the base point
+var x25519Basepoint = [x25519Size]byte{9}
+
+// fieldElement an element of GF(2^255-19), the value is l[0] + l[1]*2^51 + l[2]*2^102 + l[3]*2^153 + l[4]*2^204,
+// the limbs may exceed 51 bits slightly between the operations
+type fieldElement [5]uint64
+
+const maskLow51Bits = 1<<51 - 1
+
+// feFromBytes return the element of the 32-byte little-endian value, the bit 255 is ignored
+func feFromBytes(b *[x25519Size]byte) fieldElement {
+	w0 := binary.LittleEndian.Uint64(b[0:8])
+	w1 := binary.LittleEndian.Uint64(b[8:16])
+	w2 := binary.LittleEndian.Uint64(b[16:24])
+	w3 := binary.LittleEndian.Uint64(b[24:32])
+	return fieldElement{
+		w0 & maskLow51Bits,
+		(w0>>51 | w1<<13) & maskLow51Bits,
+		(w1>>38 | w2<<26) & maskLow51Bits,
+		(w2>>25 | w3<<39) & maskLow51Bits,
+		(w3 >> 12) & maskLow51Bits,
+	}
+}
+
+// feToBytes return the 32-byte little-endian value of the element reduced modulo 2^255-19
+func feToBytes(v fieldElement) [x25519Size]byte {
+	l := feCarry(v)
+	// c is 1 if l >= p, then l - p = l + 19 - 2^255
+	c := (l[0] + 19) >> 51
+	c = (l[1] + c) >> 51
+	c = (l[2] + c) >> 51
+	c = (l[3] + c) >> 51
+	c = (l[4] + c) >> 51
+	l[0] += 19 * c
+	l[1] += l[0] >> 51
+	l[0] &= maskLow51Bits
+	l[2] += l[1] >> 51
+	l[1] &= maskLow51Bits
+	l[3] += l[2] >> 51
+	l[2] &= maskLow51Bits
+	l[4] += l[3] >> 51
+	l[3] &= maskLow51Bits
+	l[4] &= maskLow51Bits
+
+	var b [x25519Size]byte
+	binary.LittleEndian.PutUint64(b[0:8], l[0]|l[1]<<51)
+	binary.LittleEndian.PutUint64(b[8:16], l[1]>>13|l[2]<<38)
+	binary.LittleEndian.PutUint64(b[16:24], l[2]>>26|l[3]<<25)
+	binary.LittleEndian.PutUint64(b[24:32], l[3]>>39|l[4]<<12)
+	return b
+}
+
+// feCarry reduce the limbs to 51 bits(l[0] may be slightly larger), 2^255 = 19 mod p
+func feCarry(v fieldElement) fieldElement {
+	c0, c1, c2, c3, c4 := v[0]>>51, v[1]>>51, v[2]>>51, v[3]>>51, v[4]>>51
+	return fieldElement{
+		v[0]&maskLow51Bits + c4*19,
+		v[1]&maskLow51Bits + c0,
+		v[2]&maskLow51Bits + c1,
+		v[3]&maskLow51Bits + c2,
+		v[4]&maskLow51Bits + c3,
+	}
+}
+
+// feAdd return a + b
+func feAdd(a, b fieldElement) fieldElement {
+	return feCarry(fieldElement{a[0] + b[0], a[1] + b[1], a[2] + b[2], a[3] + b[3], a[4] + b[4]})
+}
+
+// feSub return a - b, 2p is added to avoid the underflow
+func feSub(a, b fieldElement) fieldElement {
+	return feCarry(fieldElement{
+		a[0] + 0xFFFFFFFFFFFDA - b[0],
+		a[1] + 0xFFFFFFFFFFFFE - b[1],
+		a[2] + 0xFFFFFFFFFFFFE - b[2],
+		a[3] + 0xFFFFFFFFFFFFE - b[3],
+		a[4] + 0xFFFFFFFFFFFFE - b[4],
+	})
+}
+
+// uint128 a 128-bit accumulator
+type uint128 struct {
+	lo, hi uint64
+}
+
+// addMul64 return v + a*b
+func addMul64(v uint128, a, b uint64) uint128 {
+	hi, lo := bits.Mul64(a, b)
+	lo, c := bits.Add64(lo, v.lo, 0)
+	hi, _ = bits.Add64(hi, v.hi, c)
+	return uint128{lo, hi}
+}
+
+// shiftRightBy51 return v >> 51, the result must fit in 64 bits
+func shiftRightBy51(v uint128) uint64 {
+	return v.hi<<13 | v.lo>>51
+}
+
+// feMul return a * b
+func feMul(a, b fieldElement) fieldElement {
+	a0, a1, a2, a3, a4 := a[0], a[1], a[2], a[3], a[4]
+	b0, b1, b2, b3, b4 := b[0], b[1], b[2], b[3], b[4]
+	// the terms over 2^255 are folded by 2^255 = 19 mod p
+	b1x19, b2x19, b3x19, b4x19 := b1*19, b2*19, b3*19, b4*19
+
+	var r0, r1, r2, r3, r4 uint128
+	r0 = addMul64(r0, a0, b0)
+	r0 = addMul64(r0, a1, b4x19)
+	r0 = addMul64(r0, a2, b3x19)
+	r0 = addMul64(r0, a3, b2x19)
+	r0 = addMul64(r0, a4, b1x19)
+
+	r1 = addMul64(r1, a0, b1)
+	r1 = addMul64(r1, a1, b0)
+	r1 = addMul64(r1, a2, b4x19)
+	r1 = addMul64(r1, a3, b3x19)
+	r1 = addMul64(r1, a4, b2x19)
+
+	r2 = addMul64(r2, a0, b2)
+	r2 = addMul64(r2, a1, b1)
+	r2 = addMul64(r2, a2, b0)
+	r2 = addMul64(r2, a3, b4x19)
+	r2 = addMul64(r2, a4, b3x19)
+
+	r3 = addMul64(r3, a0, b3)
+	r3 = addMul64(r3, a1, b2)
+	r3 = addMul64(r3, a2, b1)
+	r3 = addMul64(r3, a3, b0)
+	r3 = addMul64(r3, a4, b4x19)
+
+	r4 = addMul64(r4, a0, b4)
+	r4 = addMul64(r4, a1, b3)
+	r4 = addMul64(r4, a2, b2)
+	r4 = addMul64(r4, a3, b1)
+	r4 = addMul64(r4, a4, b0)
+
+	c0, c1, c2, c3, c4 := shiftRightBy51(r0), shiftRightBy51(r1), shiftRightBy51(r2), shiftRightBy51(r3),
+		shiftRightBy51(r4)
+	return feCarry(fieldElement{
+		r0.lo&maskLow51Bits + c4*19,
+		r1.lo&maskLow51Bits + c0,
+		r2.lo&maskLow51Bits + c1,
+		r3.lo&maskLow51Bits + c2,
+		r4.lo&maskLow51Bits + c3,
+	})
+}
+
+// feInvert return 1/z = z^(p-2), the exponent is public so the loop is constant time
+func feInvert(z fieldElement) fieldElement {
+	// p-2 = 2^255 - 21, the bits from high to low are 250 ones, then 01011
+	r := fieldElement{1}
+	for i := 254; i >= 0; i-- {
+		r = feMul(r, r)
+		if i >= 5 || (0x0B>>uint(i))&1 == 1 {
+			r = feMul(r, z)
+		}
+	}
+	return r
+}
+
+// feSwap swap a and b if swap is 1, in constant time
+func feSwap(a, b *fieldElement, swap uint64) {
+	mask := -swap
+	for i := range a {
+		t := mask & (a[i] ^ b[i])
+		a[i] ^= t
+		b[i] ^= t
+	}
+}
+
+// x25519 return X25519(scalar, point), the scalar is clamped here
+func x25519(scalar, point *[x25519Size]byte) [x25519Size]byte {
+	var k [x25519Size]byte
+	copy(k[:], scalar[:])
+	k[0] &= 248
+	k[31] &= 127
+	k[31] |= 64
+
+	x1 := feFromBytes(point)
+	x2, z2 := fieldElement{1}, fieldElement{}
+	x3, z3 := x1, fieldElement{1}
+	a24 := fieldElement{121665}
+
+	var swap uint64
+	for t := 254; t >= 0; t-- {
+		kt := uint64(k[t>>3]>>uint(t&7)) & 1
+		swap ^= kt
+		feSwap(&x2, &x3, swap)
+		feSwap(&z2, &z3, swap)
+		swap = kt
+
+		a := feAdd(x2, z2)
+		aa := feMul(a, a)
+		b := feSub(x2, z2)
+		bb := feMul(b, b)
+		e := feSub(aa, bb)
+		c := feAdd(x3, z3)
+		d := feSub(x3, z3)
+		da := feMul(d, a)
+		cb := feMul(c, b)
+		x3 = feAdd(da, cb)
+		x3 = feMul(x3, x3)
+		z3 = feSub(da, cb)
+		z3 = feMul(x1, feMul(z3, z3))
+		x2 = feMul(aa, bb)
+		z2 = feMul(e, feAdd(aa, feMul(a24, e)))
+	}
+	feSwap(&x2, &x3, swap)
+	feSwap(&z2, &z3, swap)
+	return feToBytes(feMul(x2, feInvert(z2)))
+}
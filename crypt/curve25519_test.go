@@ -0,0 +1,70 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestX25519(t *testing.T) {
+	tests := []struct {
+		name   string
+		scalar string
+		point  string
+		want   string
+	}{
+		{
+			name:   "RFC7748_5.2_1",
+			scalar: "a546e36bf0527c9d3b16154b82465edd62144c0ac1fc5a18506a2244ba449ac4",
+			point:  "e6db6867583030db3594c1a424b15f7c726624ec26b3353b10a903a6d0ab1c4c",
+			want:   "c3da55379de9c6908e94ea4df28d084f32eccf03491c71f754b4075577a28552",
+		},
+		{
+			name:   "RFC7748_5.2_2",
+			scalar: "4b66e9d4d1b4673c5ad22691957d6af5c11b6421e0ea01d42ca4169e7918ba0d",
+			point:  "e5210f12786811d3f4b7959d0538ae2c31dbe7106fc03c3efc4cd549c715a493",
+			want:   "95cbde9476e8907d7aade45cb4b873f88b595a68799fa152e6f8f7647aac7957",
+		},
+		{
+			name:   "RFC7748_6.1_AlicePublic",
+			scalar: "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a",
+			point:  "0900000000000000000000000000000000000000000000000000000000000000",
+			want:   "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a",
+		},
+		{
+			name:   "RFC7748_6.1_Shared",
+			scalar: "5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb",
+			point:  "8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a",
+			want:   "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742",
+		},
+		{
+			name:   "SmallOrderPoint",
+			scalar: "77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a",
+			point:  "0000000000000000000000000000000000000000000000000000000000000000",
+			want:   "0000000000000000000000000000000000000000000000000000000000000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var scalar, point [x25519Size]byte
+			s, _ := hex.DecodeString(tt.scalar)
+			p, _ := hex.DecodeString(tt.point)
+			copy(scalar[:], s)
+			copy(point[:], p)
+			if got := x25519(&scalar, &point); hex.EncodeToString(got[:]) != tt.want {
+				t.Errorf("x25519() = %x, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestX25519Iterated(t *testing.T) {
+	// RFC 7748 section 5.2: k = X25519(k, u), u = the old k, starting from k = u = 9
+	k, u := x25519Basepoint, x25519Basepoint
+	for i := 0; i < 1000; i++ {
+		k, u = x25519(&k, &u), k
+	}
+	want := "684cf59ba83309552800ef566f2f4d3c1c3887c49360e3875f2eb94d99532c51"
+	if hex.EncodeToString(k[:]) != want {
+		t.Errorf("x25519() 1000 iterations = %x, want %v", k, want)
+	}
+}
@@ -0,0 +1,404 @@
+package crypt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"math/big"
+	"unicode/utf8"
+)
+
+// Format-preserving encryption(FPE) encrypts a string of characters from an alphabet into a string of the same length
+// and the same alphabet, such as: a 16-digit card number is encrypted into another 16-digit number, so the encrypted
+// values fit in the existing columns and pass the format validations. See NIST SP 800-38G Rev.1.
+// Both FF1 and FF3-1 are Feistel networks over the numeral strings of radix = len(alphabet), the round function is
+// based on AES:
+//  FF1:   10 rounds, the tweak is of any length, the round function is a CBC-MAC over the parameters and the tweak
+//  FF3-1: 8 rounds, the tweak is 56 bits(7 bytes), the round function is one AES block
+// The radix must be in [2,65536], and radix^len(text) must be at least 1000000 as SP 800-38G Rev.1 requires, such as:
+// at least 6 digits for radix 10. The maximum length of FF3-1 is 2*floor(log_radix(2^96)), such as 56 digits.
+// The same (key, tweak, text) always produces the same result, use different tweaks(such as the column name or the
+// bin of a card number) to make the equal values in different contexts encrypt differently.
+
+const (
+	FpeDigits       = "0123456789"                           // radix 10
+	FpeAlphanumeric = "0123456789abcdefghijklmnopqrstuvwxyz" // radix 36, the lowercase letters and digits
+
+	fpeMinDomain    = 1000000
+	fpeMaxRadix     = 1 << 16
+	ff1Rounds       = 10
+	ff31Rounds      = 8
+	ff31TweakSize   = 7
+	ff1MaxTweakSize = 1<<32 - 1
+)
+
+// fpeAlphabet the characters of a radix, the index of a character is its numeral
+type fpeAlphabet struct {
+	chars []rune
+	index map[rune]int
+}
+
+// newFpeAlphabet return the alphabet of the unique characters
+func newFpeAlphabet(alphabet string) (*fpeAlphabet, error) {
+	a := &fpeAlphabet{chars: []rune(alphabet), index: make(map[rune]int)}
+	if len(a.chars) < 2 || len(a.chars) > fpeMaxRadix {
+		return nil, errors.New(sErrFpeAlphabetInvalid)
+	}
+	for i, c := range a.chars {
+		if _, ok := a.index[c]; ok || c == utf8.RuneError {
+			return nil, errors.New(sErrFpeAlphabetInvalid)
+		}
+		a.index[c] = i
+	}
+	return a, nil
+}
+
+// radix return the radix of the alphabet
+func (a *fpeAlphabet) radix() int {
+	return len(a.chars)
+}
+
+// numerals return the numeral string of text, or an error if text contains a character out of the alphabet
+func (a *fpeAlphabet) numerals(text string) ([]uint16, error) {
+	res := make([]uint16, 0, len(text))
+	for _, c := range text {
+		i, ok := a.index[c]
+		if !ok {
+			return nil, errors.New(sErrFpeTextInvalid)
+		}
+		res = append(res, uint16(i))
+	}
+	return res, nil
+}
+
+// text return the string of the numerals
+func (a *fpeAlphabet) text(numerals []uint16) string {
+	res := make([]rune, len(numerals))
+	for i, x := range numerals {
+		res[i] = a.chars[x]
+	}
+	return string(res)
+}
+
+// fpeNum return NUM_radix(x), the number of the numeral string x with the most significant numeral first
+func fpeNum(x []uint16, radix *big.Int) *big.Int {
+	res := new(big.Int)
+	d := new(big.Int)
+	for _, v := range x {
+		res.Mul(res, radix)
+		res.Add(res, d.SetUint64(uint64(v)))
+	}
+	return res
+}
+
+// fpeStr return STR^m_radix(x), the m numerals of x with the most significant numeral first, x must be less than
+// radix^m, x is changed
+func fpeStr(x *big.Int, m int, radix *big.Int) []uint16 {
+	res := make([]uint16, m)
+	r := new(big.Int)
+	for i := m - 1; i >= 0; i-- {
+		x.DivMod(x, radix, r)
+		res[i] = uint16(r.Uint64())
+	}
+	return res
+}
+
+// fpeReverse return the numerals in the reverse order
+func fpeReverse(x []uint16) []uint16 {
+	res := make([]uint16, len(x))
+	for i, v := range x {
+		res[len(x)-1-i] = v
+	}
+	return res
+}
+
+// fpeMinLen return the minimum length that radix^minLen >= 1000000
+func fpeMinLen(radix int) int {
+	n, v := 0, 1
+	for v < fpeMinDomain {
+		v *= radix
+		n++
+	}
+	return n
+}
+
+// ------------------------------------------------ FF1 ------------------------------------------------
+
+// FF1 the FF1 format-preserving encryption of NIST SP 800-38G, it is safe for concurrent use
+type FF1 struct {
+	block    cipher.Block
+	alphabet *fpeAlphabet
+	tweak    []byte
+	minLen   int
+}
+
+// NewFF1 return a FF1 cipher with the AES key(16, 24 or 32 bytes), the default tweak(can be empty) and the alphabet,
+// radix = the number of the characters in alphabet, such as FpeDigits
+func NewFF1(key, tweak []byte, alphabet string) (*FF1, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	a, err := newFpeAlphabet(alphabet)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(tweak)) > ff1MaxTweakSize {
+		return nil, errors.New(sErrFpeTweakInvalid)
+	}
+	return &FF1{block: block, alphabet: a, tweak: append([]byte{}, tweak...), minLen: fpeMinLen(a.radix())}, nil
+}
+
+// Encrypt encrypt text with the default tweak, the result has the same length and alphabet
+func (f *FF1) Encrypt(text string) (string, error) {
+	return f.EncryptWithTweak(text, f.tweak)
+}
+
+// Decrypt decrypt text with the default tweak
+func (f *FF1) Decrypt(text string) (string, error) {
+	return f.DecryptWithTweak(text, f.tweak)
+}
+
+// EncryptWithTweak encrypt text with the tweak
+func (f *FF1) EncryptWithTweak(text string, tweak []byte) (string, error) {
+	return f.cipher(text, tweak, true)
+}
+
+// DecryptWithTweak decrypt text with the tweak
+func (f *FF1) DecryptWithTweak(text string, tweak []byte) (string, error) {
+	return f.cipher(text, tweak, false)
+}
+
+// cipher the algorithm 7(encrypt) and 8(decrypt) of SP 800-38G
+func (f *FF1) cipher(text string, tweak []byte, encrypt bool) (string, error) {
+	if uint64(len(tweak)) > ff1MaxTweakSize {
+		return "", errors.New(sErrFpeTweakInvalid)
+	}
+	x, err := f.alphabet.numerals(text)
+	if err != nil {
+		return "", err
+	}
+	n := len(x)
+	if n < f.minLen || uint64(n) > 1<<32-1 {
+		return "", errors.New(sErrFpeTextInvalid)
+	}
+
+	radix := f.alphabet.radix()
+	bigRadix := big.NewInt(int64(radix))
+	u := n / 2
+	v := n - u
+	a, b := x[:u], x[u:]
+
+	// b = ceil(ceil(v*log2(radix))/8), the bytes of radix^v - 1
+	bLen := (new(big.Int).Sub(new(big.Int).Exp(bigRadix, big.NewInt(int64(v)), nil), big.NewInt(1)).BitLen() + 7) / 8
+	d := 4*((bLen+3)/4) + 4
+
+	// P = [1]^1 || [2]^1 || [1]^1 || [radix]^3 || [10]^1 || [u mod 256]^1 || [n]^4 || [t]^4
+	p := make([]byte, aes.BlockSize)
+	p[0], p[1], p[2] = 1, 2, 1
+	p[3], p[4], p[5] = byte(radix>>16), byte(radix>>8), byte(radix)
+	p[6], p[7] = ff1Rounds, byte(u)
+	binary.BigEndian.PutUint32(p[8:12], uint32(n))
+	binary.BigEndian.PutUint32(p[12:16], uint32(len(tweak)))
+
+	// Q = T || [0]^((-t-b-1) mod 16) || [i]^1 || [NUM_radix(B)]^b
+	qLen := len(tweak) + bLen + 1
+	qLen += (16 - qLen%16) % 16
+	q := make([]byte, qLen)
+	copy(q, tweak)
+
+	// The CBC-MAC of P is the same in all rounds
+	var pMac [aes.BlockSize]byte
+	f.block.Encrypt(pMac[:], p)
+
+	modU := new(big.Int).Exp(bigRadix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(bigRadix, big.NewInt(int64(v)), nil)
+	s := make([]byte, (d+15)/16*16)
+	var r, blk [aes.BlockSize]byte
+	for j := 0; j < ff1Rounds; j++ {
+		i := j
+		if !encrypt {
+			i = ff1Rounds - 1 - j
+		}
+		// In the encryption B is the input of the round function, in the decryption it is A
+		in := b
+		if !encrypt {
+			in = a
+		}
+		q[qLen-bLen-1] = byte(i)
+		numIn := fpeNum(in, bigRadix).Bytes()
+		for k := qLen - bLen; k < qLen-len(numIn); k++ {
+			q[k] = 0
+		}
+		copy(q[qLen-len(numIn):], numIn)
+
+		// R = PRF(P || Q)
+		r = pMac
+		for k := 0; k < qLen; k += aes.BlockSize {
+			xorBytes(r[:], q[k:k+aes.BlockSize])
+			f.block.Encrypt(r[:], r[:])
+		}
+		// S = R || CIPH(R xor [1]^16) || CIPH(R xor [2]^16) ..., the first d bytes
+		copy(s, r[:])
+		for k := 1; k*aes.BlockSize < d; k++ {
+			blk = r
+			binary.BigEndian.PutUint64(blk[8:], binary.BigEndian.Uint64(blk[8:])^uint64(k))
+			f.block.Encrypt(s[k*aes.BlockSize:], blk[:])
+		}
+		y := new(big.Int).SetBytes(s[:d])
+
+		m, mod := u, modU
+		if i%2 == 1 {
+			m, mod = v, modV
+		}
+		if encrypt {
+			// c = (NUM_radix(A) + y) mod radix^m, A = B, B = C
+			c := y.Add(fpeNum(a, bigRadix), y)
+			a, b = b, fpeStr(c.Mod(c, mod), m, bigRadix)
+		} else {
+			// c = (NUM_radix(B) - y) mod radix^m, B = A, A = C
+			c := y.Sub(fpeNum(b, bigRadix), y)
+			a, b = fpeStr(c.Mod(c, mod), m, bigRadix), a
+		}
+	}
+	return f.alphabet.text(append(append(make([]uint16, 0, n), a...), b...)), nil
+}
+
+// ------------------------------------------------ FF3-1 ------------------------------------------------
+
+// FF31 the FF3-1 format-preserving encryption of NIST SP 800-38G Rev.1, it is safe for concurrent use
+type FF31 struct {
+	block    cipher.Block
+	alphabet *fpeAlphabet
+	tweak    []byte
+	minLen   int
+	maxLen   int
+}
+
+// NewFF31 return a FF3-1 cipher with the AES key(16, 24 or 32 bytes), the default tweak(7 bytes) and the alphabet,
+// radix = the number of the characters in alphabet, such as FpeDigits
+func NewFF31(key, tweak []byte, alphabet string) (*FF31, error) {
+	if len(tweak) != ff31TweakSize {
+		return nil, errors.New(sErrFpeTweakInvalid)
+	}
+	a, err := newFpeAlphabet(alphabet)
+	if err != nil {
+		return nil, err
+	}
+	// The AES key is byte-reversed in FF3-1
+	revKey := make([]byte, len(key))
+	for i := range key {
+		revKey[len(key)-1-i] = key[i]
+	}
+	block, err := aes.NewCipher(revKey)
+	if err != nil {
+		return nil, err
+	}
+
+	// maxLen = 2*floor(log_radix(2^96))
+	maxLen, limit, v := 0, new(big.Int).Lsh(big.NewInt(1), 96), big.NewInt(int64(a.radix()))
+	for ; v.Cmp(limit) <= 0; v.Mul(v, big.NewInt(int64(a.radix()))) {
+		maxLen++
+	}
+	return &FF31{block: block, alphabet: a, tweak: append([]byte{}, tweak...), minLen: fpeMinLen(a.radix()),
+		maxLen: 2 * maxLen}, nil
+}
+
+// Encrypt encrypt text with the default tweak, the result has the same length and alphabet
+func (f *FF31) Encrypt(text string) (string, error) {
+	return f.EncryptWithTweak(text, f.tweak)
+}
+
+// Decrypt decrypt text with the default tweak
+func (f *FF31) Decrypt(text string) (string, error) {
+	return f.DecryptWithTweak(text, f.tweak)
+}
+
+// EncryptWithTweak encrypt text with the 7-byte tweak
+func (f *FF31) EncryptWithTweak(text string, tweak []byte) (string, error) {
+	if len(tweak) != ff31TweakSize {
+		return "", errors.New(sErrFpeTweakInvalid)
+	}
+	tl, tr := ff31SplitTweak(tweak)
+	return f.cipher(text, tl, tr, true)
+}
+
+// DecryptWithTweak decrypt text with the 7-byte tweak
+func (f *FF31) DecryptWithTweak(text string, tweak []byte) (string, error) {
+	if len(tweak) != ff31TweakSize {
+		return "", errors.New(sErrFpeTweakInvalid)
+	}
+	tl, tr := ff31SplitTweak(tweak)
+	return f.cipher(text, tl, tr, false)
+}
+
+// ff31SplitTweak return T_L = T[0..27] || 0^4 and T_R = T[32..55] || T[28..31] || 0^4 of the 56-bit tweak
+func ff31SplitTweak(tweak []byte) ([4]byte, [4]byte) {
+	tl := [4]byte{tweak[0], tweak[1], tweak[2], tweak[3] & 0xF0}
+	tr := [4]byte{tweak[4], tweak[5], tweak[6], tweak[3] << 4}
+	return tl, tr
+}
+
+// cipher the algorithm 9(encrypt) and 10(decrypt) of SP 800-38G Rev.1 with the 32-bit tweak halves, they are the
+// same as FF3 with a 64-bit tweak
+func (f *FF31) cipher(text string, tl, tr [4]byte, encrypt bool) (string, error) {
+	x, err := f.alphabet.numerals(text)
+	if err != nil {
+		return "", err
+	}
+	n := len(x)
+	if n < f.minLen || n > f.maxLen {
+		return "", errors.New(sErrFpeTextInvalid)
+	}
+
+	bigRadix := big.NewInt(int64(f.alphabet.radix()))
+	v := n / 2
+	u := n - v
+	a, b := x[:u], x[u:]
+	modU := new(big.Int).Exp(bigRadix, big.NewInt(int64(u)), nil)
+	modV := new(big.Int).Exp(bigRadix, big.NewInt(int64(v)), nil)
+
+	var p, s [aes.BlockSize]byte
+	for j := 0; j < ff31Rounds; j++ {
+		i := j
+		if !encrypt {
+			i = ff31Rounds - 1 - j
+		}
+		m, mod, w := u, modU, tr
+		if i%2 == 1 {
+			m, mod, w = v, modV, tl
+		}
+		in := b
+		if !encrypt {
+			in = a
+		}
+
+		// P = W xor [i]^4 || [NUM_radix(REV(B))]^12, S = REVB(CIPH(REVB(P)))
+		binary.BigEndian.PutUint32(p[:4], binary.BigEndian.Uint32(w[:])^uint32(i))
+		num := fpeNum(fpeReverse(in), bigRadix).Bytes()
+		for k := 4; k < aes.BlockSize-len(num); k++ {
+			p[k] = 0
+		}
+		copy(p[aes.BlockSize-len(num):], num)
+		for k := 0; k < aes.BlockSize; k++ {
+			s[aes.BlockSize-1-k] = p[k]
+		}
+		f.block.Encrypt(s[:], s[:])
+		for k := 0; k < aes.BlockSize/2; k++ {
+			s[k], s[aes.BlockSize-1-k] = s[aes.BlockSize-1-k], s[k]
+		}
+		y := new(big.Int).SetBytes(s[:])
+
+		if encrypt {
+			// c = (NUM_radix(REV(A)) + y) mod radix^m, A = B, B = REV(STR(c))
+			c := y.Add(fpeNum(fpeReverse(a), bigRadix), y)
+			a, b = b, fpeReverse(fpeStr(c.Mod(c, mod), m, bigRadix))
+		} else {
+			// c = (NUM_radix(REV(B)) - y) mod radix^m, B = A, A = REV(STR(c))
+			c := y.Sub(fpeNum(fpeReverse(b), bigRadix), y)
+			a, b = fpeReverse(fpeStr(c.Mod(c, mod), m, bigRadix)), a
+		}
+	}
+	return f.alphabet.text(append(append(make([]uint16, 0, n), a...), b...)), nil
+}
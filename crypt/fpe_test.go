@@ -0,0 +1,173 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestFF1(t *testing.T) {
+	// NIST SP 800-38G FF1 samples
+	const (
+		key128 = "2B7E151628AED2A6ABF7158809CF4F3C"
+		key192 = "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F"
+		key256 = "2B7E151628AED2A6ABF7158809CF4F3CEF4359D8D580AA4F7F036D6F04FC6A94"
+	)
+	tests := []struct {
+		name      string
+		key       string
+		tweak     string
+		alphabet  string
+		plaintext string
+		want      string
+	}{
+		{"Sample1", key128, "", FpeDigits, "0123456789", "2433477484"},
+		{"Sample2", key128, "39383736353433323130", FpeDigits, "0123456789", "6124200773"},
+		{"Sample3", key128, "3737373770717273373737", FpeAlphanumeric, "0123456789abcdefghi", "a9tv40mll9kdu509eum"},
+		{"Sample4", key192, "", FpeDigits, "0123456789", "2830668132"},
+		{"Sample5", key192, "39383736353433323130", FpeDigits, "0123456789", "2496655549"},
+		{"Sample6", key192, "3737373770717273373737", FpeAlphanumeric, "0123456789abcdefghi", "xbj3kv35jrawxv32ysr"},
+		{"Sample7", key256, "", FpeDigits, "0123456789", "6657667009"},
+		{"Sample8", key256, "39383736353433323130", FpeDigits, "0123456789", "1001623463"},
+		{"Sample9", key256, "3737373770717273373737", FpeAlphanumeric, "0123456789abcdefghi", "xs8a0azh2avyalyzuwd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _ := hex.DecodeString(tt.key)
+			tweak, _ := hex.DecodeString(tt.tweak)
+			f, err := NewFF1(key, tweak, tt.alphabet)
+			if err != nil {
+				t.Fatalf("NewFF1() error = %v", err)
+			}
+			got, err := f.Encrypt(tt.plaintext)
+			if err != nil || got != tt.want {
+				t.Errorf("Encrypt() = %v, %v, want %v", got, err, tt.want)
+			}
+			got, err = f.Decrypt(tt.want)
+			if err != nil || got != tt.plaintext {
+				t.Errorf("Decrypt() = %v, %v, want %v", got, err, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestFF3(t *testing.T) {
+	// NIST FF3 samples with the 64-bit tweak, FF3-1 only changes how the 32-bit tweak halves are built
+	const key128 = "EF4359D8D580AA4F7F036D6F04FC6A94"
+	tests := []struct {
+		name      string
+		key       string
+		tweak     string
+		alphabet  string
+		plaintext string
+		want      string
+	}{
+		{"Sample1", key128, "D8E7920AFA330A73", FpeDigits, "890121234567890000", "750918814058654607"},
+		{"Sample2", key128, "9A768A92F60E12D8", FpeDigits, "890121234567890000", "018989839189395384"},
+		{"Sample3", key128, "D8E7920AFA330A73", FpeDigits, "89012123456789000000789000000",
+			"48598367162252569629397416226"},
+		{"Sample4", key128, "0000000000000000", FpeDigits, "89012123456789000000789000000",
+			"34695224821734535122613701434"},
+		{"Sample5", key128, "9A768A92F60E12D8", FpeAlphanumeric[:26], "0123456789abcdefghi", "g2pk40i992fn20cjakb"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			key, _ := hex.DecodeString(tt.key)
+			tweak, _ := hex.DecodeString(tt.tweak)
+			f, err := NewFF31(key, tweak[:ff31TweakSize], tt.alphabet)
+			if err != nil {
+				t.Fatalf("NewFF31() error = %v", err)
+			}
+			var tl, tr [4]byte
+			copy(tl[:], tweak[:4])
+			copy(tr[:], tweak[4:])
+			got, err := f.cipher(tt.plaintext, tl, tr, true)
+			if err != nil || got != tt.want {
+				t.Errorf("cipher() encrypt = %v, %v, want %v", got, err, tt.want)
+			}
+			got, err = f.cipher(tt.want, tl, tr, false)
+			if err != nil || got != tt.plaintext {
+				t.Errorf("cipher() decrypt = %v, %v, want %v", got, err, tt.plaintext)
+			}
+		})
+	}
+}
+
+func TestFF31(t *testing.T) {
+	tweak, _ := hex.DecodeString("D8E7920AFA330A")
+	tl, tr := ff31SplitTweak(tweak)
+	if tl != [4]byte{0xD8, 0xE7, 0x92, 0x00} || tr != [4]byte{0xFA, 0x33, 0x0A, 0xA0} {
+		t.Errorf("ff31SplitTweak() = %x, %x, want d8e79200, fa330aa0", tl, tr)
+	}
+
+	key, _ := hex.DecodeString("EF4359D8D580AA4F7F036D6F04FC6A94")
+	tests := []struct {
+		name      string
+		alphabet  string
+		plaintext string
+		wantErr   bool
+	}{
+		{name: "CardNumber", alphabet: FpeDigits, plaintext: "4111111111111111"},
+		{name: "MinLength", alphabet: FpeDigits, plaintext: "123456"},
+		{name: "MaxLength", alphabet: FpeDigits, plaintext: "12345678901234567890123456789012345678901234567890123456"},
+		{name: "Alphanumeric", alphabet: FpeAlphanumeric, plaintext: "user0001"},
+		{name: "Unicode", alphabet: "零一二三四五六七八九", plaintext: "一三八零零一三八零零零"},
+		{name: "TooShort", alphabet: FpeDigits, plaintext: "12345", wantErr: true},
+		{name: "TooLong", alphabet: FpeDigits, plaintext: "123456789012345678901234567890123456789012345678901234567",
+			wantErr: true},
+		{name: "CharInvalid", alphabet: FpeDigits, plaintext: "1234-5678", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := NewFF31(key, tweak, tt.alphabet)
+			if err != nil {
+				t.Fatalf("NewFF31() error = %v", err)
+			}
+			got, err := f.Encrypt(tt.plaintext)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Encrypt() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len([]rune(got)) != len([]rune(tt.plaintext)) || got == tt.plaintext {
+				t.Errorf("Encrypt() = %v, want the same length and different from %v", got, tt.plaintext)
+			}
+			if _, err = f.alphabet.numerals(got); err != nil {
+				t.Errorf("Encrypt() = %v, want the characters of the alphabet", got)
+			}
+			if dec, err := f.Decrypt(got); err != nil || dec != tt.plaintext {
+				t.Errorf("Decrypt() = %v, %v, want %v", dec, err, tt.plaintext)
+			}
+			other, _ := f.EncryptWithTweak(tt.plaintext, []byte("1234567"))
+			if other == got {
+				t.Errorf("EncryptWithTweak() with other tweak = %v, want different", other)
+			}
+		})
+	}
+}
+
+func TestNewFPEInvalid(t *testing.T) {
+	key := commonKey16
+	if _, err := NewFF1(key, nil, "0"); err == nil {
+		t.Errorf("NewFF1() alphabet of 1 character error = nil, want error")
+	}
+	if _, err := NewFF1(key, nil, "0120"); err == nil {
+		t.Errorf("NewFF1() duplicate characters error = nil, want error")
+	}
+	if _, err := NewFF1(invalidKey, nil, FpeDigits); err == nil {
+		t.Errorf("NewFF1() invalid key error = nil, want error")
+	}
+	if _, err := NewFF31(key, []byte("12345678"), FpeDigits); err == nil {
+		t.Errorf("NewFF31() 8-byte tweak error = nil, want error")
+	}
+	if _, err := NewFF31(invalidKey, []byte("1234567"), FpeDigits); err == nil {
+		t.Errorf("NewFF31() invalid key error = nil, want error")
+	}
+	f, _ := NewFF1(key, nil, FpeDigits)
+	if _, err := f.Encrypt("12345"); err == nil {
+		t.Errorf("FF1 Encrypt() too short error = nil, want error")
+	}
+	if _, err := f.Decrypt("12a456"); err == nil {
+		t.Errorf("FF1 Decrypt() invalid character error = nil, want error")
+	}
+}
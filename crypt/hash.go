@@ -4,9 +4,8 @@ import (
 	"crypto/hmac"
 	"crypto/md5"
 	"crypto/sha1"
-	"crypto/sha256"
-	"crypto/sha512"
 	"encoding/hex"
+	"errors"
 	"hash"
 	"hash/adler32"
 	"hash/crc32"
@@ -35,73 +34,49 @@ func Md5File(path string) string {
 
 // HashBytes return the checksum raw buffer of the specified hash algorithm
 // ht: md5(16bytes) 、sha1(20bytes)、sha224(28bytes)、sha256(32bytes)、sha384(48bytes)、sha512(64bytes)
+// The checksum of crc16 series is 2 bytes in big-endian byte order
+// The unsupported ht return empty slice, use HashBytesE if you need to know the reason
 func HashBytes(data []byte, ht HashType) []byte {
-	var h hash.Hash
-	switch ht {
-	case HtMD5:
-		h = md5.New()
-	case HtSha1:
-		h = sha1.New()
-	case HtSha224:
-		h = sha256.New224()
-	case HtSha256:
-		h = sha256.New()
-	case HtSha384:
-		h = sha512.New384()
-	case HtSha512:
-		h = sha512.New()
-	case HtFnv32:
-		h = fnv.New32()
-	case HtFnvA32:
-		h = fnv.New32a()
-	case HtFnv64:
-		h = fnv.New64()
-	case HtFnvA64:
-		h = fnv.New64a()
-	case HtFnv128:
-		h = fnv.New128()
-	case HtFnvA128:
-		h = fnv.New128a()
-	case HtCrc32:
-		h = crc32.NewIEEE()
-	case HtAdler32:
-		h = adler32.New()
-	case HtCrc64ISO:
-		h = crc64.New(crc64.MakeTable(crc64.ISO))
-	case HtCrc64ECMA:
-		h = crc64.New(crc64.MakeTable(crc64.ECMA))
-	case HtTime33:
-		return toBytes(Time33(data))
-	default:
+	b, err := HashBytesE(data, ht)
+	if err != nil {
 		return []byte{}
 	}
+	return b
+}
+
+// HashBytesE like HashBytes, but return an error if ht is not registered
+func HashBytesE(data []byte, ht HashType) ([]byte, error) {
+	h, err := newHash(ht)
+	if err != nil {
+		return nil, err
+	}
 	h.Write(data)
-	return h.Sum(nil)
+	return h.Sum(nil), nil
 }
 
 // HmacBytes return the authentication code raw buffer of the specified hash algorithm.
-// ht only support HtMD5、HtSha1、HtSha224、HtSha384、HtSha512
+// ht only support HtMD5、HtSha1、HtSha224、HtSha384、HtSha512 and the hash algorithms registered by RegisterHash
+// The unsupported ht return empty slice, use HmacBytesE if you need to know the reason
 func HmacBytes(data, key []byte, ht HashType) []byte {
-	var h hash.Hash
-
-	switch ht {
-	case HtMD5:
-		h = hmac.New(md5.New, key)
-	case HtSha1:
-		h = hmac.New(sha1.New, key)
-	case HtSha224:
-		h = hmac.New(sha256.New224, key)
-	case HtSha256:
-		h = hmac.New(sha256.New, key)
-	case HtSha384:
-		h = hmac.New(sha512.New384, key)
-	case HtSha512:
-		h = hmac.New(sha512.New, key)
-	default:
+	b, err := HmacBytesE(data, key, ht)
+	if err != nil {
 		return []byte{}
 	}
+	return b
+}
+
+// HmacBytesE like HmacBytes, but return an error if ht is not supported
+func HmacBytesE(data, key []byte, ht HashType) ([]byte, error) {
+	e, err := getHashEntry(ht)
+	if err != nil {
+		return nil, err
+	}
+	if !e.hmac {
+		return nil, errors.New(sErrHashTypeInvalid)
+	}
+	h := hmac.New(e.fn, key)
 	h.Write(data)
-	return h.Sum(nil)
+	return h.Sum(nil), nil
 }
 
 // ToHexString convert bytes to hexadecimal string
@@ -174,51 +149,123 @@ func Time33(data []byte) uint32 {
 }
 
 // HashUInt32 return a hash value of uint32 type through a specific hash function
-// ht only support HtFnv32、HtFnvA32、 HtAdler32、HtCrc32、HtTime33
+// ht only support HtFnv32、HtFnvA32、 HtAdler32、HtCrc32、HtTime33、HtCrc32C、HtCrc16Modbus、HtCrc16CCITT、HtCrc16XModem
+// and the hash algorithms registered by RegisterHash which implement hash.Hash32
+// The unsupported ht return 0, use HashUInt32E if you need to know the reason
 func HashUInt32(data []byte, ht HashType) uint32 {
+	v, _ := HashUInt32E(data, ht)
+	return v
+}
+
+// HashUInt32E like HashUInt32, but return an error if ht is not supported
+func HashUInt32E(data []byte, ht HashType) (uint32, error) {
 	switch ht {
 	case HtFnv32:
 		h := fnv.New32()
 		h.Write(data)
-		return h.Sum32()
+		return h.Sum32(), nil
 	case HtFnvA32:
 		h := fnv.New32a()
 		h.Write(data)
-		return h.Sum32()
+		return h.Sum32(), nil
 	case HtAdler32:
-		return adler32.Checksum(data)
+		return adler32.Checksum(data), nil
 	case HtCrc32:
-		return crc32.ChecksumIEEE(data)
+		return crc32.ChecksumIEEE(data), nil
 	case HtTime33:
-		return Time33(data)
-	default:
-		return 0
+		return Time33(data), nil
+	case HtCrc32C:
+		return crc32.Checksum(data, crc32.MakeTable(crc32.Castagnoli)), nil
+	case HtCrc16Modbus:
+		return uint32(crc16ModbusEngine.Checksum(data)), nil
+	case HtCrc16CCITT:
+		return uint32(crc16CCITTEngine.Checksum(data)), nil
+	case HtCrc16XModem:
+		return uint32(crc16XModemEngine.Checksum(data)), nil
+	}
+
+	h, err := newHash(ht)
+	if err != nil {
+		return 0, err
+	}
+	h32, ok := h.(hash.Hash32)
+	if !ok {
+		return 0, errors.New(sErrHashTypeInvalid)
 	}
+	h32.Write(data)
+	return h32.Sum32(), nil
 }
 
 // HashUInt64 return a hash value of uint64 type through a specific hash function
-// ht only support HtFnv32、HtFnvA32、 HtAdler32、HtCrc32、HtTime33、HtFnv64、HtFnvA64、HtCrc64ISO、HtCrc64ECMA
+// ht only support HtFnv32、HtFnvA32、 HtAdler32、HtCrc32、HtTime33、HtCrc32C、HtCrc16Modbus、HtCrc16CCITT、
+// HtCrc16XModem、HtFnv64、HtFnvA64、HtCrc64ISO、HtCrc64ECMA and the hash algorithms registered by RegisterHash
+// which implement hash.Hash32 or hash.Hash64
+// The unsupported ht return 0, use HashUInt64E if you need to know the reason
 func HashUInt64(data []byte, ht HashType) uint64 {
+	v, _ := HashUInt64E(data, ht)
+	return v
+}
+
+// HashUInt64E like HashUInt64, but return an error if ht is not supported
+func HashUInt64E(data []byte, ht HashType) (uint64, error) {
 	switch ht {
-	case HtFnv32, HtFnvA32, HtAdler32, HtCrc32, HtTime33:
-		return uint64(HashUInt32(data, ht))
+	case HtFnv32, HtFnvA32, HtAdler32, HtCrc32, HtTime33, HtCrc32C, HtCrc16Modbus, HtCrc16CCITT, HtCrc16XModem:
+		v, err := HashUInt32E(data, ht)
+		return uint64(v), err
 	case HtFnv64:
 		h := fnv.New64()
 		h.Write(data)
-		return h.Sum64()
+		return h.Sum64(), nil
 	case HtFnvA64:
 		h := fnv.New64a()
 		h.Write(data)
-		return h.Sum64()
+		return h.Sum64(), nil
 	case HtCrc64ISO:
 		crc64t := crc64.MakeTable(crc64.ISO)
-		return crc64.Checksum(data, crc64t)
+		return crc64.Checksum(data, crc64t), nil
 	case HtCrc64ECMA:
 		crc64t := crc64.MakeTable(crc64.ECMA)
-		return crc64.Checksum(data, crc64t)
+		return crc64.Checksum(data, crc64t), nil
+	}
+
+	h, err := newHash(ht)
+	if err != nil {
+		return 0, err
+	}
+	switch hh := h.(type) {
+	case hash.Hash64:
+		hh.Write(data)
+		return hh.Sum64(), nil
+	case hash.Hash32:
+		hh.Write(data)
+		return uint64(hh.Sum32()), nil
 	default:
-		return 0
+		return 0, errors.New(sErrHashTypeInvalid)
+	}
+}
+
+// hashSum64 return a uint64 hash value of data through ht, the hash algorithms which can not be used by HashUInt64
+// take the first 8 bytes of the checksum in big-endian byte order
+func hashSum64(data []byte, ht HashType) (uint64, error) {
+	if v, err := HashUInt64E(data, ht); err == nil {
+		return v, nil
 	}
+	b, err := HashBytesE(data, ht)
+	if err != nil {
+		return 0, err
+	}
+	var v uint64
+	for i := 0; i < len(b) && i < 8; i++ {
+		v = v<<8 | uint64(b[i])
+	}
+	return v, nil
+}
+
+// mix64 the finalizer of splitmix64, it spreads the bits of a weak hash value evenly
+func mix64(z uint64) uint64 {
+	z = (z ^ (z >> 30)) * 0xbf58476d1ce4e5b9
+	z = (z ^ (z >> 27)) * 0x94d049bb133111eb
+	return z ^ (z >> 31)
 }
 
 // uint32 convert uint32 to byte slice
@@ -0,0 +1,160 @@
+package crypt
+
+import (
+	"crypto/md5"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"errors"
+	"hash"
+	"hash/adler32"
+	"hash/crc32"
+	"hash/crc64"
+	"hash/fnv"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// hashEntry a hash algorithm in the registry
+// hmac: whether the algorithm can be used by HmacBytes, the non-cryptographic hash algorithms are not allowed
+type hashEntry struct {
+	name string
+	fn   func() hash.Hash
+	hmac bool
+}
+
+var (
+	hashMu       sync.RWMutex
+	hashRegistry = map[HashType]hashEntry{
+		HtMD5:         {"md5", md5.New, true},
+		HtSha1:        {"sha1", sha1.New, true},
+		HtSha224:      {"sha224", sha256.New224, true},
+		HtSha256:      {"sha256", sha256.New, true},
+		HtSha384:      {"sha384", sha512.New384, true},
+		HtSha512:      {"sha512", sha512.New, true},
+		HtFnv32:       {"fnv32", func() hash.Hash { return fnv.New32() }, false},
+		HtFnvA32:      {"fnv32a", func() hash.Hash { return fnv.New32a() }, false},
+		HtFnv64:       {"fnv64", func() hash.Hash { return fnv.New64() }, false},
+		HtFnvA64:      {"fnv64a", func() hash.Hash { return fnv.New64a() }, false},
+		HtFnv128:      {"fnv128", fnv.New128, false},
+		HtFnvA128:     {"fnv128a", fnv.New128a, false},
+		HtTime33:      {"time33", func() hash.Hash { return newTime33() }, false},
+		HtAdler32:     {"adler32", func() hash.Hash { return adler32.New() }, false},
+		HtCrc32:       {"crc32", func() hash.Hash { return crc32.NewIEEE() }, false},
+		HtCrc64ISO:    {"crc64iso", func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ISO)) }, false},
+		HtCrc64ECMA:   {"crc64ecma", func() hash.Hash { return crc64.New(crc64.MakeTable(crc64.ECMA)) }, false},
+		HtCrc32C:      {"crc32c", func() hash.Hash { return crc32.New(crc32.MakeTable(crc32.Castagnoli)) }, false},
+		HtCrc16Modbus: {"crc16modbus", func() hash.Hash { return crc16ModbusEngine.New() }, false},
+		HtCrc16CCITT:  {"crc16ccitt", func() hash.Hash { return crc16CCITTEngine.New() }, false},
+		HtCrc16XModem: {"crc16xmodem", func() hash.Hash { return crc16XModemEngine.New() }, false},
+	}
+)
+
+// RegisterHash register a hash constructor under ht, after that ht can be used by HashBytes、HashUInt32
+// (hash.Hash32 only)、HashUInt64(hash.Hash32 or hash.Hash64) and ParseHashType, and by HmacBytes if hmac is true.
+// hmac must be false for the non-cryptographic hash algorithms, such as CRC and FNV.
+// ht must be greater than HtUser, ht and name must not be registered, the name is case-insensitive and '-'、'_' are
+// ignored, such as: "sha3-256" and "SHA3_256" are the same name
+func RegisterHash(ht HashType, name string, fn func() hash.Hash, hmac bool) error {
+	if ht <= HtUser || fn == nil || normalizeHashName(name) == "" {
+		return errors.New(sErrHashParamInvalid)
+	}
+
+	hashMu.Lock()
+	defer hashMu.Unlock()
+	if _, ok := hashRegistry[ht]; ok {
+		return errors.New(sErrHashRegistered)
+	}
+	if _, ok := lookupHashName(name); ok {
+		return errors.New(sErrHashRegistered)
+	}
+	hashRegistry[ht] = hashEntry{name: name, fn: fn, hmac: hmac}
+	return nil
+}
+
+// ParseHashType return the HashType of the name, such as: "sha256" -> HtSha256
+// The name is case-insensitive and '-'、'_' are ignored, such as: "SHA-256" is also parsed as HtSha256
+func ParseHashType(name string) (HashType, error) {
+	hashMu.RLock()
+	defer hashMu.RUnlock()
+	if ht, ok := lookupHashName(name); ok {
+		return ht, nil
+	}
+	return 0, errors.New(sErrHashTypeInvalid)
+}
+
+// String return the name of the HashType, such as: HtSha256 -> "sha256"
+// The unregistered HashType return "HashType(n)"
+func (ht HashType) String() string {
+	hashMu.RLock()
+	defer hashMu.RUnlock()
+	if e, ok := hashRegistry[ht]; ok {
+		return e.name
+	}
+	return "HashType(" + strconv.Itoa(int(ht)) + ")"
+}
+
+// lookupHashName return the HashType of the name, the caller must hold hashMu
+func lookupHashName(name string) (HashType, bool) {
+	name = normalizeHashName(name)
+	for ht, e := range hashRegistry {
+		if normalizeHashName(e.name) == name {
+			return ht, true
+		}
+	}
+	return 0, false
+}
+
+// normalizeHashName convert the name to lowercase and remove '-'、'_'
+func normalizeHashName(name string) string {
+	return strings.NewReplacer("-", "", "_", "").Replace(strings.ToLower(strings.TrimSpace(name)))
+}
+
+// newHash return a new hash.Hash of ht
+func newHash(ht HashType) (hash.Hash, error) {
+	e, err := getHashEntry(ht)
+	if err != nil {
+		return nil, err
+	}
+	return e.fn(), nil
+}
+
+// getHashEntry return the registered entry of ht
+func getHashEntry(ht HashType) (hashEntry, error) {
+	hashMu.RLock()
+	e, ok := hashRegistry[ht]
+	hashMu.RUnlock()
+	if !ok {
+		return hashEntry{}, errors.New(sErrHashTypeInvalid)
+	}
+	return e, nil
+}
+
+// time33 implement hash.Hash32 for Time33
+type time33 struct {
+	h uint32
+}
+
+func newTime33() hash.Hash32 {
+	d := &time33{}
+	d.Reset()
+	return d
+}
+
+func (d *time33) Size() int { return 4 }
+
+func (d *time33) BlockSize() int { return 1 }
+
+func (d *time33) Reset() { d.h = 5381 }
+
+func (d *time33) Write(p []byte) (int, error) {
+	for _, b := range p {
+		d.h += ((d.h << 5) & 0x7FFFFFFF) + uint32(b)
+	}
+	return len(p), nil
+}
+
+func (d *time33) Sum32() uint32 { return d.h & 0x7FFFFFFF }
+
+func (d *time33) Sum(in []byte) []byte { return append(in, toBytes(d.Sum32())...) }
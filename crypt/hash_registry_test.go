@@ -0,0 +1,143 @@
+package crypt
+
+import (
+	"crypto/sha512"
+	"hash"
+	"hash/crc32"
+	"reflect"
+	"testing"
+)
+
+const (
+	htTestSha512T256 = HtUser + 1
+	htTestKoopman    = HtUser + 2
+)
+
+func init() {
+	if err := RegisterHash(htTestSha512T256, "sha512-256", sha512.New512_256, true); err != nil {
+		panic(err)
+	}
+	koopman := crc32.MakeTable(crc32.Koopman)
+	if err := RegisterHash(htTestKoopman, "crc32koopman", func() hash.Hash { return crc32.New(koopman) }, false); err != nil {
+		panic(err)
+	}
+}
+
+func TestRegisterHash(t *testing.T) {
+	type args struct {
+		ht   HashType
+		name string
+		fn   func() hash.Hash
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{"BuiltinType", args{HtSha256, "my-sha256", sha512.New}, true},
+		{"RegisteredType", args{htTestSha512T256, "my-sha512", sha512.New}, true},
+		{"RegisteredName", args{HtUser + 100, "SHA_256", sha512.New}, true},
+		{"EmptyName", args{HtUser + 100, " ", sha512.New}, true},
+		{"NilFunc", args{HtUser + 100, "my-sha512", nil}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := RegisterHash(tt.args.ht, tt.args.name, tt.args.fn, true); (err != nil) != tt.wantErr {
+				t.Errorf("RegisterHash() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestParseHashType(t *testing.T) {
+	tests := []struct {
+		name    string
+		want    HashType
+		wantErr bool
+	}{
+		{"sha256", HtSha256, false},
+		{"SHA-256", HtSha256, false},
+		{"fnv32a", HtFnvA32, false},
+		{"CRC16_MODBUS", HtCrc16Modbus, false},
+		{"sha512_256", htTestSha512T256, false},
+		{"sha3-256", 0, true},
+		{"", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseHashType(tt.name)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseHashType() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("ParseHashType() = %d, want %d", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashType_String(t *testing.T) {
+	tests := []struct {
+		ht   HashType
+		want string
+	}{
+		{HtMD5, "md5"},
+		{HtSha256, "sha256"},
+		{HtCrc64ECMA, "crc64ecma"},
+		{htTestSha512T256, "sha512-256"},
+		{0, "HashType(0)"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.want, func(t *testing.T) {
+			if got := tt.ht.String(); got != tt.want {
+				t.Errorf("String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashBytesE(t *testing.T) {
+	want := sha512.Sum512_256(hashCommonTest)
+	got, err := HashBytesE(hashCommonTest, htTestSha512T256)
+	if err != nil || !reflect.DeepEqual(got, want[:]) {
+		t.Errorf("HashBytesE() = %v, %v, want %v", got, err, want)
+	}
+	if _, err = HashBytesE(hashCommonTest, 0); err == nil {
+		t.Errorf("HashBytesE() expect error for unsupported type")
+	}
+}
+
+func TestHmacBytesE(t *testing.T) {
+	if _, err := HmacBytesE(hashCommonTest, hashKeyTest, HtSha256); err != nil {
+		t.Errorf("HmacBytesE() error = %v", err)
+	}
+	if got, err := HmacBytesE(hashCommonTest, hashKeyTest, htTestSha512T256); err != nil || len(got) != 32 {
+		t.Errorf("HmacBytesE() = %v, %v", got, err)
+	}
+	if _, err := HmacBytesE(hashCommonTest, hashKeyTest, HtFnv32); err == nil {
+		t.Errorf("HmacBytesE() expect error for non-cryptographic hash")
+	}
+	if _, err := HmacBytesE(hashCommonTest, hashKeyTest, htTestKoopman); err == nil {
+		t.Errorf("HmacBytesE() expect error for registered non-cryptographic hash")
+	}
+}
+
+func TestHashUIntE(t *testing.T) {
+	koopman := crc32.Checksum(hashCommonTest, crc32.MakeTable(crc32.Koopman))
+	if got, err := HashUInt32E(hashCommonTest, htTestKoopman); err != nil || got != koopman {
+		t.Errorf("HashUInt32E() = %v, %v, want %v", got, err, koopman)
+	}
+	if got, err := HashUInt64E(hashCommonTest, htTestKoopman); err != nil || got != uint64(koopman) {
+		t.Errorf("HashUInt64E() = %v, %v, want %v", got, err, koopman)
+	}
+	if got, err := HashUInt32E(hashCommonTest, HtFnvA64); err == nil {
+		t.Errorf("HashUInt32E() = %v, expect error", got)
+	}
+	if got, err := HashUInt64E(hashCommonTest, htTestSha512T256); err == nil {
+		t.Errorf("HashUInt64E() = %v, expect error", got)
+	}
+	if got, err := HashUInt64E(hashCommonTest, HtCrc16CCITT); err != nil || got != 16392 {
+		t.Errorf("HashUInt64E() = %v, %v, want %v", got, err, 16392)
+	}
+}
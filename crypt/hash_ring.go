@@ -0,0 +1,183 @@
+package crypt
+
+import (
+	"crypto/md5"
+	"math"
+	"sort"
+	"strconv"
+	"sync"
+)
+
+// Consistent hashing maps the nodes and the keys onto a ring, a key belongs to the first node found clockwise from
+// the position of the key. Every node is mapped onto the ring many times(virtual nodes) to balance the load, the more
+// weight the node has, the more virtual nodes it gets. When a node is added or removed, only the keys between it and
+// its predecessor are remapped.
+//
+// The name of the i-th virtual node is "node-i", e.g. "10.0.0.1:11211-0".
+// In Ketama mode, the placement is compatible with libketama which is used by most memcached clients:
+// every node gets floor(weight/totalWeight * 40 * nodeCount) md5 digests and every digest provides 4 points on the
+// ring, so 160 points per node with equal weights; the position of a key is the first 4 bytes of md5(key) in
+// little-endian byte order.
+
+// defaultRingReplicas the default number of virtual nodes per unit of weight
+const defaultRingReplicas = 160
+
+// HashRing consistent hash ring with weighted virtual nodes, it is safe for concurrent use
+type HashRing struct {
+	mu       sync.RWMutex
+	ht       HashType
+	replicas int
+	ketama   bool
+	weights  map[string]int
+	points   []ringPoint
+}
+
+// ringPoint a virtual node on the ring
+type ringPoint struct {
+	hash uint64
+	node string
+}
+
+// NewHashRing return a consistent hash ring which uses ht to place the virtual nodes and keys
+// replicas: The number of virtual nodes per unit of weight, if it is less than or equal to 0, 160 is used
+func NewHashRing(ht HashType, replicas int) (*HashRing, error) {
+	if _, err := hashSum64(nil, ht); err != nil {
+		return nil, err
+	}
+	if replicas <= 0 {
+		replicas = defaultRingReplicas
+	}
+	return &HashRing{ht: ht, replicas: replicas, weights: make(map[string]int)}, nil
+}
+
+// NewKetamaRing return a consistent hash ring whose placement is compatible with libketama
+func NewKetamaRing() *HashRing {
+	return &HashRing{ht: HtMD5, replicas: defaultRingReplicas, ketama: true, weights: make(map[string]int)}
+}
+
+// Add add a node with weight to the ring, if the node already exists, its weight is updated
+// If weight is less than or equal to 0, the default value of 1 is used
+func (r *HashRing) Add(node string, weight int) {
+	if weight <= 0 {
+		weight = 1
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.weights[node] = weight
+	r.build()
+}
+
+// Remove remove a node from the ring
+func (r *HashRing) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.weights[node]; !ok {
+		return
+	}
+	delete(r.weights, node)
+	r.build()
+}
+
+// Nodes return all nodes of the ring in ascending order
+func (r *HashRing) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.weights))
+	for node := range r.weights {
+		nodes = append(nodes, node)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Get return the node which the key belongs to, if the ring is empty, return ""
+func (r *HashRing) Get(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 {
+		return ""
+	}
+	return r.points[r.search(key)].node
+}
+
+// GetN return at most n distinct nodes found clockwise from the position of the key, the first one is the same as
+// Get. It is usually used to select the replicas of the key
+func (r *HashRing) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if len(r.points) == 0 || n <= 0 {
+		return nil
+	}
+	if n > len(r.weights) {
+		n = len(r.weights)
+	}
+
+	nodes := make([]string, 0, n)
+	seen := make(map[string]struct{}, n)
+	for i, idx := 0, r.search(key); i < len(r.points) && len(nodes) < n; i++ {
+		node := r.points[(idx+i)%len(r.points)].node
+		if _, ok := seen[node]; !ok {
+			seen[node] = struct{}{}
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
+// search return the index of the first point whose hash is greater than or equal to the position of the key
+func (r *HashRing) search(key string) int {
+	h := r.hashKey(key)
+	idx := sort.Search(len(r.points), func(i int) bool { return r.points[i].hash >= h })
+	if idx == len(r.points) {
+		idx = 0
+	}
+	return idx
+}
+
+// hashKey return the position of the key on the ring
+func (r *HashRing) hashKey(key string) uint64 {
+	if r.ketama {
+		d := md5.Sum([]byte(key))
+		return uint64(d[3])<<24 | uint64(d[2])<<16 | uint64(d[1])<<8 | uint64(d[0])
+	}
+	h, _ := hashSum64([]byte(key), r.ht)
+	return h
+}
+
+// build rebuild all the virtual nodes, the caller must hold the write lock
+func (r *HashRing) build() {
+	total := 0
+	for _, w := range r.weights {
+		total += w
+	}
+
+	r.points = r.points[:0]
+	for node, w := range r.weights {
+		if r.ketama {
+			// Keep the float precision of libketama: floorf(pct * 40.0 * (float)numservers)
+			pct := float32(w) / float32(total)
+			ks := int(math.Floor(float64(float32(float64(pct) * 40.0 * float64(float32(len(r.weights)))))))
+			for k := 0; k < ks; k++ {
+				d := md5.Sum([]byte(node + "-" + strconv.Itoa(k)))
+				for h := 0; h < 4; h++ {
+					p := uint64(d[3+h*4])<<24 | uint64(d[2+h*4])<<16 | uint64(d[1+h*4])<<8 | uint64(d[h*4])
+					r.points = append(r.points, ringPoint{hash: p, node: node})
+				}
+			}
+			continue
+		}
+
+		for i := 0; i < r.replicas*w; i++ {
+			h, _ := hashSum64([]byte(node+"-"+strconv.Itoa(i)), r.ht)
+			r.points = append(r.points, ringPoint{hash: h, node: node})
+		}
+	}
+
+	// Sort by hash and then by node, so that the ring is the same regardless of the insertion order
+	sort.Slice(r.points, func(i, j int) bool {
+		if r.points[i].hash != r.points[j].hash {
+			return r.points[i].hash < r.points[j].hash
+		}
+		return r.points[i].node < r.points[j].node
+	})
+}
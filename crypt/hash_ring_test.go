@@ -0,0 +1,121 @@
+package crypt
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestNewHashRing(t *testing.T) {
+	tests := []struct {
+		name    string
+		ht      HashType
+		wantErr bool
+	}{
+		{"FnvA64", HtFnvA64, false},
+		{"MD5", HtMD5, false},
+		{"Crc32", HtCrc32, false},
+		{"3Des", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHashRing(tt.ht, 0)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHashRing() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestKetamaRing(t *testing.T) {
+	r := NewKetamaRing()
+	r.Add("10.0.1.1:11211", 600)
+	r.Add("10.0.1.2:11211", 300)
+	r.Add("10.0.1.3:11211", 200)
+	if len(r.points) != 472 {
+		t.Errorf("points = %d, want %d", len(r.points), 472)
+	}
+
+	tests := []struct {
+		key  string
+		want string
+	}{
+		{"foo", "10.0.1.2:11211"},
+		{"bar", "10.0.1.1:11211"},
+		{"hello", "10.0.1.3:11211"},
+		{"world", "10.0.1.1:11211"},
+		{"12345", "10.0.1.1:11211"},
+		{"memcached", "10.0.1.2:11211"},
+		{"ketama", "10.0.1.3:11211"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			if got := r.Get(tt.key); got != tt.want {
+				t.Errorf("Get() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHashRing_Remove(t *testing.T) {
+	r, _ := NewHashRing(HtFnvA64, 100)
+	for i := 0; i < 5; i++ {
+		r.Add("node"+strconv.Itoa(i), 1)
+	}
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := "key" + strconv.Itoa(i)
+		before[key] = r.Get(key)
+	}
+
+	r.Remove("node2")
+	r.Remove("node9")
+	if got := r.Nodes(); !reflect.DeepEqual(got, []string{"node0", "node1", "node3", "node4"}) {
+		t.Errorf("Nodes() = %v", got)
+	}
+	for key, node := range before {
+		got := r.Get(key)
+		if got == "node2" || (node != "node2" && got != node) {
+			t.Errorf("Get(%s) = %v, before remove %v", key, got, node)
+		}
+	}
+}
+
+func TestHashRing_Weight(t *testing.T) {
+	r, _ := NewHashRing(HtMD5, 0)
+	r.Add("heavy", 3)
+	r.Add("light", 1)
+
+	cnt := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		cnt[r.Get("key"+strconv.Itoa(i))]++
+	}
+	if cnt["heavy"] < 6500 || cnt["heavy"] > 8500 {
+		t.Errorf("distribution = %v, expect about 3:1", cnt)
+	}
+}
+
+func TestHashRing_GetN(t *testing.T) {
+	r := NewKetamaRing()
+	if got := r.Get("key"); got != "" {
+		t.Errorf("Get() on empty ring = %v", got)
+	}
+	if got := r.GetN("key", 2); got != nil {
+		t.Errorf("GetN() on empty ring = %v", got)
+	}
+
+	r.Add("a", 1)
+	r.Add("b", 1)
+	r.Add("c", 1)
+	for i := 0; i < 100; i++ {
+		key := "key" + strconv.Itoa(i)
+		got := r.GetN(key, 2)
+		if len(got) != 2 || got[0] == got[1] || got[0] != r.Get(key) {
+			t.Errorf("GetN(%s, 2) = %v", key, got)
+		}
+		if got = r.GetN(key, 10); len(got) != 3 {
+			t.Errorf("GetN(%s, 10) = %v", key, got)
+		}
+	}
+}
@@ -190,6 +190,26 @@ func TestHashBytes(t *testing.T) {
 			args: args{hashCommonTest, HtTime33},
 			want: []byte{120, 28, 75, 93},
 		},
+		{
+			name: "Crc32C",
+			args: args{hashCommonTest, HtCrc32C},
+			want: []byte{211, 115, 128, 107},
+		},
+		{
+			name: "Crc16Modbus",
+			args: args{hashCommonTest, HtCrc16Modbus},
+			want: []byte{11, 168},
+		},
+		{
+			name: "Crc16CCITT",
+			args: args{hashCommonTest, HtCrc16CCITT},
+			want: []byte{64, 8},
+		},
+		{
+			name: "Crc16XModem",
+			args: args{hashCommonTest, HtCrc16XModem},
+			want: []byte{135, 228},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -247,7 +267,7 @@ func TestMd5File(t *testing.T) {
 		{
 			name: "Md5File",
 			args: args{"./random.go"},
-			want: "6bf5c32310ee93a54e98968259669f6d",
+			want: "70809b270e5bf51cd13c567129b85f7a",
 		},
 	}
 	for _, tt := range tests {
@@ -442,6 +462,26 @@ func TestHashUInt32(t *testing.T) {
 			args: args{hashCommonTest, HtTime33},
 			want: 2015120221,
 		},
+		{
+			name: "Crc32C",
+			args: args{hashCommonTest, HtCrc32C},
+			want: 3547562091,
+		},
+		{
+			name: "Crc16Modbus",
+			args: args{hashCommonTest, HtCrc16Modbus},
+			want: 2984,
+		},
+		{
+			name: "Crc16CCITT",
+			args: args{hashCommonTest, HtCrc16CCITT},
+			want: 16392,
+		},
+		{
+			name: "Crc16XModem",
+			args: args{hashCommonTest, HtCrc16XModem},
+			want: 34788,
+		},
 		{
 			name: "3Des",
 			args: args{hashCommonTest, 0},
@@ -0,0 +1,297 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"sort"
+)
+
+// HyperLogLog estimates the number of distinct items with m = 2^p registers, the relative standard error is about
+// 1.04/sqrt(m), e.g. 0.81% for p = 14 with 16KB memory.
+// Every item is hashed to 64 bits by the hash functions in hash.go, the first p bits select a register and the
+// register keeps the maximum position of the leftmost 1-bit of the remaining bits.
+//
+// Like HyperLogLog++ (Heule et al., 2013), a sketch starts with a sparse representation which stores the
+// (index, rank) pairs with the precision p' = 25, it is much more accurate and smaller for small cardinalities and
+// is converted to the dense registers when it grows larger than m/4 pairs.
+// The dense estimate uses the improved estimator of Otmar Ertl ("New cardinality estimation algorithms for
+// HyperLogLog sketches", 2017) which needs no empirical bias correction tables, the sparse estimate uses linear
+// counting over 2^25 registers.
+//
+// A HyperLogLog is not safe for concurrent use. The binary encoding is:
+//  magic(2 bytes: "HL") | version(1 byte) | p(1 byte) | HashType(4 bytes) | format(1 byte: 0 sparse, 1 dense) | payload
+// The dense payload is m registers(1 byte each), the sparse payload is the number of pairs followed by the
+// delta-encoded pairs(index<<6 | rank) in ascending order, all as uvarint.
+
+const (
+	hllMinPrecision    = 4
+	hllMaxPrecision    = 18
+	hllSparsePrecision = 25
+	hllVersion         = 1
+	hllHeaderLen       = 9
+	hllFormatSparse    = 0
+	hllFormatDense     = 1
+)
+
+// HyperLogLog a HyperLogLog++ cardinality estimator
+type HyperLogLog struct {
+	p      uint8
+	ht     HashType
+	sparse map[uint32]uint8 // index with p' bits -> rank
+	regs   []uint8
+}
+
+// NewHyperLogLog return a HyperLogLog with precision p which uses ht to hash the items
+// p must be in the range of [4,18], ht must be supported by HashUInt64 or HashBytes
+func NewHyperLogLog(p uint8, ht HashType) (*HyperLogLog, error) {
+	if p < hllMinPrecision || p > hllMaxPrecision {
+		return nil, errors.New(sErrHllPrecisionInvalid)
+	}
+	if _, err := hashSum64(nil, ht); err != nil {
+		return nil, err
+	}
+	return &HyperLogLog{p: p, ht: ht, sparse: make(map[uint32]uint8)}, nil
+}
+
+// Precision return the precision p
+func (h *HyperLogLog) Precision() uint8 {
+	return h.p
+}
+
+// Add add data to the sketch
+func (h *HyperLogLog) Add(data []byte) {
+	x, _ := hashSum64(data, h.ht)
+	h.insert(mix64(x))
+}
+
+// AddString add the string to the sketch
+func (h *HyperLogLog) AddString(s string) {
+	h.Add([]byte(s))
+}
+
+// insert insert a 64-bit hash value
+func (h *HyperLogLog) insert(x uint64) {
+	if h.regs != nil {
+		idx := x >> (64 - h.p)
+		rank := uint8(bits.LeadingZeros64(x<<h.p|1<<(h.p-1)) + 1)
+		if rank > h.regs[idx] {
+			h.regs[idx] = rank
+		}
+		return
+	}
+
+	idx := uint32(x >> (64 - hllSparsePrecision))
+	rank := uint8(bits.LeadingZeros64(x<<hllSparsePrecision|1<<(hllSparsePrecision-1)) + 1)
+	if rank > h.sparse[idx] {
+		h.sparse[idx] = rank
+	}
+	if len(h.sparse) > (1<<h.p)/4 {
+		h.toDense()
+	}
+}
+
+// toDense convert the sparse representation to the dense registers
+func (h *HyperLogLog) toDense() {
+	h.regs = make([]uint8, 1<<h.p)
+	shift := hllSparsePrecision - h.p
+	for idx, rank := range h.sparse {
+		r := rank + shift
+		if w := idx & (1<<shift - 1); w != 0 {
+			r = shift - uint8(bits.Len32(w)) + 1
+		}
+		if i := idx >> shift; r > h.regs[i] {
+			h.regs[i] = r
+		}
+	}
+	h.sparse = nil
+}
+
+// Count return the estimated number of distinct items
+func (h *HyperLogLog) Count() uint64 {
+	if h.regs == nil {
+		m := float64(uint64(1) << hllSparsePrecision)
+		return uint64(math.Round(m * math.Log(m/(m-float64(len(h.sparse))))))
+	}
+
+	m := float64(len(h.regs))
+	q := 64 - int(h.p)
+	hist := make([]float64, q+2)
+	for _, r := range h.regs {
+		hist[r]++
+	}
+	z := m * hllTau(1-hist[q+1]/m)
+	for k := q; k >= 1; k-- {
+		z = 0.5 * (z + hist[k])
+	}
+	z += m * hllSigma(hist[0]/m)
+	return uint64(math.Round(m * m / (2 * math.Ln2) / z))
+}
+
+// hllSigma sigma(x) = x + sum(x^(2^k) * 2^(k-1)), k = 1...∞
+func hllSigma(x float64) float64 {
+	if x == 1 {
+		return math.Inf(1)
+	}
+	y, z := 1.0, x
+	for {
+		x *= x
+		prev := z
+		z += x * y
+		y += y
+		if prev == z {
+			return z
+		}
+	}
+}
+
+// hllTau tau(x) = (1 - x - sum((1 - x^(2^-k))^2 * 2^-k)) / 3, k = 1...∞
+func hllTau(x float64) float64 {
+	if x == 0 || x == 1 {
+		return 0
+	}
+	y, z := 1.0, 1-x
+	for {
+		x = math.Sqrt(x)
+		prev := z
+		y *= 0.5
+		z -= (1 - x) * (1 - x) * y
+		if prev == z {
+			return z / 3
+		}
+	}
+}
+
+// Merge merge other into h, after that h estimates the number of distinct items of both sketches.
+// The sketches must have the same precision and HashType
+func (h *HyperLogLog) Merge(other *HyperLogLog) error {
+	if h.p != other.p || h.ht != other.ht {
+		return errors.New(sErrHllNotCompatible)
+	}
+
+	if other.regs == nil {
+		if h.regs == nil {
+			for idx, rank := range other.sparse {
+				if rank > h.sparse[idx] {
+					h.sparse[idx] = rank
+				}
+			}
+			if len(h.sparse) > (1<<h.p)/4 {
+				h.toDense()
+			}
+			return nil
+		}
+		other = other.clone()
+		other.toDense()
+	}
+
+	if h.regs == nil {
+		h.toDense()
+	}
+	for i, r := range other.regs {
+		if r > h.regs[i] {
+			h.regs[i] = r
+		}
+	}
+	return nil
+}
+
+// clone return a deep copy of h
+func (h *HyperLogLog) clone() *HyperLogLog {
+	c := &HyperLogLog{p: h.p, ht: h.ht}
+	if h.regs != nil {
+		c.regs = append([]uint8(nil), h.regs...)
+		return c
+	}
+	c.sparse = make(map[uint32]uint8, len(h.sparse))
+	for idx, rank := range h.sparse {
+		c.sparse[idx] = rank
+	}
+	return c
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler
+func (h *HyperLogLog) MarshalBinary() ([]byte, error) {
+	data := make([]byte, hllHeaderLen, hllHeaderLen+len(h.regs))
+	data[0], data[1], data[2], data[3] = 'H', 'L', hllVersion, h.p
+	binary.BigEndian.PutUint32(data[4:], uint32(h.ht))
+	if h.regs != nil {
+		data[8] = hllFormatDense
+		return append(data, h.regs...), nil
+	}
+
+	data[8] = hllFormatSparse
+	pairs := make([]uint32, 0, len(h.sparse))
+	for idx, rank := range h.sparse {
+		pairs = append(pairs, idx<<6|uint32(rank))
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i] < pairs[j] })
+
+	var buf [binary.MaxVarintLen64]byte
+	data = append(data, buf[:binary.PutUvarint(buf[:], uint64(len(pairs)))]...)
+	prev := uint32(0)
+	for _, pair := range pairs {
+		data = append(data, buf[:binary.PutUvarint(buf[:], uint64(pair-prev))]...)
+		prev = pair
+	}
+	return data, nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler
+func (h *HyperLogLog) UnmarshalBinary(data []byte) error {
+	if len(data) < hllHeaderLen || data[0] != 'H' || data[1] != 'L' || data[2] != hllVersion {
+		return errors.New(sErrDataInvalid)
+	}
+	p, ht := data[3], HashType(binary.BigEndian.Uint32(data[4:]))
+	if p < hllMinPrecision || p > hllMaxPrecision {
+		return errors.New(sErrHllPrecisionInvalid)
+	}
+	// The HashType must be registered and usable by Add, the same as NewHyperLogLog
+	if _, err := hashSum64(nil, ht); err != nil {
+		return errors.New(sErrDataInvalid)
+	}
+
+	payload := data[hllHeaderLen:]
+	switch data[8] {
+	case hllFormatDense:
+		if len(payload) != 1<<p {
+			return errors.New(sErrDataInvalid)
+		}
+		for _, r := range payload {
+			if int(r) > 65-int(p) {
+				return errors.New(sErrDataInvalid)
+			}
+		}
+		h.p, h.ht, h.sparse, h.regs = p, ht, nil, append([]uint8(nil), payload...)
+		return nil
+	case hllFormatSparse:
+		cnt, n := binary.Uvarint(payload)
+		if n <= 0 || cnt > (1<<p)/4 {
+			return errors.New(sErrDataInvalid)
+		}
+		payload = payload[n:]
+		sparse := make(map[uint32]uint8, cnt)
+		pair := uint64(0)
+		for i := uint64(0); i < cnt; i++ {
+			delta, n := binary.Uvarint(payload)
+			if n <= 0 || (i > 0 && delta == 0) {
+				return errors.New(sErrDataInvalid)
+			}
+			payload = payload[n:]
+			pair += delta
+			idx, rank := pair>>6, uint8(pair&63)
+			if idx >= 1<<hllSparsePrecision || rank == 0 || rank > 64-hllSparsePrecision+1 {
+				return errors.New(sErrDataInvalid)
+			}
+			sparse[uint32(idx)] = rank
+		}
+		if len(payload) != 0 {
+			return errors.New(sErrDataInvalid)
+		}
+		h.p, h.ht, h.sparse, h.regs = p, ht, sparse, nil
+		return nil
+	default:
+		return errors.New(sErrDataInvalid)
+	}
+}
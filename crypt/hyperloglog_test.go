@@ -0,0 +1,160 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"math"
+	"strconv"
+	"testing"
+)
+
+func TestNewHyperLogLog(t *testing.T) {
+	tests := []struct {
+		name    string
+		p       uint8
+		ht      HashType
+		wantErr bool
+	}{
+		{"p14", 14, HtFnvA64, false},
+		{"p4", 4, HtMD5, false},
+		{"p3", 3, HtFnvA64, true},
+		{"p19", 19, HtFnvA64, true},
+		{"3Des", 14, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewHyperLogLog(tt.p, tt.ht)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewHyperLogLog() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestHyperLogLog_Count(t *testing.T) {
+	tests := []struct {
+		name string
+		p    uint8
+		n    int
+		err  float64
+	}{
+		{"Empty", 14, 0, 0},
+		{"Sparse#100", 14, 100, 0.01},
+		{"Sparse#3000", 14, 3000, 0.01},
+		{"Dense#10000", 14, 10000, 0.03},
+		{"Dense#1000000", 14, 1000000, 0.03},
+		{"p10#100000", 10, 100000, 0.1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h, _ := NewHyperLogLog(tt.p, HtFnvA64)
+			for i := 0; i < tt.n; i++ {
+				h.AddString("user-" + strconv.Itoa(i))
+				h.AddString("user-" + strconv.Itoa(i))
+			}
+			got := h.Count()
+			if math.Abs(float64(got)-float64(tt.n)) > float64(tt.n)*tt.err {
+				t.Errorf("Count() = %v, want %v", got, tt.n)
+			}
+		})
+	}
+}
+
+func TestHyperLogLog_Merge(t *testing.T) {
+	a, _ := NewHyperLogLog(12, HtFnvA64)
+	b, _ := NewHyperLogLog(12, HtFnvA64)
+	c, _ := NewHyperLogLog(12, HtFnvA64)
+	for i := 0; i < 200; i++ {
+		a.AddString("user-" + strconv.Itoa(i))
+	}
+	for i := 100; i < 50000; i++ {
+		b.AddString("user-" + strconv.Itoa(i))
+	}
+	for i := 150; i < 300; i++ {
+		c.AddString("user-" + strconv.Itoa(i))
+	}
+
+	// sparse + sparse
+	if err := c.Merge(a); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if got := c.Count(); got < 297 || got > 303 {
+		t.Errorf("Count() = %v, want %v", got, 300)
+	}
+	// sparse + dense
+	if err := a.Merge(b); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if got := a.Count(); math.Abs(float64(got)-50000) > 50000*0.05 {
+		t.Errorf("Count() = %v, want %v", got, 50000)
+	}
+	// dense + sparse
+	if err := b.Merge(c); err != nil {
+		t.Fatalf("Merge() error = %v", err)
+	}
+	if a.Count() != b.Count() {
+		t.Errorf("Count() = %v, want %v", b.Count(), a.Count())
+	}
+
+	d, _ := NewHyperLogLog(14, HtFnvA64)
+	if err := a.Merge(d); err == nil {
+		t.Errorf("Merge() expect error for different precision")
+	}
+	e, _ := NewHyperLogLog(12, HtMD5)
+	if err := a.Merge(e); err == nil {
+		t.Errorf("Merge() expect error for different HashType")
+	}
+}
+
+func TestHyperLogLog_Binary(t *testing.T) {
+	for _, n := range []int{0, 500, 100000} {
+		t.Run(strconv.Itoa(n), func(t *testing.T) {
+			h, _ := NewHyperLogLog(14, HtFnvA64)
+			for i := 0; i < n; i++ {
+				h.AddString("user-" + strconv.Itoa(i))
+			}
+			data, _ := h.MarshalBinary()
+
+			got := &HyperLogLog{}
+			if err := got.UnmarshalBinary(data); err != nil {
+				t.Fatalf("UnmarshalBinary() error = %v", err)
+			}
+			if got.Count() != h.Count() || got.Precision() != 14 {
+				t.Errorf("Count() = %v, want %v", got.Count(), h.Count())
+			}
+			again, _ := got.MarshalBinary()
+			if string(again) != string(data) {
+				t.Errorf("MarshalBinary() is not stable")
+			}
+			// The restored sketch can continue to add items
+			got.AddString("user-new")
+			h.AddString("user-new")
+			if got.Count() != h.Count() {
+				t.Errorf("Count() = %v, want %v", got.Count(), h.Count())
+			}
+
+			if err := got.UnmarshalBinary(data[:len(data)-1]); n > 0 && err == nil {
+				t.Errorf("UnmarshalBinary() expect error for truncated data")
+			}
+		})
+	}
+
+	for _, invalid := range [][]byte{nil, []byte("HL\x01\x03\x00\x00\x00\x0b\x01"), []byte("HL\x01\x0e\x00\x00\x00\x0b\x02")} {
+		if err := (&HyperLogLog{}).UnmarshalBinary(invalid); err == nil {
+			t.Errorf("UnmarshalBinary(%v) expect error", invalid)
+		}
+	}
+
+	// The HashType of the header must be registered
+	h, _ := NewHyperLogLog(10, htTestSha512T256)
+	h.AddString("hello")
+	data, _ := h.MarshalBinary()
+	if err := (&HyperLogLog{}).UnmarshalBinary(data); err != nil {
+		t.Errorf("UnmarshalBinary() of the registered HashType error = %v", err)
+	}
+	for _, ht := range []uint32{0, uint32(HtUser) + 999} {
+		binary.BigEndian.PutUint32(data[4:], ht)
+		if err := (&HyperLogLog{}).UnmarshalBinary(data); err == nil || err.Error() != sErrDataInvalid {
+			t.Errorf("UnmarshalBinary() of HashType %d error = %v, want %v", ht, err, sErrDataInvalid)
+		}
+	}
+}
@@ -0,0 +1,229 @@
+package crypt
+
+import (
+	"crypto/cipher"
+	"encoding/binary"
+	"errors"
+	"math"
+	"sort"
+	"sync"
+)
+
+// A Keyring holds multiple versioned AES keys, so the keys can be rotated without breaking the stored ciphertexts.
+// Every ciphertext is prefixed with the ID of the key used to encrypt it:
+//  version(1 byte, 0x01) || key ID(4 bytes big-endian) || the AES-GCM or AES-SIV ciphertext
+// The AES-GCM ciphertext is nonce(12 bytes) || ciphertext || tag(16 bytes), the AES-SIV ciphertext is the result of
+// AESSIVEncrypt. The header is the associated data, so a ciphertext moved to another key ID or version fails to
+// decrypt.
+// Encrypt always uses the primary key, Decrypt finds the key by the ID in the ciphertext. To rotate a key, add a new
+// key and make it the primary(or call Rotate), then migrate the old ciphertexts by ReEncrypt, and remove the old key
+// when no ciphertext uses it.
+
+const (
+	keyringVersion    = 1
+	keyringHeaderSize = 5
+)
+
+// Keyring a set of AES keys identified by the key IDs, it is safe for concurrent use
+type Keyring struct {
+	mu      sync.RWMutex
+	mode    KeyringMode
+	keys    map[uint32][]byte
+	primary uint32
+	hasPri  bool
+}
+
+// NewKeyring return an empty keyring which encrypts in mode, KeyringModeGCM or KeyringModeSIV
+func NewKeyring(mode KeyringMode) (*Keyring, error) {
+	if mode != KeyringModeGCM && mode != KeyringModeSIV {
+		return nil, errors.New(sErrKeyringModeInvalid)
+	}
+	return &Keyring{mode: mode, keys: make(map[uint32][]byte)}, nil
+}
+
+// AddKey add the key with the id, the first key added becomes the primary key.
+// The key must be 16, 24 or 32 bytes, or 32, 48 or 64 bytes in KeyringModeSIV
+func (kr *Keyring) AddKey(id uint32, key []byte) error {
+	if err := kr.checkKey(key); err != nil {
+		return err
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if _, ok := kr.keys[id]; ok {
+		return errors.New(sErrKeyringKeyExists)
+	}
+	kr.keys[id] = append([]byte{}, key...)
+	if !kr.hasPri {
+		kr.primary, kr.hasPri = id, true
+	}
+	return nil
+}
+
+// Rotate add the key with the ID which is the maximum ID plus 1 and make it the primary key, return the new ID
+func (kr *Keyring) Rotate(key []byte) (uint32, error) {
+	if err := kr.checkKey(key); err != nil {
+		return 0, err
+	}
+
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	var id uint32
+	if len(kr.keys) > 0 {
+		var max uint32
+		for k := range kr.keys {
+			if k > max {
+				max = k
+			}
+		}
+		if max == math.MaxUint32 {
+			return 0, errors.New(sErrKeyringKeyExists)
+		}
+		id = max + 1
+	}
+	kr.keys[id] = append([]byte{}, key...)
+	kr.primary, kr.hasPri = id, true
+	return id, nil
+}
+
+// SetPrimary make the key with the id the primary key which is used by Encrypt
+func (kr *Keyring) SetPrimary(id uint32) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if _, ok := kr.keys[id]; !ok {
+		return errors.New(sErrKeyringKeyNotFound)
+	}
+	kr.primary, kr.hasPri = id, true
+	return nil
+}
+
+// Primary return the ID of the primary key, false if the keyring is empty
+func (kr *Keyring) Primary() (uint32, bool) {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	return kr.primary, kr.hasPri
+}
+
+// RemoveKey remove the key with the id, the ciphertexts encrypted by it can not be decrypted any more.
+// The primary key can not be removed
+func (kr *Keyring) RemoveKey(id uint32) error {
+	kr.mu.Lock()
+	defer kr.mu.Unlock()
+	if _, ok := kr.keys[id]; !ok {
+		return errors.New(sErrKeyringKeyNotFound)
+	}
+	if kr.hasPri && kr.primary == id {
+		return errors.New(sErrKeyringPrimaryRemove)
+	}
+	delete(kr.keys, id)
+	return nil
+}
+
+// IDs return the IDs of all keys in ascending order
+func (kr *Keyring) IDs() []uint32 {
+	kr.mu.RLock()
+	defer kr.mu.RUnlock()
+	ids := make([]uint32, 0, len(kr.keys))
+	for id := range kr.keys {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
+// Encrypt encrypt plaintext with the primary key, the result is prefixed with the version and the key ID
+func (kr *Keyring) Encrypt(plaintext []byte) ([]byte, error) {
+	kr.mu.RLock()
+	id, key, ok := kr.primary, kr.keys[kr.primary], kr.hasPri
+	kr.mu.RUnlock()
+	if !ok {
+		return nil, errors.New(sErrKeyringNoPrimary)
+	}
+
+	header := make([]byte, keyringHeaderSize)
+	header[0] = keyringVersion
+	binary.BigEndian.PutUint32(header[1:], id)
+	var encrypted []byte
+	var err error
+	if kr.mode == KeyringModeSIV {
+		encrypted, err = AESSIVEncrypt(plaintext, key, header)
+	} else {
+		var aead cipher.AEAD
+		if aead, err = keyringGCM(key); err == nil {
+			encrypted, err = aeadEncrypt(aead, plaintext, header)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return append(header, encrypted...), nil
+}
+
+// Decrypt decrypt the result of Encrypt with the key of the ID in ciphertext
+func (kr *Keyring) Decrypt(ciphertext []byte) ([]byte, error) {
+	id, err := KeyringKeyID(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	kr.mu.RLock()
+	key, ok := kr.keys[id]
+	kr.mu.RUnlock()
+	if !ok {
+		return nil, errors.New(sErrKeyringKeyNotFound)
+	}
+	header, data := ciphertext[:keyringHeaderSize], ciphertext[keyringHeaderSize:]
+	if kr.mode == KeyringModeSIV {
+		return AESSIVDecrypt(data, key, header)
+	}
+	aead, err := keyringGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	return aeadDecrypt(aead, data, header)
+}
+
+// ReEncrypt decrypt ciphertext and encrypt it again with the primary key, it is used to migrate the ciphertexts
+// after the rotation. If ciphertext is already encrypted by the primary key, it is returned as is and the second
+// return value is false
+func (kr *Keyring) ReEncrypt(ciphertext []byte) ([]byte, bool, error) {
+	plaintext, err := kr.Decrypt(ciphertext)
+	if err != nil {
+		return nil, false, err
+	}
+	id, _ := KeyringKeyID(ciphertext)
+	if primary, ok := kr.Primary(); ok && primary == id {
+		return ciphertext, false, nil
+	}
+	out, err := kr.Encrypt(plaintext)
+	if err != nil {
+		return nil, false, err
+	}
+	return out, true, nil
+}
+
+// KeyringKeyID return the ID of the key which encrypted the result of Keyring.Encrypt
+func KeyringKeyID(ciphertext []byte) (uint32, error) {
+	if len(ciphertext) < keyringHeaderSize || ciphertext[0] != keyringVersion {
+		return 0, errors.New(sErrDataInvalid)
+	}
+	return binary.BigEndian.Uint32(ciphertext[1:keyringHeaderSize]), nil
+}
+
+// checkKey return an error if the length of key is invalid for the mode
+func (kr *Keyring) checkKey(key []byte) error {
+	if kr.mode == KeyringModeSIV {
+		_, _, err := aesSIVCiphers(key)
+		return err
+	}
+	_, err := newAESCipher(key)
+	return err
+}
+
+// keyringGCM return the AES-GCM AEAD of key
+func keyringGCM(key []byte) (cipher.AEAD, error) {
+	block, err := newAESCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
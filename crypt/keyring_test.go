@@ -0,0 +1,150 @@
+package crypt
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestKeyring(t *testing.T) {
+	modes := []struct {
+		name string
+		mode KeyringMode
+		key1 []byte
+		key2 []byte
+	}{
+		{"GCM", KeyringModeGCM, commonKey16, commonKey32},
+		{"SIV", KeyringModeSIV, commonKey32, append(append([]byte{}, commonKey32...), commonKey16...)},
+	}
+	for _, m := range modes {
+		t.Run(m.name, func(t *testing.T) {
+			kr, err := NewKeyring(m.mode)
+			if err != nil {
+				t.Fatalf("NewKeyring() error = %v", err)
+			}
+			if _, err = kr.Encrypt(commonOriginData); err == nil {
+				t.Errorf("Encrypt() empty keyring error = nil, want error")
+			}
+			if err = kr.AddKey(1, m.key1); err != nil {
+				t.Fatalf("AddKey() error = %v", err)
+			}
+			if id, ok := kr.Primary(); !ok || id != 1 {
+				t.Errorf("Primary() = %v, %v, want 1, true", id, ok)
+			}
+			old, err := kr.Encrypt(commonOriginData)
+			if err != nil {
+				t.Fatalf("Encrypt() error = %v", err)
+			}
+			if id, _ := KeyringKeyID(old); id != 1 {
+				t.Errorf("KeyringKeyID() = %v, want 1", id)
+			}
+
+			// rotate to key 2, the old ciphertext can still be decrypted
+			id, err := kr.Rotate(m.key2)
+			if err != nil || id != 2 {
+				t.Fatalf("Rotate() = %v, %v, want 2", id, err)
+			}
+			current, _ := kr.Encrypt(commonOriginData)
+			if id, _ := KeyringKeyID(current); id != 2 {
+				t.Errorf("KeyringKeyID() after Rotate = %v, want 2", id)
+			}
+			for _, c := range [][]byte{old, current} {
+				if got, err := kr.Decrypt(c); err != nil || !bytes.Equal(got, commonOriginData) {
+					t.Errorf("Decrypt() = %v, %v, want %v", got, err, commonOriginData)
+				}
+			}
+
+			// migrate the old ciphertext, then the old key can be removed
+			migrated, changed, err := kr.ReEncrypt(old)
+			if err != nil || !changed {
+				t.Fatalf("ReEncrypt() = %v, %v, want true", changed, err)
+			}
+			if id, _ := KeyringKeyID(migrated); id != 2 {
+				t.Errorf("KeyringKeyID() after ReEncrypt = %v, want 2", id)
+			}
+			same, changed, err := kr.ReEncrypt(current)
+			if err != nil || changed || !bytes.Equal(same, current) {
+				t.Errorf("ReEncrypt() of primary = %v, %v, want unchanged", changed, err)
+			}
+			if err = kr.RemoveKey(1); err != nil {
+				t.Fatalf("RemoveKey() error = %v", err)
+			}
+			if _, err = kr.Decrypt(old); err == nil {
+				t.Errorf("Decrypt() with removed key error = nil, want error")
+			}
+			if got, err := kr.Decrypt(migrated); err != nil || !bytes.Equal(got, commonOriginData) {
+				t.Errorf("Decrypt() migrated = %v, %v, want %v", got, err, commonOriginData)
+			}
+		})
+	}
+}
+
+func TestKeyringKeys(t *testing.T) {
+	for _, mode := range []KeyringMode{0, KeyringModeSIV + 1} {
+		if _, err := NewKeyring(mode); err == nil {
+			t.Errorf("NewKeyring(%v) invalid mode error = nil, want error", mode)
+		}
+	}
+	kr, _ := NewKeyring(KeyringModeGCM)
+	if err := kr.AddKey(1, invalidKey); err == nil {
+		t.Errorf("AddKey() invalid key error = nil, want error")
+	}
+	_ = kr.AddKey(7, commonKey16)
+	_ = kr.AddKey(3, commonKey32)
+	if err := kr.AddKey(3, commonKey16); err == nil {
+		t.Errorf("AddKey() duplicate id error = nil, want error")
+	}
+	if got := kr.IDs(); !reflect.DeepEqual(got, []uint32{3, 7}) {
+		t.Errorf("IDs() = %v, want [3 7]", got)
+	}
+	if err := kr.SetPrimary(5); err == nil {
+		t.Errorf("SetPrimary() unknown id error = nil, want error")
+	}
+	if err := kr.SetPrimary(3); err != nil {
+		t.Errorf("SetPrimary() error = %v", err)
+	}
+	if err := kr.RemoveKey(3); err == nil {
+		t.Errorf("RemoveKey() primary error = nil, want error")
+	}
+	if err := kr.RemoveKey(5); err == nil {
+		t.Errorf("RemoveKey() unknown id error = nil, want error")
+	}
+	if id, err := kr.Rotate(commonKey24); err != nil || id != 8 {
+		t.Errorf("Rotate() = %v, %v, want 8", id, err)
+	}
+	_ = kr.AddKey(math.MaxUint32, commonKey16)
+	if _, err := kr.Rotate(commonKey24); err == nil {
+		t.Errorf("Rotate() after max id error = nil, want error")
+	}
+
+	for _, data := range [][]byte{nil, {keyringVersion, 0, 0}, {2, 0, 0, 0, 3, 1, 2}} {
+		if _, err := kr.Decrypt(data); err == nil {
+			t.Errorf("Decrypt(%v) error = nil, want error", data)
+		}
+	}
+}
+
+func TestKeyringHeaderAuthenticated(t *testing.T) {
+	for _, m := range []struct {
+		mode KeyringMode
+		key  []byte
+	}{{KeyringModeGCM, commonKey16}, {KeyringModeSIV, commonKey32}} {
+		// The same key under two IDs, so only the authentication of the header detects the change
+		kr, _ := NewKeyring(m.mode)
+		_ = kr.AddKey(1, m.key)
+		_ = kr.AddKey(2, m.key)
+		encrypted, err := kr.Encrypt(commonOriginData)
+		if err != nil {
+			t.Fatalf("Encrypt() error = %v", err)
+		}
+		moved := append([]byte{}, encrypted...)
+		moved[keyringHeaderSize-1] = 2
+		if _, err = kr.Decrypt(moved); err == nil {
+			t.Errorf("Decrypt() of mode %v with the key ID changed error = nil, want error", m.mode)
+		}
+		if got, err := kr.Decrypt(encrypted); err != nil || !bytes.Equal(got, commonOriginData) {
+			t.Errorf("Decrypt() = %v, %v, want %v", got, err, commonOriginData)
+		}
+	}
+}
@@ -0,0 +1,145 @@
+package crypt
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+	"time"
+)
+
+// KSUID(K-Sortable Unique IDentifier) is a 32-bit timestamp in seconds since the KSUID epoch(2014-05-13 16:53:20 UTC)
+// followed by a 128-bit random payload. It is encoded as 27 characters of Base62(0-9A-Za-z) left padded with '0',
+// so the strings are sorted by the creation time, such as: 0ujtsYcgvSTl8PAuAdqWYSMnLOv.
+
+// KSUID a 160-bit sortable identifier
+type KSUID [20]byte
+
+const (
+	ksuidEpoch       = 1400000000
+	ksuidStrLen      = 27
+	ksuidPayloadSize = 16
+	// base62Alphabet the alphabet of Base62
+	base62Alphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+)
+
+// NewKSUID return a KSUID of the current time with a random payload read from crypto/rand
+func NewKSUID() (KSUID, error) {
+	return newKSUID(time.Now())
+}
+
+// newKSUID return a KSUID of t
+func newKSUID(t time.Time) (KSUID, error) {
+	var k KSUID
+	if _, err := io.ReadFull(crand.Reader, k[4:]); err != nil {
+		return KSUID{}, err
+	}
+	binary.BigEndian.PutUint32(k[:4], uint32(t.Unix()-ksuidEpoch))
+	return k, nil
+}
+
+// ParseKSUID parse the 27 characters of a KSUID
+func ParseKSUID(s string) (KSUID, error) {
+	var k KSUID
+	if len(s) != ksuidStrLen {
+		return k, errors.New(sErrKSUIDInvalid)
+	}
+	digits := make([]byte, len(s))
+	for i := 0; i < len(s); i++ {
+		v := baseIndex(base62Alphabet, s[i])
+		if v < 0 {
+			return k, errors.New(sErrKSUIDInvalid)
+		}
+		digits[i] = byte(v)
+	}
+	raw := convertBase(digits, 62, 256)
+	// The largest KSUID is aWgEPTl1tmebfsQzFP4bxwgy80V
+	if len(raw) > len(k) {
+		return k, errors.New(sErrKSUIDInvalid)
+	}
+	copy(k[len(k)-len(raw):], raw)
+	return k, nil
+}
+
+// String return the 27 characters of k
+func (k KSUID) String() string {
+	digits := convertBase(k[:], 256, 62)
+	buf := make([]byte, ksuidStrLen)
+	pad := ksuidStrLen - len(digits)
+	for i := range buf {
+		if i < pad {
+			buf[i] = base62Alphabet[0]
+		} else {
+			buf[i] = base62Alphabet[digits[i-pad]]
+		}
+	}
+	return string(buf)
+}
+
+// Timestamp return the timestamp of k, the seconds since the KSUID epoch
+func (k KSUID) Timestamp() uint32 {
+	return binary.BigEndian.Uint32(k[:4])
+}
+
+// Time return the creation time of k
+func (k KSUID) Time() time.Time {
+	return time.Unix(int64(k.Timestamp())+ksuidEpoch, 0)
+}
+
+// Payload return the 128-bit random payload of k
+func (k KSUID) Payload() []byte {
+	return append([]byte{}, k[4:]...)
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (k KSUID) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (k *KSUID) UnmarshalText(text []byte) error {
+	v, err := ParseKSUID(string(text))
+	if err != nil {
+		return err
+	}
+	*k = v
+	return nil
+}
+
+// convertBase convert the big-endian digits of a number in base from to the digits in base to by the long division,
+// the leading zeros are dropped, so the result of zero is empty. from and to must not be greater than 256
+func convertBase(src []byte, from, to uint32) []byte {
+	for len(src) > 0 && src[0] == 0 {
+		src = src[1:]
+	}
+	num := append([]byte{}, src...)
+	var res []byte
+	for len(num) > 0 {
+		var rem uint32
+		// The quotient is written in place, it is never longer than the dividend
+		quotient := num[:0]
+		for _, d := range num {
+			acc := rem*from + uint32(d)
+			if q := acc / to; q != 0 || len(quotient) > 0 {
+				quotient = append(quotient, byte(q))
+			}
+			rem = acc % to
+		}
+		res = append(res, byte(rem))
+		num = quotient
+	}
+	for i, j := 0, len(res)-1; i < j; i, j = i+1, j-1 {
+		res[i], res[j] = res[j], res[i]
+	}
+	return res
+}
+
+// baseIndex return the index of c in alphabet, -1 if c is not in alphabet
+func baseIndex(alphabet string, c byte) int {
+	for i := 0; i < len(alphabet); i++ {
+		if alphabet[i] == c {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,107 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseKSUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "Segment", s: "0ujtsYcgvSTl8PAuAdqWYSMnLOv", want: "0669f7efb5a1cd34b5f99d1154fb6853345c9735"},
+		{name: "Max", s: "aWgEPTl1tmebfsQzFP4bxwgy80V", want: "ffffffffffffffffffffffffffffffffffffffff"},
+		{name: "Zero", s: "000000000000000000000000000", want: "0000000000000000000000000000000000000000"},
+		{name: "Overflow", s: "aWgEPTl1tmebfsQzFP4bxwgy80W", wantErr: true},
+		{name: "InvalidChar", s: "0ujtsYcgvSTl8PAuAdqWYSMnLO-", wantErr: true},
+		{name: "Short", s: "0ujtsYcgvSTl8PAuAdqWYSMnLO", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseKSUID(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseKSUID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if hex.EncodeToString(got[:]) != tt.want {
+				t.Errorf("ParseKSUID() = %x, want %v", got, tt.want)
+			}
+			if got.String() != tt.s {
+				t.Errorf("String() = %v, want %v", got.String(), tt.s)
+			}
+		})
+	}
+
+	k, _ := ParseKSUID("0ujtsYcgvSTl8PAuAdqWYSMnLOv")
+	if k.Timestamp() != 107608047 || !k.Time().Equal(time.Unix(1507608047, 0)) {
+		t.Errorf("Timestamp() = %v, Time() = %v", k.Timestamp(), k.Time())
+	}
+	if hex.EncodeToString(k.Payload()) != "b5a1cd34b5f99d1154fb6853345c9735" {
+		t.Errorf("Payload() = %x", k.Payload())
+	}
+}
+
+func TestNewKSUID(t *testing.T) {
+	now := time.Now()
+	k, err := NewKSUID()
+	if err != nil {
+		t.Fatalf("NewKSUID() error = %v", err)
+	}
+	if d := k.Time().Sub(now); d < -time.Second || d > time.Second {
+		t.Errorf("Time() = %v, want %v", k.Time(), now)
+	}
+	if len(k.String()) != 27 {
+		t.Errorf("String() = %v", k.String())
+	}
+
+	// The strings are sorted by the time
+	k1, _ := newKSUID(now)
+	k2, _ := newKSUID(now.Add(time.Second))
+	if k1.String() >= k2.String() {
+		t.Errorf("%v is not less than %v", k1, k2)
+	}
+}
+
+func TestKSUIDMarshal(t *testing.T) {
+	k, _ := NewKSUID()
+	data, err := json.Marshal(k)
+	if err != nil || string(data) != `"`+k.String()+`"` {
+		t.Errorf("json.Marshal() = %s, error = %v", data, err)
+	}
+	var got KSUID
+	if err := json.Unmarshal(data, &got); err != nil || got != k {
+		t.Errorf("json.Unmarshal() = %v, error = %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(`"invalid"`), &got); err == nil {
+		t.Errorf("json.Unmarshal() with invalid ksuid should return an error")
+	}
+}
+
+func TestConvertBase(t *testing.T) {
+	tests := []struct {
+		name     string
+		src      []byte
+		from, to uint32
+		want     []byte
+	}{
+		{name: "Zero", src: []byte{0, 0}, from: 256, to: 62, want: nil},
+		{name: "LeadingZero", src: []byte{0, 1, 0}, from: 256, to: 10, want: []byte{2, 5, 6}},
+		{name: "ToBinary", src: []byte{5}, from: 10, to: 2, want: []byte{1, 0, 1}},
+		{name: "FromBase62", src: []byte{1, 0}, from: 62, to: 256, want: []byte{62}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := convertBase(tt.src, tt.from, tt.to); !bytes.Equal(got, tt.want) {
+				t.Errorf("convertBase() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package crypt
+
+import (
+	"errors"
+	"sort"
+	"sync"
+)
+
+// Maglev hashing builds a lookup table of prime size M, every node fills the table by its own permutation of
+// [0, M) in turns, so that every node owns almost M/n entries and a membership change only moves a few entries
+// besides those of the changed node. A lookup is O(1): table[hash(key) % M].
+// The permutation of a node: offset = h1(node) % M, skip = h2(node) % (M-1) + 1, permutation[j] = (offset + j*skip) % M
+// See "Maglev: A Fast and Reliable Software Network Load Balancer" (Eisenbud et al., NSDI 2016).
+// M should be much larger than the number of nodes(the paper recommends M > 100*n) to keep the load balanced.
+
+const (
+	defaultMaglevSize = 65537   // the default size of the lookup table, it is a prime
+	maxMaglevSize     = 1 << 24 // the maximum size of the lookup table, it bounds the memory of the table
+)
+
+// Maglev maglev hashing with a lookup table, it is safe for concurrent use
+type Maglev struct {
+	mu    sync.RWMutex
+	ht    HashType
+	size  uint64
+	nodes []string
+	table []int32
+}
+
+// NewMaglev return a maglev hashing which uses ht to hash the nodes and keys
+// size: The size of the lookup table, it must be a prime not greater than 1<<24, if it is 0, 65537 is used
+func NewMaglev(ht HashType, size uint64) (*Maglev, error) {
+	if _, err := hashSum64(nil, ht); err != nil {
+		return nil, err
+	}
+	if size == 0 {
+		size = defaultMaglevSize
+	}
+	if size > maxMaglevSize || !isPrime(size) {
+		return nil, errors.New(sErrMaglevSizeInvalid)
+	}
+	return &Maglev{ht: ht, size: size}, nil
+}
+
+// Add add nodes and rebuild the lookup table, the existing nodes are ignored
+func (m *Maglev) Add(nodes ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, node := range nodes {
+		if idx := sort.SearchStrings(m.nodes, node); idx == len(m.nodes) || m.nodes[idx] != node {
+			m.nodes = append(m.nodes, node)
+			sort.Strings(m.nodes)
+		}
+	}
+	m.populate()
+}
+
+// Remove remove nodes and rebuild the lookup table
+func (m *Maglev) Remove(nodes ...string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, node := range nodes {
+		if idx := sort.SearchStrings(m.nodes, node); idx < len(m.nodes) && m.nodes[idx] == node {
+			m.nodes = append(m.nodes[:idx], m.nodes[idx+1:]...)
+		}
+	}
+	m.populate()
+}
+
+// Nodes return all nodes in ascending order
+func (m *Maglev) Nodes() []string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	return append([]string(nil), m.nodes...)
+}
+
+// Get return the node which the key belongs to, if there is no node, return ""
+func (m *Maglev) Get(key string) string {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	if len(m.nodes) == 0 {
+		return ""
+	}
+	h, _ := hashSum64([]byte(key), m.ht)
+	return m.nodes[m.table[mix64(h)%m.size]]
+}
+
+// populate rebuild the lookup table, the caller must hold the write lock
+func (m *Maglev) populate() {
+	n := len(m.nodes)
+	if n == 0 {
+		m.table = nil
+		return
+	}
+
+	offsets := make([]uint64, n)
+	skips := make([]uint64, n)
+	for i, node := range m.nodes {
+		h, _ := hashSum64([]byte(node), m.ht)
+		offsets[i] = mix64(h) % m.size
+		skips[i] = mix64(h^0x9e3779b97f4a7c15)%(m.size-1) + 1
+	}
+
+	table := make([]int32, m.size)
+	for i := range table {
+		table[i] = -1
+	}
+	next := make([]uint64, n)
+	for filled := uint64(0); ; {
+		for i := 0; i < n; i++ {
+			// Find the next empty entry in the permutation of node i
+			c := (offsets[i] + next[i]*skips[i]) % m.size
+			for table[c] >= 0 {
+				next[i]++
+				c = (offsets[i] + next[i]*skips[i]) % m.size
+			}
+			table[c] = int32(i)
+			next[i]++
+			filled++
+			if filled == m.size {
+				m.table = table
+				return
+			}
+		}
+	}
+}
+
+// isPrime return whether n is a prime
+func isPrime(n uint64) bool {
+	if n < 2 {
+		return false
+	}
+	for i := uint64(2); i*i <= n; i++ {
+		if n%i == 0 {
+			return false
+		}
+	}
+	return true
+}
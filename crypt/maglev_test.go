@@ -0,0 +1,96 @@
+package crypt
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestNewMaglev(t *testing.T) {
+	tests := []struct {
+		name    string
+		ht      HashType
+		size    uint64
+		wantErr bool
+	}{
+		{"Default", HtFnvA64, 0, false},
+		{"Prime", HtMD5, 251, false},
+		{"NotPrime", HtFnvA64, 100, true},
+		{"MaxPrime", HtFnvA64, 16777213, false},
+		{"TooLarge", HtFnvA64, 16777259, true},
+		{"HugePrime", HtFnvA64, 1<<61 - 1, true},
+		{"3Des", 0, 251, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewMaglev(tt.ht, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewMaglev() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestMaglev_Balance(t *testing.T) {
+	m, _ := NewMaglev(HtFnvA64, 0)
+	if got := m.Get("key"); got != "" {
+		t.Errorf("Get() with no node = %v", got)
+	}
+	m.Add("a", "b", "c", "d", "a")
+	if got := m.Nodes(); !reflect.DeepEqual(got, []string{"a", "b", "c", "d"}) {
+		t.Errorf("Nodes() = %v", got)
+	}
+
+	cnt := make(map[int32]int)
+	for _, idx := range m.table {
+		cnt[idx]++
+	}
+	for idx, c := range cnt {
+		if c < 65537/4-100 || c > 65537/4+100 {
+			t.Errorf("node %s owns %d entries", m.nodes[idx], c)
+		}
+	}
+}
+
+func TestMaglev_Remove(t *testing.T) {
+	m, _ := NewMaglev(HtCrc32, 0)
+	for i := 0; i < 10; i++ {
+		m.Add("node" + strconv.Itoa(i))
+	}
+
+	before := make(map[string]string)
+	for i := 0; i < 10000; i++ {
+		key := "key" + strconv.Itoa(i)
+		before[key] = m.Get(key)
+	}
+
+	m.Remove("node5", "node9")
+	moved := 0
+	for key, node := range before {
+		got := m.Get(key)
+		if got == "node5" || got == "node9" {
+			t.Fatalf("Get(%s) = %v after remove", key, got)
+		}
+		if node != "node5" && node != "node9" && got != node {
+			moved++
+		}
+	}
+	// Only a few keys of the remaining nodes should be moved
+	if moved > 500 {
+		t.Errorf("moved %d keys of the remaining nodes", moved)
+	}
+}
+
+func TestIsPrime(t *testing.T) {
+	tests := []struct {
+		n    uint64
+		want bool
+	}{
+		{0, false}, {1, false}, {2, true}, {9, false}, {251, true}, {65537, true}, {65535, false},
+	}
+	for _, tt := range tests {
+		if got := isPrime(tt.n); got != tt.want {
+			t.Errorf("isPrime(%d) = %v, want %v", tt.n, got, tt.want)
+		}
+	}
+}
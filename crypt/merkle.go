@@ -0,0 +1,235 @@
+package crypt
+
+import (
+	"bytes"
+	"errors"
+)
+
+// A Merkle tree hashes a list of leaves into one root hash, the root changes if any leaf changes, so that a small
+// proof can show a record is included in the tree with a published root, or a tree is an append-only extension of
+// an older one. The shape of the tree follows RFC 6962(Certificate Transparency): for n > 1 leaves, the left subtree
+// contains the first k leaves where k is the largest power of 2 smaller than n, the right subtree contains the rest.
+//  MTH({})       = HASH()
+//  MTH({d(0)})   = HASH(0x00 || d(0))
+//  MTH(D[n])     = HASH(0x01 || MTH(D[0:k]) || MTH(D[k:n]))
+// The prefix 0x00 and 0x01 are the domain separation of RFC 6962, which prevents the second preimage attack that
+// presents an inner node as a leaf. Without domain separation, the leaf is HASH(d) and the node is HASH(left||right).
+// The inclusion proof(audit path) and the consistency proof are generated and verified as RFC 6962 and RFC 9162.
+
+// MerkleTree a Merkle tree of leaves, it is not safe for concurrent use
+type MerkleTree struct {
+	ht      HashType
+	rfc6962 bool
+	leaves  [][]byte // the hashes of the leaves
+}
+
+// NewMerkleTree return a Merkle tree over leaves which uses ht to hash the leaves and nodes, rfc6962 enables the
+// domain separation of RFC 6962. ht must be supported by HashBytes
+func NewMerkleTree(leaves [][]byte, ht HashType, rfc6962 bool) (*MerkleTree, error) {
+	if _, err := HashBytesE(nil, ht); err != nil {
+		return nil, err
+	}
+	t := &MerkleTree{ht: ht, rfc6962: rfc6962, leaves: make([][]byte, 0, len(leaves))}
+	for _, leaf := range leaves {
+		t.Append(leaf)
+	}
+	return t, nil
+}
+
+// Append append a leaf to the tree
+func (t *MerkleTree) Append(leaf []byte) {
+	t.leaves = append(t.leaves, merkleLeafHash(leaf, t.ht, t.rfc6962))
+}
+
+// Size return the number of leaves
+func (t *MerkleTree) Size() int {
+	return len(t.leaves)
+}
+
+// LeafHash return the hash of the index-th leaf
+func (t *MerkleTree) LeafHash(index int) ([]byte, error) {
+	if index < 0 || index >= len(t.leaves) {
+		return nil, errors.New(sErrMerkleIndexInvalid)
+	}
+	return t.leaves[index], nil
+}
+
+// Root return the root hash of the tree
+func (t *MerkleTree) Root() []byte {
+	return t.subtreeRoot(0, len(t.leaves))
+}
+
+// RootAt return the root hash of the tree with the first size leaves, it is the root published when the tree had
+// size leaves
+func (t *MerkleTree) RootAt(size int) ([]byte, error) {
+	if size < 0 || size > len(t.leaves) {
+		return nil, errors.New(sErrMerkleIndexInvalid)
+	}
+	return t.subtreeRoot(0, size), nil
+}
+
+// InclusionProof return the inclusion proof(audit path) of the index-th leaf in the tree with the first size leaves,
+// index must be in the range of [0,size) and size must not be greater than Size()
+func (t *MerkleTree) InclusionProof(index, size int) ([][]byte, error) {
+	if index < 0 || index >= size || size > len(t.leaves) {
+		return nil, errors.New(sErrMerkleIndexInvalid)
+	}
+	return t.path(index, 0, size), nil
+}
+
+// ConsistencyProof return the consistency proof between the tree with the first oldSize leaves and the tree with the
+// first newSize leaves, 0 <= oldSize <= newSize <= Size()
+func (t *MerkleTree) ConsistencyProof(oldSize, newSize int) ([][]byte, error) {
+	if oldSize < 0 || oldSize > newSize || newSize > len(t.leaves) {
+		return nil, errors.New(sErrMerkleIndexInvalid)
+	}
+	if oldSize == 0 || oldSize == newSize {
+		return [][]byte{}, nil
+	}
+	return t.subproof(oldSize, 0, newSize, true), nil
+}
+
+// subtreeRoot return MTH(D[begin:end])
+func (t *MerkleTree) subtreeRoot(begin, end int) []byte {
+	n := end - begin
+	switch n {
+	case 0:
+		return HashBytes(nil, t.ht)
+	case 1:
+		return t.leaves[begin]
+	}
+	k := merkleSplit(n)
+	return merkleNodeHash(t.subtreeRoot(begin, begin+k), t.subtreeRoot(begin+k, end), t.ht, t.rfc6962)
+}
+
+// path return PATH(m, D[begin:end]) of RFC 6962
+func (t *MerkleTree) path(m, begin, end int) [][]byte {
+	n := end - begin
+	if n == 1 {
+		return [][]byte{}
+	}
+	k := merkleSplit(n)
+	if m < k {
+		return append(t.path(m, begin, begin+k), t.subtreeRoot(begin+k, end))
+	}
+	return append(t.path(m-k, begin+k, end), t.subtreeRoot(begin, begin+k))
+}
+
+// subproof return SUBPROOF(m, D[begin:end], b) of RFC 6962
+func (t *MerkleTree) subproof(m, begin, end int, b bool) [][]byte {
+	n := end - begin
+	if m == n {
+		if b {
+			return [][]byte{}
+		}
+		return [][]byte{t.subtreeRoot(begin, end)}
+	}
+	k := merkleSplit(n)
+	if m <= k {
+		return append(t.subproof(m, begin, begin+k, b), t.subtreeRoot(begin+k, end))
+	}
+	return append(t.subproof(m-k, begin+k, end, false), t.subtreeRoot(begin, begin+k))
+}
+
+// VerifyMerkleInclusion verify that leaf is the index-th leaf of the tree with size leaves and root hash root.
+// ht and rfc6962 must be the same as the tree
+func VerifyMerkleInclusion(leaf []byte, index, size int, proof [][]byte, root []byte, ht HashType, rfc6962 bool) bool {
+	if index < 0 || index >= size {
+		return false
+	}
+
+	fn, sn := index, size-1
+	r := merkleLeafHash(leaf, ht, rfc6962)
+	for _, p := range proof {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			r = merkleNodeHash(p, r, ht, rfc6962)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			r = merkleNodeHash(r, p, ht, rfc6962)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(r, root)
+}
+
+// VerifyMerkleConsistency verify that the tree with newSize leaves and root hash newRoot is an append-only extension
+// of the tree with oldSize leaves and root hash oldRoot. ht and rfc6962 must be the same as the tree
+func VerifyMerkleConsistency(oldSize, newSize int, oldRoot, newRoot []byte, proof [][]byte, ht HashType,
+	rfc6962 bool) bool {
+	switch {
+	case oldSize < 0 || oldSize > newSize:
+		return false
+	case oldSize == newSize:
+		return len(proof) == 0 && bytes.Equal(oldRoot, newRoot)
+	case oldSize == 0:
+		return len(proof) == 0
+	case len(proof) == 0:
+		return false
+	}
+
+	// If oldSize is an exact power of 2, the old root is a node of the new tree and it is omitted from the proof
+	if oldSize&(oldSize-1) == 0 {
+		proof = append([][]byte{oldRoot}, proof...)
+	}
+	fn, sn := oldSize-1, newSize-1
+	for fn&1 == 1 {
+		fn >>= 1
+		sn >>= 1
+	}
+
+	fr, sr := proof[0], proof[0]
+	for _, c := range proof[1:] {
+		if sn == 0 {
+			return false
+		}
+		if fn&1 == 1 || fn == sn {
+			fr = merkleNodeHash(c, fr, ht, rfc6962)
+			sr = merkleNodeHash(c, sr, ht, rfc6962)
+			for fn&1 == 0 && fn != 0 {
+				fn >>= 1
+				sn >>= 1
+			}
+		} else {
+			sr = merkleNodeHash(sr, c, ht, rfc6962)
+		}
+		fn >>= 1
+		sn >>= 1
+	}
+	return sn == 0 && bytes.Equal(fr, oldRoot) && bytes.Equal(sr, newRoot)
+}
+
+// merkleSplit return the largest power of 2 smaller than n, n must be greater than 1
+func merkleSplit(n int) int {
+	k := 1
+	for k<<1 < n {
+		k <<= 1
+	}
+	return k
+}
+
+// merkleLeafHash return the hash of a leaf
+func merkleLeafHash(leaf []byte, ht HashType, rfc6962 bool) []byte {
+	if !rfc6962 {
+		return HashBytes(leaf, ht)
+	}
+	data := make([]byte, 0, len(leaf)+1)
+	data = append(data, 0x00)
+	return HashBytes(append(data, leaf...), ht)
+}
+
+// merkleNodeHash return the hash of an inner node
+func merkleNodeHash(left, right []byte, ht HashType, rfc6962 bool) []byte {
+	data := make([]byte, 0, len(left)+len(right)+1)
+	if rfc6962 {
+		data = append(data, 0x01)
+	}
+	data = append(data, left...)
+	return HashBytes(append(data, right...), ht)
+}
@@ -0,0 +1,189 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"reflect"
+	"testing"
+)
+
+// The test vectors of RFC 6962 from certificate-transparency
+var merkleLeavesTest = [][]byte{
+	{},
+	{0x00},
+	{0x10},
+	{0x20, 0x21},
+	{0x30, 0x31},
+	{0x40, 0x41, 0x42, 0x43},
+	{0x50, 0x51, 0x52, 0x53, 0x54, 0x55, 0x56, 0x57},
+	{0x60, 0x61, 0x62, 0x63, 0x64, 0x65, 0x66, 0x67, 0x68, 0x69, 0x6a, 0x6b, 0x6c, 0x6d, 0x6e, 0x6f},
+}
+
+var merkleRootsTest = []string{
+	"e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855",
+	"6e340b9cffb37a989ca544e6bb780a2c78901d3fb33738768511a30617afa01d",
+	"fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125",
+	"aeb6bcfe274b70a14fb067a5e5578264db0fa9b51af5e0ba159158f329e06e77",
+	"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7",
+	"4e3bbb1f7b478dcfe71fb631631519a3bca12c9aefca1612bfce4c13a86264d4",
+	"76e67dadbcdf1e10e1b74ddc608abd2f98dfb16fbce75277b5232a127f2087ef",
+	"ddb89be403809e325750d3d263cd78929c2942b7942a34b77e122c9594a74c8c",
+	"5dc9da79a70659a9ad559cb701ded9a2ab9d823aad2f4960cfe370eff4604328",
+}
+
+func merkleHexList(list ...string) [][]byte {
+	res := make([][]byte, len(list))
+	for i, s := range list {
+		res[i], _ = hex.DecodeString(s)
+	}
+	return res
+}
+
+func TestMerkleTree_Root(t *testing.T) {
+	if _, err := NewMerkleTree(nil, 0, true); err == nil {
+		t.Errorf("NewMerkleTree() expect error for unsupported type")
+	}
+	for i, want := range merkleRootsTest {
+		tree, _ := NewMerkleTree(merkleLeavesTest[:i], HtSha256, true)
+		if got := hex.EncodeToString(tree.Root()); got != want {
+			t.Errorf("Root(%d) = %v, want %v", i, got, want)
+		}
+	}
+
+	tree, _ := NewMerkleTree(merkleLeavesTest, HtSha256, true)
+	for i, want := range merkleRootsTest {
+		if got, _ := tree.RootAt(i); hex.EncodeToString(got) != want {
+			t.Errorf("RootAt(%d) = %x, want %v", i, got, want)
+		}
+	}
+	if _, err := tree.RootAt(9); err == nil {
+		t.Errorf("RootAt(9) expect error")
+	}
+	if _, err := tree.LeafHash(8); err == nil {
+		t.Errorf("LeafHash(8) expect error")
+	}
+}
+
+func TestMerkleTree_InclusionProof(t *testing.T) {
+	tree, _ := NewMerkleTree(merkleLeavesTest, HtSha256, true)
+	tests := []struct {
+		index, size int
+		want        [][]byte
+	}{
+		{0, 1, [][]byte{}},
+		{0, 8, merkleHexList("96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+			"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+			"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4")},
+		{5, 8, merkleHexList("bc1a0643b12e4d2d7c77918f44e0f4f79a838b6cf9ec5b5c283e1f4d88599e6b",
+			"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+			"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7")},
+		{2, 3, merkleHexList("fac54203e7cc696cf0dfcb42c92a1d9dbaf70ad9e621f4bd8d98662f00e3c125")},
+	}
+	for _, tt := range tests {
+		got, err := tree.InclusionProof(tt.index, tt.size)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("InclusionProof(%d, %d) = %x, %v, want %x", tt.index, tt.size, got, err, tt.want)
+		}
+	}
+	for _, invalid := range [][2]int{{-1, 1}, {1, 1}, {0, 9}} {
+		if _, err := tree.InclusionProof(invalid[0], invalid[1]); err == nil {
+			t.Errorf("InclusionProof(%v) expect error", invalid)
+		}
+	}
+}
+
+func TestVerifyMerkleInclusion(t *testing.T) {
+	for _, rfc6962 := range []bool{true, false} {
+		tree, _ := NewMerkleTree(merkleLeavesTest, HtSha1, rfc6962)
+		for size := 1; size <= tree.Size(); size++ {
+			root, _ := tree.RootAt(size)
+			for index := 0; index < size; index++ {
+				proof, _ := tree.InclusionProof(index, size)
+				leaf := merkleLeavesTest[index]
+				if !VerifyMerkleInclusion(leaf, index, size, proof, root, HtSha1, rfc6962) {
+					t.Errorf("VerifyMerkleInclusion(%d, %d) = false", index, size)
+				}
+				if VerifyMerkleInclusion([]byte("x"), index, size, proof, root, HtSha1, rfc6962) {
+					t.Errorf("VerifyMerkleInclusion(%d, %d) = true for wrong leaf", index, size)
+				}
+				if size > 1 && VerifyMerkleInclusion(leaf, index, size, proof[1:], root, HtSha1, rfc6962) {
+					t.Errorf("VerifyMerkleInclusion(%d, %d) = true for short proof", index, size)
+				}
+				if VerifyMerkleInclusion(leaf, index, size, append(proof, root), root, HtSha1, rfc6962) {
+					t.Errorf("VerifyMerkleInclusion(%d, %d) = true for long proof", index, size)
+				}
+				if VerifyMerkleInclusion(leaf, index, size, proof, root, HtSha1, !rfc6962) {
+					t.Errorf("VerifyMerkleInclusion(%d, %d) = true for wrong domain separation", index, size)
+				}
+			}
+		}
+	}
+	if VerifyMerkleInclusion(nil, 1, 1, nil, nil, HtSha256, true) {
+		t.Errorf("VerifyMerkleInclusion() = true for invalid index")
+	}
+}
+
+func TestMerkleTree_ConsistencyProof(t *testing.T) {
+	tree, _ := NewMerkleTree(merkleLeavesTest, HtSha256, true)
+	tests := []struct {
+		oldSize, newSize int
+		want             [][]byte
+	}{
+		{1, 1, [][]byte{}},
+		{1, 8, merkleHexList("96a296d224f285c67bee93c30f8a309157f0daa35dc5b87e410b78630a09cfc7",
+			"5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+			"6b47aaf29ee3c2af9af889bc1fb9254dabd31177f16232dd6aab035ca39bf6e4")},
+		{6, 8, merkleHexList("0ebc5d3437fbe2db158b9f126a1d118e308181031d0a949f8dededebc558ef6a",
+			"ca854ea128ed050b41b35ffc1b87b8eb2bde461e9e3b5596ece6b9d5975a0ae0",
+			"d37ee418976dd95753c1c73862b9398fa2a2cf9b4ff0fdfe8b30cd95209614b7")},
+		{2, 5, merkleHexList("5f083f0a1a33ca076a95279832580db3e0ef4584bdff1f54c8a360f50de3031e",
+			"bc1a0643b12e4d2d7c77918f44e0f4f79a838b6cf9ec5b5c283e1f4d88599e6b")},
+	}
+	for _, tt := range tests {
+		got, err := tree.ConsistencyProof(tt.oldSize, tt.newSize)
+		if err != nil || !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ConsistencyProof(%d, %d) = %x, %v, want %x", tt.oldSize, tt.newSize, got, err, tt.want)
+		}
+	}
+	if _, err := tree.ConsistencyProof(3, 2); err == nil {
+		t.Errorf("ConsistencyProof(3, 2) expect error")
+	}
+}
+
+func TestVerifyMerkleConsistency(t *testing.T) {
+	for _, rfc6962 := range []bool{true, false} {
+		tree, _ := NewMerkleTree(nil, HtSha256, rfc6962)
+		for _, leaf := range merkleLeavesTest {
+			tree.Append(leaf)
+		}
+		for newSize := 0; newSize <= tree.Size(); newSize++ {
+			newRoot, _ := tree.RootAt(newSize)
+			for oldSize := 0; oldSize <= newSize; oldSize++ {
+				oldRoot, _ := tree.RootAt(oldSize)
+				proof, _ := tree.ConsistencyProof(oldSize, newSize)
+				if !VerifyMerkleConsistency(oldSize, newSize, oldRoot, newRoot, proof, HtSha256, rfc6962) {
+					t.Errorf("VerifyMerkleConsistency(%d, %d) = false", oldSize, newSize)
+				}
+				if oldSize == 0 || oldSize == newSize {
+					continue
+				}
+				wrong := HashBytes(oldRoot, HtSha256)
+				if VerifyMerkleConsistency(oldSize, newSize, wrong, newRoot, proof, HtSha256, rfc6962) {
+					t.Errorf("VerifyMerkleConsistency(%d, %d) = true for wrong old root", oldSize, newSize)
+				}
+				if VerifyMerkleConsistency(oldSize, newSize, oldRoot, wrong, proof, HtSha256, rfc6962) {
+					t.Errorf("VerifyMerkleConsistency(%d, %d) = true for wrong new root", oldSize, newSize)
+				}
+				if VerifyMerkleConsistency(oldSize, newSize, oldRoot, newRoot, proof[1:], HtSha256, rfc6962) {
+					t.Errorf("VerifyMerkleConsistency(%d, %d) = true for short proof", oldSize, newSize)
+				}
+				if VerifyMerkleConsistency(oldSize, newSize, oldRoot, newRoot, append(proof, wrong), HtSha256,
+					rfc6962) {
+					t.Errorf("VerifyMerkleConsistency(%d, %d) = true for long proof", oldSize, newSize)
+				}
+			}
+		}
+	}
+	if VerifyMerkleConsistency(2, 1, nil, nil, nil, HtSha256, true) {
+		t.Errorf("VerifyMerkleConsistency() = true for invalid size")
+	}
+}
@@ -0,0 +1,246 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"errors"
+	"math"
+	"math/bits"
+	"math/rand"
+	"sort"
+	"sync"
+)
+
+// MinHash (Broder, 1997) estimates the Jaccard similarity |A∩B|/|A∪B| of two sets: for a random permutation of the
+// universe, the probability that the minimum elements of A and B are the same equals their Jaccard similarity.
+// A signature keeps the minimum values under n hash functions h_i(x) = (a_i*x + b_i) mod (2^61-1), x is the hash of
+// the token by HashType, the fraction of equal values in two signatures is the estimated similarity.
+//
+// Locality-sensitive hashing(LSH) divides a signature into b bands of r rows, the documents which have the same
+// values in any band are candidates, so the similar documents can be found without comparing all pairs. The
+// probability of becoming candidates is 1-(1-s^r)^b for the similarity s, the threshold is about (1/b)^(1/r).
+
+// minHashPrime the Mersenne prime 2^61-1
+const minHashPrime = 1<<61 - 1
+
+// MinHasher compute the MinHash signatures, it is safe for concurrent use
+type MinHasher struct {
+	ht HashType
+	a  []uint64
+	b  []uint64
+}
+
+// NewMinHasher return a MinHasher with numHashes hash functions which uses ht to hash the tokens.
+// The hash functions are generated from seed, the signatures are comparable only if they are computed by the
+// MinHashers with the same numHashes, seed and ht
+func NewMinHasher(numHashes int, seed int64, ht HashType) (*MinHasher, error) {
+	if numHashes <= 0 {
+		return nil, errors.New(sErrMinHashParamInvalid)
+	}
+	if _, err := hashSum64(nil, ht); err != nil {
+		return nil, err
+	}
+
+	r := rand.New(rand.NewSource(seed))
+	mh := &MinHasher{ht: ht, a: make([]uint64, numHashes), b: make([]uint64, numHashes)}
+	for i := 0; i < numHashes; i++ {
+		mh.a[i] = uint64(r.Int63n(minHashPrime-1)) + 1
+		mh.b[i] = uint64(r.Int63n(minHashPrime))
+	}
+	return mh, nil
+}
+
+// Signature return the MinHash signature of the tokens, the duplicate tokens are ignored.
+// The signature of empty tokens is all math.MaxUint64
+func (mh *MinHasher) Signature(tokens []string) []uint64 {
+	sig := make([]uint64, len(mh.a))
+	for i := range sig {
+		sig[i] = math.MaxUint64
+	}
+	for _, token := range tokens {
+		x, _ := hashSum64([]byte(token), mh.ht)
+		x = mix64(x) % minHashPrime
+		for i := range sig {
+			if v := mulAddMod61(mh.a[i], x, mh.b[i]); v < sig[i] {
+				sig[i] = v
+			}
+		}
+	}
+	return sig
+}
+
+// SignatureText return the MinHash signature of the text split by tokenizer, if tokenizer is nil,
+// NewShingleTokenizer(3) is used
+func (mh *MinHasher) SignatureText(text string, tokenizer Tokenizer) []uint64 {
+	if tokenizer == nil {
+		tokenizer = NewShingleTokenizer(3)
+	}
+	return mh.Signature(tokenizer(text))
+}
+
+// mulAddMod61 return (a*x + b) mod (2^61-1), a、x and b must be less than 2^61-1
+func mulAddMod61(a, x, b uint64) uint64 {
+	hi, lo := bits.Mul64(a, x)
+	lo, carry := bits.Add64(lo, b, 0)
+	hi += carry
+	// (hi*2^64 + lo) mod (2^61-1) = (hi*2^3 + lo>>61 + lo&(2^61-1)) mod (2^61-1)
+	v := (hi << 3) + (lo >> 61) + (lo & minHashPrime)
+	v = (v & minHashPrime) + (v >> 61)
+	if v >= minHashPrime {
+		v -= minHashPrime
+	}
+	return v
+}
+
+// MinHashSimilarity return the estimated Jaccard similarity of two signatures, the fraction of equal values.
+// If the lengths of the signatures are different or 0, return 0
+func MinHashSimilarity(a, b []uint64) float64 {
+	if len(a) != len(b) || len(a) == 0 {
+		return 0
+	}
+	eq := 0
+	for i := range a {
+		if a[i] == b[i] {
+			eq++
+		}
+	}
+	return float64(eq) / float64(len(a))
+}
+
+// JaccardSimilarity return the exact Jaccard similarity of two sets of tokens, the duplicate tokens are ignored.
+// If both are empty, return 1
+func JaccardSimilarity(a, b []string) float64 {
+	setA := make(map[string]struct{}, len(a))
+	for _, s := range a {
+		setA[s] = struct{}{}
+	}
+	setB := make(map[string]struct{}, len(b))
+	inter := 0
+	for _, s := range b {
+		if _, ok := setB[s]; ok {
+			continue
+		}
+		setB[s] = struct{}{}
+		if _, ok := setA[s]; ok {
+			inter++
+		}
+	}
+	union := len(setA) + len(setB) - inter
+	if union == 0 {
+		return 1
+	}
+	return float64(inter) / float64(union)
+}
+
+// MinHashLSH an index of MinHash signatures with LSH banding, it is safe for concurrent use
+type MinHashLSH struct {
+	mu      sync.RWMutex
+	bands   int
+	rows    int
+	buckets []map[uint64][]string
+	keys    map[string][]uint64
+}
+
+// NewMinHashLSH return a LSH index with bands bands of rows rows, the length of the signatures must be bands*rows
+func NewMinHashLSH(bands, rows int) (*MinHashLSH, error) {
+	if bands <= 0 || rows <= 0 {
+		return nil, errors.New(sErrMinHashParamInvalid)
+	}
+	lsh := &MinHashLSH{bands: bands, rows: rows, buckets: make([]map[uint64][]string, bands),
+		keys: make(map[string][]uint64)}
+	for i := range lsh.buckets {
+		lsh.buckets[i] = make(map[uint64][]string)
+	}
+	return lsh, nil
+}
+
+// Threshold return the approximate similarity threshold (1/b)^(1/r) of the index, the pairs with a higher
+// similarity are likely to be candidates
+func (lsh *MinHashLSH) Threshold() float64 {
+	return math.Pow(1/float64(lsh.bands), 1/float64(lsh.rows))
+}
+
+// Insert insert the signature of the document id, if id already exists, its signature is replaced
+func (lsh *MinHashLSH) Insert(id string, sig []uint64) error {
+	keys, err := lsh.bandKeys(sig)
+	if err != nil {
+		return err
+	}
+
+	lsh.mu.Lock()
+	defer lsh.mu.Unlock()
+	lsh.remove(id)
+	for i, key := range keys {
+		lsh.buckets[i][key] = append(lsh.buckets[i][key], id)
+	}
+	lsh.keys[id] = keys
+	return nil
+}
+
+// Remove remove the document id
+func (lsh *MinHashLSH) Remove(id string) {
+	lsh.mu.Lock()
+	defer lsh.mu.Unlock()
+	lsh.remove(id)
+}
+
+// Query return the ids of the candidate documents in ascending order which have the same values as sig in any band
+func (lsh *MinHashLSH) Query(sig []uint64) ([]string, error) {
+	keys, err := lsh.bandKeys(sig)
+	if err != nil {
+		return nil, err
+	}
+
+	lsh.mu.RLock()
+	defer lsh.mu.RUnlock()
+	seen := make(map[string]struct{})
+	var ids []string
+	for i, key := range keys {
+		for _, id := range lsh.buckets[i][key] {
+			if _, ok := seen[id]; !ok {
+				seen[id] = struct{}{}
+				ids = append(ids, id)
+			}
+		}
+	}
+	sort.Strings(ids)
+	return ids, nil
+}
+
+// remove remove the document id, the caller must hold the write lock
+func (lsh *MinHashLSH) remove(id string) {
+	keys, ok := lsh.keys[id]
+	if !ok {
+		return
+	}
+	for i, key := range keys {
+		bucket := lsh.buckets[i][key]
+		for j := range bucket {
+			if bucket[j] == id {
+				bucket = append(bucket[:j], bucket[j+1:]...)
+				break
+			}
+		}
+		if len(bucket) == 0 {
+			delete(lsh.buckets[i], key)
+		} else {
+			lsh.buckets[i][key] = bucket
+		}
+	}
+	delete(lsh.keys, id)
+}
+
+// bandKeys return the hash of every band of the signature
+func (lsh *MinHashLSH) bandKeys(sig []uint64) ([]uint64, error) {
+	if len(sig) != lsh.bands*lsh.rows {
+		return nil, errors.New(sErrMinHashParamInvalid)
+	}
+	keys := make([]uint64, lsh.bands)
+	buf := make([]byte, lsh.rows*8)
+	for i := range keys {
+		for j, v := range sig[i*lsh.rows : (i+1)*lsh.rows] {
+			binary.BigEndian.PutUint64(buf[j*8:], v)
+		}
+		keys[i] = HashUInt64(buf, HtFnvA64)
+	}
+	return keys, nil
+}
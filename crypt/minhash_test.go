@@ -0,0 +1,106 @@
+package crypt
+
+import (
+	"math"
+	"math/big"
+	"reflect"
+	"testing"
+)
+
+func TestNewMinHasher(t *testing.T) {
+	if _, err := NewMinHasher(0, 1, HtFnvA64); err == nil {
+		t.Errorf("NewMinHasher() expect error for numHashes 0")
+	}
+	if _, err := NewMinHasher(128, 1, 0); err == nil {
+		t.Errorf("NewMinHasher() expect error for unsupported type")
+	}
+	a, _ := NewMinHasher(64, 42, HtFnvA64)
+	b, _ := NewMinHasher(64, 42, HtFnvA64)
+	if !reflect.DeepEqual(a.SignatureText(simHashDocs[0], nil), b.SignatureText(simHashDocs[0], nil)) {
+		t.Errorf("Signature() is not reproducible with the same seed")
+	}
+}
+
+func TestMinHashSimilarity(t *testing.T) {
+	mh, _ := NewMinHasher(256, 1, HtFnvA64)
+	tokenizer := NewShingleTokenizer(3)
+	for i := 0; i < len(simHashDocs); i++ {
+		for j := i + 1; j < len(simHashDocs); j++ {
+			ta, tb := tokenizer(simHashDocs[i]), tokenizer(simHashDocs[j])
+			want := JaccardSimilarity(ta, tb)
+			got := MinHashSimilarity(mh.Signature(ta), mh.Signature(tb))
+			if math.Abs(got-want) > 0.1 {
+				t.Errorf("MinHashSimilarity(%d, %d) = %v, want about %v", i, j, got, want)
+			}
+		}
+	}
+	if got := MinHashSimilarity([]uint64{1}, []uint64{1, 2}); got != 0 {
+		t.Errorf("MinHashSimilarity() = %v, want 0", got)
+	}
+}
+
+func TestJaccardSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b []string
+		want float64
+	}{
+		{nil, nil, 1},
+		{[]string{"a"}, nil, 0},
+		{[]string{"a", "b", "c"}, []string{"b", "c", "d", "d"}, 0.5},
+	}
+	for _, tt := range tests {
+		if got := JaccardSimilarity(tt.a, tt.b); got != tt.want {
+			t.Errorf("JaccardSimilarity(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestMulAddMod61(t *testing.T) {
+	p := new(big.Int).SetUint64(minHashPrime)
+	tests := [][3]uint64{{0, 0, 0}, {1, 2, 3}, {minHashPrime - 1, minHashPrime - 1, minHashPrime - 1},
+		{0x123456789abcdef, 0x0fedcba987654321, 0x1000000000000000}}
+	for _, tt := range tests {
+		want := new(big.Int).SetUint64(tt[0])
+		want.Mul(want, new(big.Int).SetUint64(tt[1])).Add(want, new(big.Int).SetUint64(tt[2])).Mod(want, p)
+		if got := mulAddMod61(tt[0], tt[1], tt[2]); got != want.Uint64() {
+			t.Errorf("mulAddMod61(%v) = %v, want %v", tt, got, want)
+		}
+	}
+}
+
+func TestMinHashLSH(t *testing.T) {
+	if _, err := NewMinHashLSH(0, 4); err == nil {
+		t.Errorf("NewMinHashLSH() expect error")
+	}
+	lsh, _ := NewMinHashLSH(32, 4)
+	if got := lsh.Threshold(); math.Abs(got-0.42) > 0.01 {
+		t.Errorf("Threshold() = %v", got)
+	}
+
+	mh, _ := NewMinHasher(128, 7, HtFnvA64)
+	for i, doc := range simHashDocs {
+		if err := lsh.Insert(string(rune('a'+i)), mh.SignatureText(doc, nil)); err != nil {
+			t.Fatalf("Insert() error = %v", err)
+		}
+	}
+	if err := lsh.Insert("x", []uint64{1, 2}); err == nil {
+		t.Errorf("Insert() expect error for wrong signature length")
+	}
+
+	got, err := lsh.Query(mh.SignatureText(simHashDocs[0], nil))
+	if err != nil || !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Query() = %v, %v, want [a b]", got, err)
+	}
+
+	lsh.Remove("b")
+	// Insert the same id again replaces the old signature
+	_ = lsh.Insert("a", mh.SignatureText(simHashDocs[2], nil))
+	got, _ = lsh.Query(mh.SignatureText(simHashDocs[1], nil))
+	if len(got) != 0 {
+		t.Errorf("Query() = %v, want []", got)
+	}
+	got, _ = lsh.Query(mh.SignatureText(simHashDocs[2], nil))
+	if !reflect.DeepEqual(got, []string{"a", "c"}) {
+		t.Errorf("Query() = %v, want [a c]", got)
+	}
+}
@@ -0,0 +1,109 @@
+package crypt
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"io"
+	"math"
+	"math/bits"
+)
+
+// NanoID is a URL-safe random ID compatible with the JavaScript nanoid, the default is 21 characters of
+// NanoIDAlphabet(126 random bits, about the same as UUID v4). The characters are chosen uniformly from the alphabet
+// by the masking: every random byte from crypto/rand is masked to the smallest 2^k-1 not less than the alphabet size
+// minus 1, and the values out of the alphabet are dropped, so there is no modulo bias.
+// The alphabet can be any characters(including the non-ASCII ones), such as NanoIDNoLookalikes which has no
+// ambiguous characters like 0/O and 1/l. Use NanoIDCollisionProbability to choose the length.
+
+const (
+	// NanoIDAlphabet the default alphabet, A-Za-z0-9_-
+	NanoIDAlphabet = "useandom-26T198340PX75pxJACKVERYMINDBUSHWOLF_GQZbfghjklqvwyzrict"
+	// NanoIDNoLookalikes the alphabet without the lookalike characters 1、l、I、0、O、o、u、v、5、S、s、2 and Z
+	NanoIDNoLookalikes = "346789ABCDEFGHJKLMNPQRTUVWXYabcdefghijkmnpqrtwxyz"
+	// NanoIDNumbers the alphabet of the numbers
+	NanoIDNumbers = "0123456789"
+	// NanoIDDefaultSize the default length of NanoID
+	NanoIDDefaultSize = 21
+	// nanoIDMaxAlphabet the maximum size of the alphabet, a random byte is used for each character
+	nanoIDMaxAlphabet = 256
+)
+
+// NanoIDGenerator generate the NanoIDs of an alphabet and a length, it is safe for concurrent use
+type NanoIDGenerator struct {
+	alphabet []rune
+	size     int
+	mask     byte
+	step     int
+}
+
+// NewNanoIDGenerator return a generator of the alphabet and the length size. The alphabet must have 2 to 256
+// different characters, and size must be positive
+func NewNanoIDGenerator(alphabet string, size int) (*NanoIDGenerator, error) {
+	runes := []rune(alphabet)
+	if len(runes) < 2 || len(runes) > nanoIDMaxAlphabet {
+		return nil, errors.New(sErrNanoIDAlphabetInvalid)
+	}
+	seen := make(map[rune]bool, len(runes))
+	for _, r := range runes {
+		if seen[r] {
+			return nil, errors.New(sErrNanoIDAlphabetInvalid)
+		}
+		seen[r] = true
+	}
+	if size <= 0 {
+		return nil, errors.New(sErrNanoIDSizeInvalid)
+	}
+
+	mask := 1<<uint(bits.Len(uint(len(runes)-1))) - 1
+	// The bytes read at once, 1.6 is the empirical factor of nanoid, it makes a second read rare
+	step := int(math.Ceil(1.6 * float64(mask) * float64(size) / float64(len(runes))))
+	return &NanoIDGenerator{alphabet: runes, size: size, mask: byte(mask), step: step}, nil
+}
+
+// New return a NanoID
+func (g *NanoIDGenerator) New() (string, error) {
+	id := make([]rune, 0, g.size)
+	buf := make([]byte, g.step)
+	for {
+		if _, err := io.ReadFull(crand.Reader, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if idx := int(b & g.mask); idx < len(g.alphabet) {
+				id = append(id, g.alphabet[idx])
+				if len(id) == g.size {
+					return string(id), nil
+				}
+			}
+		}
+	}
+}
+
+// defaultNanoIDGenerator the generator of NanoID
+var defaultNanoIDGenerator, _ = NewNanoIDGenerator(NanoIDAlphabet, NanoIDDefaultSize)
+
+// NanoID return a NanoID of 21 characters of NanoIDAlphabet
+func NanoID() (string, error) {
+	return defaultNanoIDGenerator.New()
+}
+
+// NanoIDCustom return a NanoID of the alphabet and the length size, NewNanoIDGenerator is faster for repeated calls
+func NanoIDCustom(alphabet string, size int) (string, error) {
+	g, err := NewNanoIDGenerator(alphabet, size)
+	if err != nil {
+		return "", err
+	}
+	return g.New()
+}
+
+// NanoIDCollisionProbability return the probability that at least two of count IDs are the same, the IDs have size
+// characters from an alphabet of alphabetSize characters. It uses the birthday approximation 1 - e^(-n(n-1)/2N),
+// N = alphabetSize^size, such as: NanoIDCollisionProbability(64, 21, 1e9) is about 6e-21
+func NanoIDCollisionProbability(alphabetSize, size int, count float64) float64 {
+	if alphabetSize < 2 || size <= 0 || count < 2 {
+		return 0
+	}
+	// log(n(n-1)/2N) avoids the overflow of N
+	logRatio := math.Log(count) + math.Log(count-1) - math.Log(2) - float64(size)*math.Log(float64(alphabetSize))
+	return -math.Expm1(-math.Exp(logRatio))
+}
@@ -0,0 +1,120 @@
+package crypt
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestNanoID(t *testing.T) {
+	seen := make(map[string]bool)
+	for i := 0; i < 1000; i++ {
+		id, err := NanoID()
+		if err != nil {
+			t.Fatalf("NanoID() error = %v", err)
+		}
+		if len(id) != NanoIDDefaultSize {
+			t.Fatalf("NanoID() = %v, want %d characters", id, NanoIDDefaultSize)
+		}
+		for _, c := range id {
+			if !strings.ContainsRune(NanoIDAlphabet, c) {
+				t.Fatalf("NanoID() = %v contains %q", id, c)
+			}
+		}
+		if seen[id] {
+			t.Fatalf("NanoID() = %v is duplicated", id)
+		}
+		seen[id] = true
+	}
+}
+
+func TestNanoIDCustom(t *testing.T) {
+	tests := []struct {
+		name     string
+		alphabet string
+		size     int
+		wantErr  bool
+	}{
+		{name: "NoLookalikes", alphabet: NanoIDNoLookalikes, size: 12},
+		{name: "Numbers", alphabet: NanoIDNumbers, size: 6},
+		{name: "Binary", alphabet: "01", size: 64},
+		{name: "Unicode", alphabet: "αβγδεζηθ", size: 10},
+		{name: "TooShort", alphabet: "a", size: 10, wantErr: true},
+		{name: "Duplicate", alphabet: "abca", size: 10, wantErr: true},
+		{name: "TooLong", alphabet: strings.Repeat("a", 257), size: 10, wantErr: true},
+		{name: "ZeroSize", alphabet: NanoIDNumbers, size: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NanoIDCustom(tt.alphabet, tt.size)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NanoIDCustom() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if utf8.RuneCountInString(got) != tt.size {
+				t.Errorf("NanoIDCustom() = %v, want %d characters", got, tt.size)
+			}
+			for _, c := range got {
+				if !strings.ContainsRune(tt.alphabet, c) {
+					t.Errorf("NanoIDCustom() = %v contains %q", got, c)
+				}
+			}
+		})
+	}
+}
+
+func TestNanoIDUniform(t *testing.T) {
+	// 3 characters, the mask is 3 and the value 3 is dropped
+	g, err := NewNanoIDGenerator("abc", 30000)
+	if err != nil {
+		t.Fatalf("NewNanoIDGenerator() error = %v", err)
+	}
+	if g.mask != 3 {
+		t.Errorf("mask = %v, want 3", g.mask)
+	}
+	id, _ := g.New()
+	for _, c := range "abc" {
+		// The expected count is 10000, the standard deviation is about 82
+		if n := strings.Count(id, string(c)); n < 9500 || n > 10500 {
+			t.Errorf("the count of %q = %v, want about 10000", c, n)
+		}
+	}
+
+	full, _ := NewNanoIDGenerator(string(func() []rune {
+		r := make([]rune, 256)
+		for i := range r {
+			r[i] = rune(0x4E00 + i)
+		}
+		return r
+	}()), 8)
+	if full.mask != 0xFF {
+		t.Errorf("mask of 256 characters = %v, want 255", full.mask)
+	}
+}
+
+func TestNanoIDCollisionProbability(t *testing.T) {
+	tests := []struct {
+		name         string
+		alphabetSize int
+		size         int
+		count        float64
+		want         float64
+	}{
+		{name: "Default", alphabetSize: 64, size: 21, count: 1e9, want: 5.877471748233964e-21},
+		{name: "Birthday", alphabetSize: 365, size: 1, count: 23, want: 0.5000017521827107},
+		{name: "Certain", alphabetSize: 10, size: 2, count: 1e4, want: 1},
+		{name: "One", alphabetSize: 64, size: 21, count: 1, want: 0},
+		{name: "Invalid", alphabetSize: 1, size: 21, count: 100, want: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NanoIDCollisionProbability(tt.alphabetSize, tt.size, tt.count)
+			if math.Abs(got-tt.want) > tt.want*1e-9 {
+				t.Errorf("NanoIDCollisionProbability() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
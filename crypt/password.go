@@ -0,0 +1,315 @@
+package crypt
+
+import (
+	"errors"
+	"strings"
+	"unicode"
+)
+
+// GeneratePassword generate the passwords satisfying a PasswordPolicy, the randomness is read from crypto/rand.
+// There are three modes:
+//  PasswordModeRandom:        Length characters of the classes in Scope, at least MinLower lowercase letters、
+//                             MinUpper uppercase letters、MinNumber numbers and MinSpecial special characters
+//  PasswordModePronounceable: alternate consonants and vowels, MinUpper letters are capitalized, MinNumber numbers
+//                             and MinSpecial special characters are appended, the length is Length
+//  PasswordModePassphrase:    WordCount random words of Words joined by Separator, MinUpper words are capitalized,
+//                             MinNumber numbers and MinSpecial special characters are appended to a random word
+// The characters in Exclude never appear in the password, the words containing them are dropped from Words.
+// In PasswordModeRandom, the minimum characters of the classes are chosen first and all characters are shuffled at
+// last, so the positions of the classes are random.
+
+const (
+	passwordDefaultLength    = 16
+	passwordDefaultWordCount = 6
+	passwordDefaultSeparator = "-"
+	passwordConsonants       = "bcdfghjklmnpqrstvwxyz"
+	passwordVowels           = "aeiou"
+)
+
+// PasswordPolicy the constraints of the password, the zero values are replaced by the defaults
+type PasswordPolicy struct {
+	Mode       PasswordMode // default PasswordModeRandom
+	Length     int          // the length of the password, default 16, not used in PasswordModePassphrase
+	Scope      ScopeType    // the character classes of PasswordModeRandom, default AllLetter
+	MinLower   int          // the minimum lowercase letters
+	MinUpper   int          // the minimum uppercase letters
+	MinNumber  int          // the minimum numbers
+	MinSpecial int          // the minimum special characters
+	Special    string       // the special characters, default ~!@#$%^&*()_,.<>{}=-+
+	Exclude    string       // the characters never used, such as the ambiguous characters 0O1lI
+	NoRepeat   bool         // every character appears at most once, only in PasswordModeRandom
+	Words      []string     // the word list of PasswordModePassphrase, such as the EFF long word list
+	WordCount  int          // the number of words of PasswordModePassphrase, default 6
+	Separator  string       // the separator of the words of PasswordModePassphrase, default "-"
+}
+
+// GeneratePassword return a password satisfying policy, an error is returned if the policy can not be satisfied
+func GeneratePassword(policy PasswordPolicy) (string, error) {
+	return generatePassword(policy, SecureRandIntN)
+}
+
+// generatePassword return a password satisfying p, intn return a random integer in [0, n-1]
+func generatePassword(p PasswordPolicy, intn func(n int) (int, error)) (string, error) {
+	if p.Length == 0 {
+		p.Length = passwordDefaultLength
+	}
+	if p.Scope == 0 {
+		p.Scope = AllLetter
+	}
+	if p.Special == "" {
+		p.Special = specialCharset
+	}
+	if p.WordCount == 0 {
+		p.WordCount = passwordDefaultWordCount
+	}
+	if p.Separator == "" {
+		p.Separator = passwordDefaultSeparator
+	}
+	if p.Length < 0 || p.WordCount < 0 || p.MinLower < 0 || p.MinUpper < 0 || p.MinNumber < 0 || p.MinSpecial < 0 {
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+
+	g := &passwordGen{p: p, intn: intn}
+	switch p.Mode {
+	case PasswordModeRandom:
+		return g.random()
+	case PasswordModePronounceable:
+		return g.pronounceable()
+	case PasswordModePassphrase:
+		return g.passphrase()
+	default:
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+}
+
+// passwordGen the state of generating a password
+type passwordGen struct {
+	p    PasswordPolicy
+	intn func(n int) (int, error)
+	err  error
+}
+
+// pick return a random element of chars, the error is kept in g.err
+func (g *passwordGen) pick(chars []rune) rune {
+	if g.err != nil || len(chars) == 0 {
+		return 0
+	}
+	i, err := g.intn(len(chars))
+	g.err = err
+	return chars[i]
+}
+
+// shuffle shuffle s by Fisher-Yates
+func (g *passwordGen) shuffle(s []rune) {
+	for i := len(s) - 1; i > 0 && g.err == nil; i-- {
+		var j int
+		j, g.err = g.intn(i + 1)
+		s[i], s[j] = s[j], s[i]
+	}
+}
+
+// allowed return the characters of charset not in Exclude, without the duplicates
+func (g *passwordGen) allowed(charset string) []rune {
+	res := make([]rune, 0, len(charset))
+	for _, c := range charset {
+		if !strings.ContainsRune(g.p.Exclude, c) && !containsRune(res, c) {
+			res = append(res, c)
+		}
+	}
+	return res
+}
+
+// random return a password of PasswordModeRandom
+func (g *passwordGen) random() (string, error) {
+	p := g.p
+	scope := scopeCharset(p.Scope)
+	classes := []struct {
+		chars    []rune
+		min      int
+		included bool
+	}{
+		{g.allowed(lowerAlphaCharset), p.MinLower, strings.Contains(scope, lowerAlphaCharset)},
+		{g.allowed(upperAlphaCharset), p.MinUpper, strings.Contains(scope, upperAlphaCharset)},
+		{g.allowed(numberCharset), p.MinNumber, strings.Contains(scope, numberCharset)},
+		{g.allowed(p.Special), p.MinSpecial, strings.Contains(scope, specialCharset)},
+	}
+
+	var pool []rune
+	sum := 0
+	for _, c := range classes {
+		if !c.included {
+			if c.min > 0 {
+				return "", errors.New(sErrPasswordPolicyInvalid)
+			}
+			continue
+		}
+		if c.min > 0 && len(c.chars) == 0 || p.NoRepeat && c.min > len(c.chars) {
+			return "", errors.New(sErrPasswordPolicyInvalid)
+		}
+		sum += c.min
+		for _, r := range c.chars {
+			if !containsRune(pool, r) {
+				pool = append(pool, r)
+			}
+		}
+	}
+	if sum > p.Length || len(pool) == 0 || p.NoRepeat && len(pool) < p.Length {
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+
+	res := make([]rune, 0, p.Length)
+	next := func(chars []rune) {
+		if p.NoRepeat {
+			unused := make([]rune, 0, len(chars))
+			for _, r := range chars {
+				if !containsRune(res, r) {
+					unused = append(unused, r)
+				}
+			}
+			chars = unused
+		}
+		res = append(res, g.pick(chars))
+	}
+	for _, c := range classes {
+		for i := 0; i < c.min; i++ {
+			next(c.chars)
+		}
+	}
+	for len(res) < p.Length {
+		next(pool)
+	}
+	g.shuffle(res)
+	if g.err != nil {
+		return "", g.err
+	}
+	return string(res), nil
+}
+
+// pronounceable return a password of PasswordModePronounceable
+func (g *passwordGen) pronounceable() (string, error) {
+	p := g.p
+	letters := p.Length - p.MinNumber - p.MinSpecial
+	consonants, vowels := g.allowed(passwordConsonants), g.allowed(passwordVowels)
+	numbers, specials := g.allowed(numberCharset), g.allowed(p.Special)
+	if letters <= 0 || p.MinLower+p.MinUpper > letters || len(consonants) == 0 || len(vowels) == 0 ||
+		p.MinNumber > 0 && len(numbers) == 0 || p.MinSpecial > 0 && len(specials) == 0 {
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+
+	res := make([]rune, 0, p.Length)
+	start, err := g.intn(2)
+	if err != nil {
+		return "", err
+	}
+	for i := 0; i < letters; i++ {
+		if (i+start)%2 == 0 {
+			res = append(res, g.pick(consonants))
+		} else {
+			res = append(res, g.pick(vowels))
+		}
+	}
+	if !g.capitalize(res, p.MinUpper) {
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+	for i := 0; i < p.MinNumber; i++ {
+		res = append(res, g.pick(numbers))
+	}
+	for i := 0; i < p.MinSpecial; i++ {
+		res = append(res, g.pick(specials))
+	}
+	if g.err != nil {
+		return "", g.err
+	}
+	return string(res), nil
+}
+
+// capitalize capitalize n random letters of s whose uppercase is not excluded, false if there are not enough letters
+func (g *passwordGen) capitalize(s []rune, n int) bool {
+	var candidates []int
+	for i, r := range s {
+		if u := unicode.ToUpper(r); u != r && !strings.ContainsRune(g.p.Exclude, u) {
+			candidates = append(candidates, i)
+		}
+	}
+	if n > len(candidates) {
+		return false
+	}
+	for i := 0; i < n && g.err == nil; i++ {
+		var j int
+		j, g.err = g.intn(len(candidates) - i)
+		idx := candidates[i+j]
+		candidates[i+j] = candidates[i]
+		s[idx] = unicode.ToUpper(s[idx])
+	}
+	return true
+}
+
+// passphrase return a password of PasswordModePassphrase
+func (g *passwordGen) passphrase() (string, error) {
+	p := g.p
+	var words []string
+	seen := make(map[string]bool, len(p.Words))
+	for _, w := range p.Words {
+		if w != "" && !seen[w] && !strings.ContainsAny(w, p.Exclude) {
+			seen[w] = true
+			words = append(words, w)
+		}
+	}
+	numbers, specials := g.allowed(numberCharset), g.allowed(p.Special)
+	if len(words) < 2 || p.MinUpper > p.WordCount || p.MinNumber > 0 && len(numbers) == 0 ||
+		p.MinSpecial > 0 && len(specials) == 0 {
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+
+	res := make([][]rune, p.WordCount)
+	for i := range res {
+		j, err := g.intn(len(words))
+		if err != nil {
+			return "", err
+		}
+		res[i] = []rune(words[j])
+	}
+	// Capitalize the first letters of MinUpper random words
+	firsts := make([]rune, len(res))
+	for i, w := range res {
+		firsts[i] = w[0]
+	}
+	if !g.capitalize(firsts, p.MinUpper) {
+		return "", errors.New(sErrPasswordPolicyInvalid)
+	}
+	for i, w := range res {
+		w[0] = firsts[i]
+	}
+
+	var suffix []rune
+	for i := 0; i < p.MinNumber; i++ {
+		suffix = append(suffix, g.pick(numbers))
+	}
+	for i := 0; i < p.MinSpecial; i++ {
+		suffix = append(suffix, g.pick(specials))
+	}
+	if len(suffix) > 0 && g.err == nil {
+		var i int
+		i, g.err = g.intn(len(res))
+		res[i] = append(res[i], suffix...)
+	}
+	if g.err != nil {
+		return "", g.err
+	}
+
+	parts := make([]string, len(res))
+	for i, w := range res {
+		parts[i] = string(w)
+	}
+	return strings.Join(parts, p.Separator), nil
+}
+
+// containsRune return true if s contains r
+func containsRune(s []rune, r rune) bool {
+	for _, c := range s {
+		if c == r {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,959 @@
+package crypt
+
+import (
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// EstimatePasswordStrength estimates how many guesses an attacker needs to crack a password, in the way of zxcvbn.
+// The password is matched by the patterns an attacker tries first:
+//  dictionary: the common passwords、the English words and the user inputs, also reversed and with the l33t
+//              substitutions like '@' for 'a', the guesses are the rank in the dictionary
+//  spatial:    the keyboard patterns on QWERTY, such as qwerty and zxcvfr, the guesses grow with the length and turns
+//  repeat:     the repeated characters or blocks, such as aaa and abcabc
+//  sequence:   the characters of a constant step, such as abc、9753 and ZYX
+//  date:       the dates and the years, such as 1987、13/05/1987 and 19870513
+//  bruteforce: the characters not matched by the other patterns, 10 guesses per character
+// The guesses of a password is the minimum over all ways to split it into the matches:
+//  l! * (the product of the guesses of the l matches) + 10000^(l-1)
+// The first term means the attacker tries the patterns in any order, the second term penalizes the long sequences.
+// The score is 0 to 4 by the log10 of the guesses: < 3、< 6、< 8、< 10 and >= 10, the passwords with a score less
+// than 3 should be rejected. Only the first 100 characters are analyzed.
+// The guesses of a date grow with the distance of its year from the reference year, which is the current year by
+// default, set PasswordStrengthOptions.ReferenceYear to get the same result in every year.
+// The estimate is a rough heuristic, it is not equivalent to zxcvbn: the built-in dictionaries only hold about 250
+// common passwords and 220 English words(zxcvbn ships tens of thousands), so a common password or word not in them
+// is scored as bruteforce and the strength is overestimated. Pass the words known to be weak for the application,
+// such as the site name and the user's personal information, by the user inputs.
+
+const (
+	pwsMaxLength           = 100
+	pwsBruteforceCardinal  = 10
+	pwsMinGuessesSingle    = 10
+	pwsMinGuessesMulti     = 50
+	pwsMinGuessesGrowing   = 10000
+	pwsMinYearSpace        = 20
+	pwsMinRepeatLength     = 3
+	pwsMinSequenceLength   = 3
+	pwsMinSpatialLength    = 3
+	pwsMaxSequenceDelta    = 5
+	pwsMaxL33tSubstitution = 64
+)
+
+// The patterns of PasswordMatch
+const (
+	PatternDictionary = "dictionary"
+	PatternSpatial    = "spatial"
+	PatternRepeat     = "repeat"
+	PatternSequence   = "sequence"
+	PatternDate       = "date"
+	PatternBruteforce = "bruteforce"
+)
+
+// PasswordMatch a part of the password matched by a pattern
+type PasswordMatch struct {
+	Pattern string  // the pattern, such as PatternDictionary
+	Token   string  // the matched characters
+	Guesses float64 // the guesses of the token
+}
+
+// PasswordStrength the result of EstimatePasswordStrength
+type PasswordStrength struct {
+	Guesses     float64         // the estimated guesses to crack the password
+	Entropy     float64         // log2(Guesses), the bits of the entropy
+	Score       int             // 0(too guessable) to 4(very unguessable)
+	Warning     string          // why the password is weak, empty if the score is greater than 2
+	Suggestions []string        // how to make the password stronger
+	Sequence    []PasswordMatch // the matches of the password which make the minimum guesses
+}
+
+// PasswordStrengthOptions the options of EstimatePasswordStrengthWithOptions
+type PasswordStrengthOptions struct {
+	UserInputs    []string // the personal information such as the user name and the email, matched as a dictionary
+	ReferenceYear int      // the year the distances of the dates are measured from, default the current year
+}
+
+// EstimatePasswordStrength return the strength of password, userInputs are the personal information such as the
+// user name and the email, they are matched as a dictionary
+func EstimatePasswordStrength(password string, userInputs ...string) PasswordStrength {
+	return EstimatePasswordStrengthWithOptions(password, PasswordStrengthOptions{UserInputs: userInputs})
+}
+
+// EstimatePasswordStrengthWithOptions like EstimatePasswordStrength, but with the options
+func EstimatePasswordStrengthWithOptions(password string, opts PasswordStrengthOptions) PasswordStrength {
+	runes := []rune(password)
+	if len(runes) > pwsMaxLength {
+		runes = runes[:pwsMaxLength]
+	}
+	year := opts.ReferenceYear
+	if year == 0 {
+		year = time.Now().Year()
+	}
+	inputs := make(map[string]int, len(opts.UserInputs))
+	for i, in := range opts.UserInputs {
+		in = strings.ToLower(in)
+		if _, ok := inputs[in]; !ok && in != "" {
+			inputs[in] = i + 1
+		}
+	}
+
+	guesses, seq := pwsMostGuessable(runes, inputs, year)
+	res := PasswordStrength{Guesses: guesses, Entropy: math.Log2(guesses), Score: pwsScore(guesses)}
+	for _, m := range seq {
+		res.Sequence = append(res.Sequence, PasswordMatch{Pattern: m.pattern, Token: m.token, Guesses: m.guesses})
+	}
+	res.Warning, res.Suggestions = pwsFeedback(res.Score, seq)
+	return res
+}
+
+// pwsMatch a match of the password, i and j are the indexes of the first and the last rune
+type pwsMatch struct {
+	pattern string
+	i, j    int
+	token   string
+	guesses float64
+
+	// dictionary
+	dictName string
+	rank     int
+	reversed bool
+	l33t     bool
+	subs     map[rune]rune // the l33t character -> the letter
+	// spatial
+	turns   int
+	shifted int
+	// repeat
+	baseGuesses float64
+	repeatCount int
+	// sequence
+	ascending bool
+	// date
+	year      int
+	separator bool
+}
+
+// pwsScore return the score of the guesses
+func pwsScore(guesses float64) int {
+	const delta = 5
+	switch {
+	case guesses < 1e3+delta:
+		return 0
+	case guesses < 1e6+delta:
+		return 1
+	case guesses < 1e8+delta:
+		return 2
+	case guesses < 1e10+delta:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// pwsMostGuessable return the minimum guesses of the password and the matches which make it, by the dynamic
+// programming over the end position and the number of the matches, year is the reference year of the dates
+func pwsMostGuessable(runes []rune, inputs map[string]int, year int) (float64, []*pwsMatch) {
+	n := len(runes)
+	if n == 0 {
+		return 1, nil
+	}
+
+	matchesByJ := make([][]*pwsMatch, n)
+	for _, m := range pwsOmnimatch(runes, inputs, year) {
+		matchesByJ[m.j] = append(matchesByJ[m.j], m)
+	}
+
+	// optimal[k][l] the best sequence of l matches covering runes[:k+1], nil if there is no such sequence
+	type state struct {
+		m  *pwsMatch
+		pi float64 // the product of the guesses
+		g  float64 // the total guesses
+	}
+	optimal := make([][]*state, n)
+	for k := range optimal {
+		optimal[k] = make([]*state, k+2)
+	}
+	update := func(m *pwsMatch, l int) {
+		k := m.j
+		pi := pwsEstimateGuesses(m, n, year)
+		if l > 1 {
+			pi *= optimal[m.i-1][l-1].pi
+		}
+		g := pwsFactorial(l)*pi + math.Pow(pwsMinGuessesGrowing, float64(l-1))
+		// Skip if a sequence of not more matches is not worse
+		for cl := 1; cl <= l; cl++ {
+			if c := optimal[k][cl]; c != nil && c.g <= g {
+				return
+			}
+		}
+		optimal[k][l] = &state{m: m, pi: pi, g: g}
+	}
+	bruteforce := func(i, j int) *pwsMatch {
+		return &pwsMatch{pattern: PatternBruteforce, i: i, j: j, token: string(runes[i : j+1])}
+	}
+
+	for k := 0; k < n; k++ {
+		for _, m := range matchesByJ[k] {
+			if m.i == 0 {
+				update(m, 1)
+				continue
+			}
+			for l, s := range optimal[m.i-1] {
+				if s != nil {
+					update(m, l+1)
+				}
+			}
+		}
+		// The bruteforce matches end at k, a bruteforce match never follows another one
+		update(bruteforce(0, k), 1)
+		for i := 1; i <= k; i++ {
+			for l, s := range optimal[i-1] {
+				if s != nil && s.m.pattern != PatternBruteforce {
+					update(bruteforce(i, k), l+1)
+				}
+			}
+		}
+	}
+
+	bestL := 0
+	for l, s := range optimal[n-1] {
+		if s != nil && (bestL == 0 || s.g < optimal[n-1][bestL].g) {
+			bestL = l
+		}
+	}
+	bestG := optimal[n-1][bestL].g
+	seq := make([]*pwsMatch, bestL)
+	for k, l := n-1, bestL; l > 0; l-- {
+		m := optimal[k][l].m
+		seq[l-1] = m
+		k = m.i - 1
+	}
+	return bestG, seq
+}
+
+// pwsEstimateGuesses return the guesses of m, a token shorter than the password has at least 10 or 50 guesses
+func pwsEstimateGuesses(m *pwsMatch, passwordLen, year int) float64 {
+	if m.guesses > 0 {
+		return m.guesses
+	}
+	minGuesses := 1.0
+	if tokenLen := m.j - m.i + 1; tokenLen < passwordLen {
+		minGuesses = pwsMinGuessesMulti
+		if tokenLen == 1 {
+			minGuesses = pwsMinGuessesSingle
+		}
+	}
+
+	var guesses float64
+	switch m.pattern {
+	case PatternDictionary:
+		guesses = float64(m.rank) * pwsUppercaseVariations(m.token) * pwsL33tVariations(m)
+		if m.reversed {
+			guesses *= 2
+		}
+	case PatternSpatial:
+		guesses = pwsSpatialGuesses(m)
+	case PatternRepeat:
+		guesses = m.baseGuesses * float64(m.repeatCount)
+	case PatternSequence:
+		guesses = pwsSequenceGuesses(m)
+	case PatternDate:
+		guesses = math.Max(math.Abs(float64(m.year-year)), pwsMinYearSpace)
+		if m.token != strconv.Itoa(m.year) {
+			// A full date, the day and the month
+			guesses *= 365
+		}
+		if m.separator {
+			guesses *= 4
+		}
+	default:
+		tokenLen := m.j - m.i + 1
+		guesses = math.Pow(pwsBruteforceCardinal, float64(tokenLen))
+		if math.IsInf(guesses, 1) {
+			guesses = math.MaxFloat64
+		}
+		// A bruteforce match is never cheaper than a match of another pattern
+		if tokenLen == 1 {
+			minGuesses = math.Max(minGuesses, pwsMinGuessesSingle+1)
+		} else {
+			minGuesses = math.Max(minGuesses, pwsMinGuessesMulti+1)
+		}
+	}
+	m.guesses = math.Max(guesses, minGuesses)
+	return m.guesses
+}
+
+// pwsOmnimatch return all matches of the patterns except bruteforce
+func pwsOmnimatch(runes []rune, inputs map[string]int, year int) []*pwsMatch {
+	var matches []*pwsMatch
+	matches = append(matches, pwsDictionaryMatch(runes, inputs)...)
+	matches = append(matches, pwsReverseDictionaryMatch(runes, inputs)...)
+	matches = append(matches, pwsL33tMatch(runes, inputs)...)
+	matches = append(matches, pwsSpatialMatch(runes)...)
+	matches = append(matches, pwsRepeatMatch(runes, inputs, year)...)
+	matches = append(matches, pwsSequenceMatch(runes)...)
+	matches = append(matches, pwsDateMatch(runes)...)
+	return matches
+}
+
+// ---------------------------------------------- dictionary ----------------------------------------------
+
+// pwsDictionaries the ranked dictionaries, the rank of a word is its position starting from 1
+var pwsDictionaries = map[string]map[string]int{
+	"passwords": pwsRankedDictionary(pwsCommonPasswords),
+	"english":   pwsRankedDictionary(pwsEnglishWords),
+}
+
+// pwsRankedDictionary return the ranks of the space separated words
+func pwsRankedDictionary(words string) map[string]int {
+	ranks := make(map[string]int)
+	for _, w := range strings.Fields(words) {
+		if _, ok := ranks[w]; !ok {
+			ranks[w] = len(ranks) + 1
+		}
+	}
+	return ranks
+}
+
+// pwsDictionaryMatch return the substrings of the password in the dictionaries, the match is case-insensitive
+func pwsDictionaryMatch(runes []rune, inputs map[string]int) []*pwsMatch {
+	lower := []rune(strings.ToLower(string(runes)))
+	if len(lower) != len(runes) {
+		lower = runes
+	}
+	dicts := []struct {
+		name  string
+		ranks map[string]int
+	}{{"passwords", pwsDictionaries["passwords"]}, {"english", pwsDictionaries["english"]}, {"user_inputs", inputs}}
+
+	var matches []*pwsMatch
+	for i := range lower {
+		for j := i; j < len(lower); j++ {
+			word := string(lower[i : j+1])
+			for _, d := range dicts {
+				if rank, ok := d.ranks[word]; ok {
+					matches = append(matches, &pwsMatch{pattern: PatternDictionary, i: i, j: j,
+						token: string(runes[i : j+1]), dictName: d.name, rank: rank})
+				}
+			}
+		}
+	}
+	return matches
+}
+
+// pwsReverseDictionaryMatch return the dictionary matches of the reversed password
+func pwsReverseDictionaryMatch(runes []rune, inputs map[string]int) []*pwsMatch {
+	n := len(runes)
+	reversed := make([]rune, n)
+	for i, r := range runes {
+		reversed[n-1-i] = r
+	}
+	var matches []*pwsMatch
+	for _, m := range pwsDictionaryMatch(reversed, inputs) {
+		token := string(runes[n-1-m.j : n-m.i])
+		// The palindromes are matched by pwsDictionaryMatch already
+		if token == m.token {
+			continue
+		}
+		m.i, m.j = n-1-m.j, n-1-m.i
+		m.token = token
+		m.reversed = true
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+// pwsL33tTable the l33t substitutions, the letter -> the l33t characters
+var pwsL33tTable = map[rune]string{
+	'a': "4@", 'b': "8", 'c': "({[<", 'e': "3", 'g': "69", 'i': "1!|", 'l': "1|7", 'o': "0", 's': "$5", 't': "+7",
+	'x': "%", 'z': "2",
+}
+
+// pwsL33tMatch return the dictionary matches of the password with the l33t characters substituted by the letters
+func pwsL33tMatch(runes []rune, inputs map[string]int) []*pwsMatch {
+	// The possible letters of every l33t character in the password
+	candidates := make(map[rune][]rune)
+	for letter, chars := range pwsL33tTable {
+		for _, c := range chars {
+			if containsRune(runes, c) {
+				candidates[c] = append(candidates[c], letter)
+			}
+		}
+	}
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	var matches []*pwsMatch
+	for _, subs := range pwsL33tSubstitutions(candidates) {
+		subbed := make([]rune, len(runes))
+		for i, r := range runes {
+			if letter, ok := subs[r]; ok {
+				subbed[i] = letter
+			} else {
+				subbed[i] = r
+			}
+		}
+		for _, m := range pwsDictionaryMatch(subbed, inputs) {
+			token := runes[m.i : m.j+1]
+			// The single l33t characters like '1' are not matched
+			if len(token) < 2 || string(token) == m.token {
+				continue
+			}
+			m.token = string(token)
+			m.l33t = true
+			m.subs = make(map[rune]rune)
+			for _, r := range token {
+				if letter, ok := subs[r]; ok {
+					m.subs[r] = letter
+				}
+			}
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// pwsL33tSubstitutions return the maps of every l33t character to one of its letters, at most 64 maps
+func pwsL33tSubstitutions(candidates map[rune][]rune) []map[rune]rune {
+	chars := make([]rune, 0, len(candidates))
+	for c := range candidates {
+		chars = append(chars, c)
+	}
+	sort.Slice(chars, func(i, j int) bool { return chars[i] < chars[j] })
+
+	res := []map[rune]rune{{}}
+	for _, c := range chars {
+		letters := candidates[c]
+		sort.Slice(letters, func(i, j int) bool { return letters[i] < letters[j] })
+		var next []map[rune]rune
+		for _, subs := range res {
+			for _, letter := range letters {
+				if len(next) == pwsMaxL33tSubstitution {
+					break
+				}
+				m := make(map[rune]rune, len(subs)+1)
+				for k, v := range subs {
+					m[k] = v
+				}
+				m[c] = letter
+				next = append(next, m)
+			}
+		}
+		res = next
+	}
+	return res
+}
+
+// pwsUppercaseVariations return the number of the ways to capitalize the token with the same uppercase count
+func pwsUppercaseVariations(token string) float64 {
+	if strings.ToLower(token) == token {
+		return 1
+	}
+	runes := []rune(token)
+	// The first letter、the last letter or all letters are uppercase
+	isUpperOnly := func(rs []rune) bool { return strings.ToUpper(string(rs)) == string(rs) }
+	if isUpperOnly(runes[:1]) && strings.ToLower(string(runes[1:])) == string(runes[1:]) ||
+		isUpperOnly(runes[len(runes)-1:]) && strings.ToLower(string(runes[:len(runes)-1])) == string(runes[:len(runes)-1]) ||
+		isUpperOnly(runes) {
+		return 2
+	}
+	var upper, lower int
+	for _, r := range runes {
+		if unicode.IsUpper(r) {
+			upper++
+		} else if unicode.IsLower(r) {
+			lower++
+		}
+	}
+	return pwsVariations(upper, lower)
+}
+
+// pwsL33tVariations return the number of the ways to substitute the letters of the l33t match
+func pwsL33tVariations(m *pwsMatch) float64 {
+	if !m.l33t {
+		return 1
+	}
+	variations := 1.0
+	lower := []rune(strings.ToLower(m.token))
+	for subbed, unsubbed := range m.subs {
+		var s, u int
+		for _, r := range lower {
+			if r == subbed {
+				s++
+			} else if r == unsubbed {
+				u++
+			}
+		}
+		if s == 0 || u == 0 {
+			// All the letters are substituted or not, the attacker tries both
+			variations *= 2
+		} else {
+			variations *= pwsVariations(s, u)
+		}
+	}
+	return variations
+}
+
+// pwsVariations return sum(C(a+b, i)), 1 <= i <= min(a, b)
+func pwsVariations(a, b int) float64 {
+	var res float64
+	for i := 1; i <= a && i <= b; i++ {
+		res += pwsBinomial(a+b, i)
+	}
+	return res
+}
+
+// ---------------------------------------------- spatial ----------------------------------------------
+
+// pwsKeyboardRows the rows of the QWERTY keyboard, unshifted and shifted, every row is shifted right by half a key
+var pwsKeyboardRows = [][2]string{
+	{"`1234567890-=", "~!@#$%^&*()_+"},
+	{"qwertyuiop[]\\", "QWERTYUIOP{}|"},
+	{"asdfghjkl;'", "ASDFGHJKL:\""},
+	{"zxcvbnm,./", "ZXCVBNM<>?"},
+}
+
+// pwsKey the position of a key, x is in half keys
+type pwsKey struct {
+	row, x  int
+	shifted bool
+}
+
+var (
+	// pwsKeys the positions of the characters of the keyboard
+	pwsKeys = func() map[rune]pwsKey {
+		keys := make(map[rune]pwsKey)
+		offsets := []int{0, 3, 4, 5}
+		for row, chars := range pwsKeyboardRows {
+			for shifted, s := range chars {
+				for i, r := range s {
+					keys[r] = pwsKey{row: row, x: offsets[row] + 2*i, shifted: shifted == 1}
+				}
+			}
+		}
+		return keys
+	}()
+	// pwsKeyboardStarts、pwsKeyboardDegree the number of the characters and the average number of the neighbors
+	pwsKeyboardStarts, pwsKeyboardDegree = func() (float64, float64) {
+		var neighbors int
+		for _, a := range pwsKeys {
+			for _, b := range pwsKeys {
+				if !b.shifted && pwsKeyDirection(a, b) >= 0 {
+					neighbors++
+				}
+			}
+		}
+		return float64(len(pwsKeys)), float64(neighbors) / float64(len(pwsKeys))
+	}()
+)
+
+// pwsKeyDirection return the direction from the key a to the neighbor b: 0 left、1 right、2 upper left、3 upper
+// right、4 lower left、5 lower right, -1 if they are not neighbors
+func pwsKeyDirection(a, b pwsKey) int {
+	dx := b.x - a.x
+	switch {
+	case b.row == a.row && dx == -2:
+		return 0
+	case b.row == a.row && dx == 2:
+		return 1
+	case b.row == a.row-1 && dx == -1:
+		return 2
+	case b.row == a.row-1 && dx == 1:
+		return 3
+	case b.row == a.row+1 && dx == -1:
+		return 4
+	case b.row == a.row+1 && dx == 1:
+		return 5
+	}
+	return -1
+}
+
+// pwsSpatialMatch return the runs of the adjacent keys, at least 3 characters
+func pwsSpatialMatch(runes []rune) []*pwsMatch {
+	var matches []*pwsMatch
+	for i := 0; i < len(runes)-1; {
+		j, turns, lastDir := i, 0, -1
+		first, ok := pwsKeys[runes[i]]
+		if !ok {
+			i++
+			continue
+		}
+		shifted := 0
+		if first.shifted {
+			shifted++
+		}
+		for j+1 < len(runes) {
+			cur, ok1 := pwsKeys[runes[j]]
+			next, ok2 := pwsKeys[runes[j+1]]
+			if !ok1 || !ok2 {
+				break
+			}
+			dir := pwsKeyDirection(cur, next)
+			if dir < 0 {
+				break
+			}
+			if dir != lastDir {
+				turns++
+				lastDir = dir
+			}
+			if next.shifted {
+				shifted++
+			}
+			j++
+		}
+		if j-i+1 >= pwsMinSpatialLength {
+			matches = append(matches, &pwsMatch{pattern: PatternSpatial, i: i, j: j, token: string(runes[i : j+1]),
+				turns: turns, shifted: shifted})
+		}
+		if j > i {
+			i = j
+		} else {
+			i++
+		}
+	}
+	return matches
+}
+
+// pwsSpatialGuesses return the guesses of the spatial match: sum(C(i-1, j-1) * starts * degree^j) of the length i
+// and the turns j, multiplied by the variations of the shifted characters
+func pwsSpatialGuesses(m *pwsMatch) float64 {
+	length := m.j - m.i + 1
+	var guesses float64
+	for i := 2; i <= length; i++ {
+		for j := 1; j <= m.turns && j <= i-1; j++ {
+			guesses += pwsBinomial(i-1, j-1) * pwsKeyboardStarts * math.Pow(pwsKeyboardDegree, float64(j))
+		}
+	}
+	if m.shifted > 0 {
+		unshifted := length - m.shifted
+		if unshifted == 0 {
+			guesses *= 2
+		} else {
+			guesses *= pwsVariations(m.shifted, unshifted)
+		}
+	}
+	return guesses
+}
+
+// ---------------------------------------------- repeat ----------------------------------------------
+
+// pwsRepeatMatch return the repeated blocks, such as aaa and abcabc, the longest repeat at every position is chosen
+func pwsRepeatMatch(runes []rune, inputs map[string]int, year int) []*pwsMatch {
+	var matches []*pwsMatch
+	n := len(runes)
+	for i := 0; i < n; {
+		bestLen, bestBase, bestCount := 0, 0, 0
+		for base := 1; base <= (n-i)/2; base++ {
+			count := 1
+			for i+(count+1)*base <= n &&
+				string(runes[i+count*base:i+(count+1)*base]) == string(runes[i:i+base]) {
+				count++
+			}
+			if count >= 2 && count*base >= pwsMinRepeatLength && count*base > bestLen {
+				bestLen, bestBase, bestCount = count*base, base, count
+			}
+		}
+		if bestLen == 0 {
+			i++
+			continue
+		}
+		base := runes[i : i+bestBase]
+		baseGuesses, _ := pwsMostGuessable(base, inputs, year)
+		matches = append(matches, &pwsMatch{pattern: PatternRepeat, i: i, j: i + bestLen - 1,
+			token: string(runes[i : i+bestLen]), baseGuesses: baseGuesses, repeatCount: bestCount})
+		i += bestLen
+	}
+	return matches
+}
+
+// ---------------------------------------------- sequence ----------------------------------------------
+
+// pwsSequenceMatch return the runs of the same class(lowercase、uppercase or digits) with a constant step
+func pwsSequenceMatch(runes []rune) []*pwsMatch {
+	var matches []*pwsMatch
+	class := func(r rune) int {
+		switch {
+		case r >= 'a' && r <= 'z':
+			return 1
+		case r >= 'A' && r <= 'Z':
+			return 2
+		case r >= '0' && r <= '9':
+			return 3
+		}
+		return 0
+	}
+	for i := 0; i+1 < len(runes); {
+		delta := int(runes[i+1]) - int(runes[i])
+		c := class(runes[i])
+		j := i + 1
+		if c == 0 || delta == 0 || delta > pwsMaxSequenceDelta || delta < -pwsMaxSequenceDelta || class(runes[j]) != c {
+			i++
+			continue
+		}
+		for j+1 < len(runes) && int(runes[j+1])-int(runes[j]) == delta && class(runes[j+1]) == c {
+			j++
+		}
+		if j-i+1 >= pwsMinSequenceLength {
+			matches = append(matches, &pwsMatch{pattern: PatternSequence, i: i, j: j, token: string(runes[i : j+1]),
+				ascending: delta > 0})
+		}
+		i = j
+	}
+	return matches
+}
+
+// pwsSequenceGuesses return the guesses of the sequence match by the first character and the length
+func pwsSequenceGuesses(m *pwsMatch) float64 {
+	first := []rune(m.token)[0]
+	var base float64
+	switch {
+	case strings.ContainsRune("aAzZ019", first):
+		// The obvious start
+		base = 4
+	case first >= '0' && first <= '9':
+		base = 10
+	default:
+		base = 26
+	}
+	if !m.ascending {
+		base *= 2
+	}
+	return base * float64(m.j-m.i+1)
+}
+
+// ---------------------------------------------- date ----------------------------------------------
+
+// pwsDateMatch return the years(1900-2049) and the dates with or without the separators
+func pwsDateMatch(runes []rune) []*pwsMatch {
+	var matches []*pwsMatch
+	n := len(runes)
+	for i := 0; i < n; i++ {
+		for j := i + 3; j < n && j < i+10; j++ {
+			token := string(runes[i : j+1])
+			if year, ok := pwsParseDate(token); ok {
+				matches = append(matches, &pwsMatch{pattern: PatternDate, i: i, j: j, token: token, year: year,
+					separator: strings.IndexFunc(token, func(r rune) bool { return r < '0' || r > '9' }) >= 0})
+			}
+		}
+	}
+	return matches
+}
+
+// pwsParseDate return the year if token is a year or a date in the orders y-m-d、d-m-y or m-d-y
+func pwsParseDate(token string) (int, bool) {
+	var parts []string
+	if sepIdx := strings.IndexAny(token, " -/\\_."); sepIdx >= 0 {
+		sep := token[sepIdx : sepIdx+1]
+		parts = strings.Split(token, sep)
+		if len(parts) != 3 {
+			return 0, false
+		}
+	} else {
+		for _, r := range token {
+			if r < '0' || r > '9' {
+				return 0, false
+			}
+		}
+		if len(token) == 4 {
+			if year, _ := strconv.Atoi(token); year >= 1900 && year <= 2049 {
+				return year, true
+			}
+		}
+		// Split the digits into three parts of 1-4 digits
+		for a := 1; a <= 4 && a < len(token)-1; a++ {
+			for b := 1; b <= 2 && a+b < len(token); b++ {
+				if year, ok := pwsDateParts([]string{token[:a], token[a : a+b], token[a+b:]}); ok {
+					return year, true
+				}
+			}
+		}
+		return 0, false
+	}
+	return pwsDateParts(parts)
+}
+
+// pwsDateParts return the year if the three parts are a valid date
+func pwsDateParts(parts []string) (int, bool) {
+	nums := make([]int, 3)
+	for i, p := range parts {
+		if len(p) == 0 || len(p) > 4 {
+			return 0, false
+		}
+		v, err := strconv.Atoi(p)
+		if err != nil {
+			return 0, false
+		}
+		nums[i] = v
+	}
+	// year-month-day, day-month-year and month-day-year
+	orders := [][3]int{{0, 1, 2}, {2, 1, 0}, {2, 0, 1}}
+	for _, o := range orders {
+		yearStr, year, month, day := parts[o[0]], nums[o[0]], nums[o[1]], nums[o[2]]
+		if len(parts[o[1]]) > 2 || len(parts[o[2]]) > 2 || month < 1 || month > 12 || day < 1 || day > 31 {
+			continue
+		}
+		switch len(yearStr) {
+		case 2:
+			if year > 50 {
+				year += 1900
+			} else {
+				year += 2000
+			}
+		case 4:
+			if year < 1900 || year > 2049 {
+				continue
+			}
+		default:
+			continue
+		}
+		return year, true
+	}
+	return 0, false
+}
+
+// ---------------------------------------------- feedback ----------------------------------------------
+
+// pwsFeedback return the warning and the suggestions of the password by the longest match
+func pwsFeedback(score int, seq []*pwsMatch) (string, []string) {
+	if len(seq) == 0 {
+		return "", []string{"Use a few words, avoid common phrases", "No need for symbols, digits, or uppercase letters"}
+	}
+	if score > 2 {
+		return "", nil
+	}
+
+	longest := seq[0]
+	for _, m := range seq[1:] {
+		if len(m.token) > len(longest.token) {
+			longest = m
+		}
+	}
+	suggestions := []string{"Add another word or two. Uncommon words are better."}
+	var warning string
+	switch longest.pattern {
+	case PatternDictionary:
+		warning = pwsDictionaryWarning(longest, len(seq) == 1)
+		word := longest.token
+		if strings.ToUpper(word) == word && strings.ToLower(word) != word {
+			suggestions = append(suggestions, "All-uppercase is almost as easy to guess as all-lowercase")
+		} else if r := []rune(word); unicode.IsUpper(r[0]) {
+			suggestions = append(suggestions, "Capitalization doesn't help very much")
+		}
+		if longest.reversed && len(word) >= 4 {
+			suggestions = append(suggestions, "Reversed words aren't much harder to guess")
+		}
+		if longest.l33t {
+			suggestions = append(suggestions, "Predictable substitutions like '@' instead of 'a' don't help very much")
+		}
+	case PatternSpatial:
+		warning = "Short keyboard patterns are easy to guess"
+		if longest.turns == 1 {
+			warning = "Straight rows of keys are easy to guess"
+		}
+		suggestions = append(suggestions, "Use a longer keyboard pattern with more turns")
+	case PatternRepeat:
+		warning = `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`
+		if len([]rune(longest.token))/longest.repeatCount == 1 {
+			warning = `Repeats like "aaa" are easy to guess`
+		}
+		suggestions = append(suggestions, "Avoid repeated words and characters")
+	case PatternSequence:
+		warning = "Sequences like abc or 6543 are easy to guess"
+		suggestions = append(suggestions, "Avoid sequences")
+	case PatternDate:
+		warning = "Dates are often easy to guess"
+		if longest.token == strconv.Itoa(longest.year) {
+			warning = "Recent years are easy to guess"
+		}
+		suggestions = append(suggestions, "Avoid dates and years that are associated with you")
+	}
+	return warning, suggestions
+}
+
+// pwsDictionaryWarning return the warning of the dictionary match, whole is true if it is the whole password
+func pwsDictionaryWarning(m *pwsMatch, whole bool) string {
+	switch m.dictName {
+	case "passwords":
+		if !whole {
+			return "This is similar to a commonly used password"
+		}
+		switch {
+		case m.l33t || m.reversed:
+			return "This is similar to a commonly used password"
+		case m.rank <= 10:
+			return "This is a top-10 common password"
+		case m.rank <= 100:
+			return "This is a top-100 common password"
+		default:
+			return "This is a very common password"
+		}
+	case "english":
+		if whole {
+			return "A word by itself is easy to guess"
+		}
+	case "user_inputs":
+		return "Personal information like names and emails is easy to guess"
+	}
+	return ""
+}
+
+// pwsBinomial return C(n, k)
+func pwsBinomial(n, k int) float64 {
+	if k > n || k < 0 {
+		return 0
+	}
+	res := 1.0
+	for i := 1; i <= k; i++ {
+		res = res * float64(n-k+i) / float64(i)
+	}
+	return res
+}
+
+// pwsFactorial return n!
+func pwsFactorial(n int) float64 {
+	res := 1.0
+	for i := 2; i <= n; i++ {
+		res *= float64(i)
+	}
+	return res
+}
+
+// pwsCommonPasswords the most common passwords ordered by the frequency, it is a small sample of the leaked lists
+const pwsCommonPasswords = `
+123456 password 12345678 qwerty 123456789 12345 1234 111111 1234567 dragon 123123 baseball abc123 football monkey
+letmein 696969 shadow master 666666 qwertyuiop 123321 mustang 1234567890 michael 654321 superman 1qaz2wsx 7777777
+121212 000000 qazwsx 123qwe killer trustno1 jordan jennifer zxcvbnm asdfgh hunter buster soccer harley batman
+andrew tigger sunshine iloveyou 2000 charlie robert thomas hockey ranger daniel starwars klaster 112233 george
+computer michelle jessica pepper 1111 zxcvbn 555555 11111111 131313 freedom 777777 pass maggie 159753 aaaaaa ginger
+princess joshua cheese amanda summer love ashley 6969 nicole chelsea matthew access yankees 987654321 dallas austin
+thunder taylor matrix william corvette hello martin heather secret merlin diamond 1234qwer hammer silver 222222
+88888888 anthony justin test bailey q1w2e3r4t5 patrick internet scooter orange 11111 golfer cookie richard samantha
+bigdog guitar jackson whatever mickey chicken sparky snoopy maverick phoenix camaro peanut morgan welcome falcon
+cowboy ferrari samsung andrea smokey steelers joseph mercedes dakota arsenal eagles melissa boomer booboo spider
+nascar monster tigers yellow xxxxxx 123123123 gateway marina diablo bulldog qwer1234 compaq purple banana junior
+hannah 123654 porsche lakers iceman money cowboys 987654 london tennis 999999 ncc1701 coffee scooby 0000 miller
+boston q1w2e3r4 brandon yamaha chester mother forever johnny edward 333333 oliver redsox player nikita knight fender
+barney midnight please brandy chicago badboy slayer rangers charles angel flower rabbit wizard jasper enter rachel
+chris steven winner adidas victoria natasha 1q2w3e4r jasmine winter prince marine fishing cocacola casper james
+232323 raiders 888888 marlboro gandalf asdfasdf crystal 87654321 12344321 golden 8675309 asdf admin login abc
+letmein1 passw0rd password1 qwerty123 welcome1 monkey1 admin123 root toor changeme default guest p@ssw0rd
+`
+
+// pwsEnglishWords the most common English words ordered by the frequency, it is a small sample
+const pwsEnglishWords = `
+the and that have for not with you this but his from they say her she will one all would there their what out about
+who get which when make can like time just him know take people into year your good some could them see other than
+then now look only come its over think also back after use two how our work first well way even new want because any
+these give day most man woman life child world school state family student group country problem hand part place case
+week company system program question government number night point home water room mother area money story fact month
+lot right study book eye job word business issue side kind head house service friend father power hour game line end
+member law car city community name president team minute idea kid body information parent face others level office
+door health person art war history party result change morning reason research girl guy moment air teacher force
+education dog cat love baby sun moon star summer winter spring autumn flower tree apple orange banana horse battery
+staple correct monkey dragon tiger lion secret freedom music football soccer purple yellow green blue black white red
+happy sunshine princess angel heart dream magic king queen prince silver golden diamond ocean river mountain forest
+`
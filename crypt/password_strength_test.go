@@ -0,0 +1,178 @@
+package crypt
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+// pwsTestYear the reference year of the tests, so the guesses of the dates do not change with the current year
+const pwsTestYear = 2020
+
+func TestEstimatePasswordStrength(t *testing.T) {
+	tests := []struct {
+		name        string
+		password    string
+		wantScore   int
+		wantPattern string
+		wantWarning string
+	}{
+		{name: "Empty", password: "", wantScore: 0},
+		{name: "Top10", password: "password", wantScore: 0, wantPattern: PatternDictionary,
+			wantWarning: "This is a top-10 common password"},
+		{name: "Capitalized", password: "Password1", wantScore: 0, wantPattern: PatternDictionary,
+			wantWarning: "This is a very common password"},
+		{name: "L33t", password: "p@ssw0rd", wantScore: 0, wantPattern: PatternDictionary,
+			wantWarning: "This is similar to a commonly used password"},
+		{name: "Reversed", password: "drowssap", wantScore: 0, wantPattern: PatternDictionary,
+			wantWarning: "This is similar to a commonly used password"},
+		{name: "Spatial", password: "zxcvfr", wantScore: 1, wantPattern: PatternSpatial,
+			wantWarning: "Short keyboard patterns are easy to guess"},
+		{name: "StraightRow", password: "sdfghj", wantScore: 1, wantPattern: PatternSpatial,
+			wantWarning: "Straight rows of keys are easy to guess"},
+		{name: "RepeatChar", password: "aaaaaa", wantScore: 0, wantPattern: PatternRepeat,
+			wantWarning: `Repeats like "aaa" are easy to guess`},
+		{name: "RepeatBlock", password: "abcabcabc", wantScore: 0, wantPattern: PatternRepeat,
+			wantWarning: `Repeats like "abcabcabc" are only slightly harder to guess than "abc"`},
+		{name: "Sequence", password: "abcdef", wantScore: 0, wantPattern: PatternSequence,
+			wantWarning: "Sequences like abc or 6543 are easy to guess"},
+		{name: "DescendingSequence", password: "97531", wantScore: 0, wantPattern: PatternSequence,
+			wantWarning: "Sequences like abc or 6543 are easy to guess"},
+		{name: "Year", password: "1987", wantScore: 0, wantPattern: PatternDate,
+			wantWarning: "Recent years are easy to guess"},
+		{name: "Date", password: "13/05/1987", wantScore: 1, wantPattern: PatternDate,
+			wantWarning: "Dates are often easy to guess"},
+		{name: "DateNoSeparator", password: "19870513", wantScore: 1, wantPattern: PatternDate,
+			wantWarning: "Dates are often easy to guess"},
+		{name: "Passphrase", password: "correcthorsebatterystaple", wantScore: 4, wantPattern: PatternDictionary},
+		{name: "Random", password: "rWibMFACxAUGZmxhVncy", wantScore: 4, wantPattern: PatternBruteforce},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EstimatePasswordStrengthWithOptions(tt.password, PasswordStrengthOptions{ReferenceYear: pwsTestYear})
+			if got.Score != tt.wantScore {
+				t.Errorf("Score = %v, want %v, guesses = %v", got.Score, tt.wantScore, got.Guesses)
+			}
+			if math.Abs(got.Entropy-math.Log2(got.Guesses)) > 1e-9 {
+				t.Errorf("Entropy = %v, want log2(%v)", got.Entropy, got.Guesses)
+			}
+			if got.Warning != tt.wantWarning {
+				t.Errorf("Warning = %q, want %q", got.Warning, tt.wantWarning)
+			}
+			if tt.password == "" {
+				if len(got.Sequence) != 0 || got.Guesses != 1 || len(got.Suggestions) == 0 {
+					t.Errorf("EstimatePasswordStrength(\"\") = %+v", got)
+				}
+				return
+			}
+			if len(got.Sequence) == 0 || got.Sequence[0].Pattern != tt.wantPattern {
+				t.Fatalf("Sequence = %+v, want the pattern %v", got.Sequence, tt.wantPattern)
+			}
+			// The tokens of the sequence make up the password
+			var tokens []string
+			for _, m := range got.Sequence {
+				tokens = append(tokens, m.Token)
+			}
+			if strings.Join(tokens, "") != tt.password {
+				t.Errorf("the tokens of the sequence %v do not make up %v", tokens, tt.password)
+			}
+			if got.Score <= 2 && len(got.Suggestions) == 0 || got.Score > 2 && got.Suggestions != nil {
+				t.Errorf("Suggestions = %v of the score %v", got.Suggestions, got.Score)
+			}
+		})
+	}
+}
+
+func TestEstimatePasswordStrengthReferenceYear(t *testing.T) {
+	tests := []struct {
+		password string
+		year     int
+		want     float64
+	}{
+		{password: "1987", year: 2020, want: 33},
+		{password: "1987", year: 1990, want: 20},
+		{password: "1987", year: 1900, want: 87},
+		{password: "19870513", year: 2020, want: 33 * 365},
+		{password: "13/05/1987", year: 2020, want: 33 * 365 * 4},
+	}
+	for _, tt := range tests {
+		got := EstimatePasswordStrengthWithOptions(tt.password, PasswordStrengthOptions{ReferenceYear: tt.year})
+		if len(got.Sequence) != 1 || got.Sequence[0].Pattern != PatternDate || got.Sequence[0].Guesses != tt.want {
+			t.Errorf("the reference year %v of %v: Sequence = %+v, want the guesses %v",
+				tt.year, tt.password, got.Sequence, tt.want)
+		}
+	}
+
+	// The default reference year is the current year
+	want := EstimatePasswordStrengthWithOptions("1987", PasswordStrengthOptions{ReferenceYear: time.Now().Year()})
+	if got := EstimatePasswordStrength("1987"); got.Guesses != want.Guesses {
+		t.Errorf("Guesses = %v, want %v", got.Guesses, want.Guesses)
+	}
+}
+
+func TestEstimatePasswordStrengthUserInputs(t *testing.T) {
+	without := EstimatePasswordStrength("zhangwei")
+	with := EstimatePasswordStrength("zhangwei", "ZhangWei", "zhangwei@example.com")
+	if with.Guesses >= without.Guesses || with.Score != 0 {
+		t.Errorf("Guesses = %v with user inputs, %v without", with.Guesses, without.Guesses)
+	}
+	if with.Warning != "Personal information like names and emails is easy to guess" {
+		t.Errorf("Warning = %q", with.Warning)
+	}
+}
+
+func TestEstimatePasswordStrengthSuggestions(t *testing.T) {
+	tests := []struct {
+		password string
+		want     string
+	}{
+		{password: "Monkey", want: "Capitalization doesn't help very much"},
+		{password: "MONKEY", want: "All-uppercase is almost as easy to guess as all-lowercase"},
+		{password: "yeknom", want: "Reversed words aren't much harder to guess"},
+		{password: "m0nk3y", want: "Predictable substitutions like '@' instead of 'a' don't help very much"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.password, func(t *testing.T) {
+			got := EstimatePasswordStrength(tt.password)
+			found := false
+			for _, s := range got.Suggestions {
+				found = found || s == tt.want
+			}
+			if !found {
+				t.Errorf("Suggestions = %v, want %q", got.Suggestions, tt.want)
+			}
+		})
+	}
+}
+
+func TestEstimatePasswordStrengthLong(t *testing.T) {
+	start := time.Now()
+	long := strings.Repeat("Xq7#pL2$vR9!", 50)
+	got := EstimatePasswordStrength(long)
+	if got.Score != 4 {
+		t.Errorf("Score = %v, want 4", got.Score)
+	}
+	if d := time.Since(start); d > 5*time.Second {
+		t.Errorf("EstimatePasswordStrength() takes %v", d)
+	}
+}
+
+func TestPasswordStrengthHelpers(t *testing.T) {
+	if got := pwsBinomial(5, 2); got != 10 {
+		t.Errorf("pwsBinomial(5, 2) = %v, want 10", got)
+	}
+	if got := pwsFactorial(5); got != 120 {
+		t.Errorf("pwsFactorial(5) = %v, want 120", got)
+	}
+	variations := map[string]float64{"password": 1, "Password": 2, "passworD": 2, "PASSWORD": 2, "PaSsword": 36}
+	for token, want := range variations {
+		if got := pwsUppercaseVariations(token); got != want {
+			t.Errorf("pwsUppercaseVariations(%v) = %v, want %v", token, got, want)
+		}
+	}
+	// Straight rows have 1 turn, every key has about 4.6 neighbors on average
+	if pwsKeyboardStarts != 94 || pwsKeyboardDegree < 4 || pwsKeyboardDegree > 5 {
+		t.Errorf("pwsKeyboardStarts = %v, pwsKeyboardDegree = %v", pwsKeyboardStarts, pwsKeyboardDegree)
+	}
+}
@@ -0,0 +1,179 @@
+package crypt
+
+import (
+	"strings"
+	"testing"
+	"unicode"
+)
+
+// countClasses return the numbers of the lowercase letters、uppercase letters、numbers and other characters of s
+func countClasses(s string) (lower, upper, number, other int) {
+	for _, r := range s {
+		switch {
+		case unicode.IsLower(r):
+			lower++
+		case unicode.IsUpper(r):
+			upper++
+		case unicode.IsDigit(r):
+			number++
+		default:
+			other++
+		}
+	}
+	return
+}
+
+func TestGeneratePassword(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  PasswordPolicy
+		wantLen int
+		wantErr bool
+	}{
+		{name: "Default", policy: PasswordPolicy{}, wantLen: 16},
+		{name: "MinPerClass", policy: PasswordPolicy{Length: 8, MinLower: 2, MinUpper: 2, MinNumber: 2, MinSpecial: 2}, wantLen: 8},
+		{name: "OnlyNumber", policy: PasswordPolicy{Length: 6, Scope: OnlyNumber, MinNumber: 6}, wantLen: 6},
+		{name: "CustomSpecial", policy: PasswordPolicy{Length: 12, Special: "#@", MinSpecial: 3}, wantLen: 12},
+		{name: "Exclude", policy: PasswordPolicy{Length: 40, Exclude: "0O1lI"}, wantLen: 40},
+		{name: "NoRepeat", policy: PasswordPolicy{Length: 10, Scope: OnlyNumber, NoRepeat: true}, wantLen: 10},
+		{name: "NoRepeatTooLong", policy: PasswordPolicy{Length: 11, Scope: OnlyNumber, NoRepeat: true}, wantErr: true},
+		{name: "MinTooLarge", policy: PasswordPolicy{Length: 4, MinLower: 3, MinUpper: 2}, wantErr: true},
+		{name: "ClassOutOfScope", policy: PasswordPolicy{Scope: AlphaLetter, MinNumber: 1}, wantErr: true},
+		{name: "ClassAllExcluded", policy: PasswordPolicy{MinNumber: 1, Exclude: numberCharset}, wantErr: true},
+		{name: "NegativeMin", policy: PasswordPolicy{MinLower: -1}, wantErr: true},
+		{name: "InvalidMode", policy: PasswordPolicy{Mode: 10}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for i := 0; i < 50; i++ {
+				got, err := GeneratePassword(tt.policy)
+				if (err != nil) != tt.wantErr {
+					t.Fatalf("GeneratePassword() error = %v, wantErr %v", err, tt.wantErr)
+				}
+				if tt.wantErr {
+					return
+				}
+				checkPassword(t, got, tt.policy, tt.wantLen)
+			}
+		})
+	}
+}
+
+// checkPassword check that s satisfies p in PasswordModeRandom
+func checkPassword(t *testing.T, s string, p PasswordPolicy, wantLen int) {
+	if len([]rune(s)) != wantLen {
+		t.Fatalf("GeneratePassword() = %v, want %d characters", s, wantLen)
+	}
+	lower, upper, number, other := countClasses(s)
+	if lower < p.MinLower || upper < p.MinUpper || number < p.MinNumber || other < p.MinSpecial {
+		t.Fatalf("GeneratePassword() = %v does not satisfy the minimums", s)
+	}
+	special := p.Special
+	if special == "" {
+		special = specialCharset
+	}
+	scope := p.Scope
+	if scope == 0 {
+		scope = AllLetter
+	}
+	allowed := strings.Replace(scopeCharset(scope), specialCharset, special, 1)
+	for _, r := range s {
+		if !strings.ContainsRune(allowed, r) || strings.ContainsRune(p.Exclude, r) {
+			t.Fatalf("GeneratePassword() = %v contains %q", s, r)
+		}
+		if p.NoRepeat && strings.Count(s, string(r)) > 1 {
+			t.Fatalf("GeneratePassword() = %v repeats %q", s, r)
+		}
+	}
+}
+
+func TestGeneratePasswordDeterministic(t *testing.T) {
+	intn := func(seed uint64) func(int) (int, error) {
+		r, _ := NewRand(seed, RtXoshiro256)
+		return func(n int) (int, error) { return r.IntN(n), nil }
+	}
+	policy := PasswordPolicy{Length: 20, MinNumber: 3, MinSpecial: 3}
+	p1, _ := generatePassword(policy, intn(7))
+	p2, _ := generatePassword(policy, intn(7))
+	p3, _ := generatePassword(policy, intn(8))
+	if p1 != p2 || p1 == p3 {
+		t.Errorf("generatePassword() = %v, %v, %v", p1, p2, p3)
+	}
+}
+
+func TestGeneratePronounceablePassword(t *testing.T) {
+	policy := PasswordPolicy{Mode: PasswordModePronounceable, Length: 12, MinUpper: 2, MinNumber: 2, MinSpecial: 1,
+		Special: "!", Exclude: "lI"}
+	for i := 0; i < 50; i++ {
+		got, err := GeneratePassword(policy)
+		if err != nil {
+			t.Fatalf("GeneratePassword() error = %v", err)
+		}
+		lower, upper, number, other := countClasses(got)
+		if len(got) != 12 || lower+upper != 9 || upper < 2 || number != 2 || other != 1 || !strings.HasSuffix(got, "!") ||
+			strings.ContainsAny(got, "lI") {
+			t.Fatalf("GeneratePassword() = %v", got)
+		}
+		// Consonants and vowels alternate
+		letters := strings.ToLower(got[:9])
+		for j := 1; j < len(letters); j++ {
+			if strings.IndexByte(passwordVowels, letters[j]) >= 0 == (strings.IndexByte(passwordVowels, letters[j-1]) >= 0) {
+				t.Fatalf("GeneratePassword() = %v is not pronounceable", got)
+			}
+		}
+	}
+
+	invalid := []PasswordPolicy{
+		{Mode: PasswordModePronounceable, Length: 3, MinNumber: 3},
+		{Mode: PasswordModePronounceable, Exclude: passwordVowels},
+		{Mode: PasswordModePronounceable, Length: 4, MinUpper: 3, MinLower: 2},
+	}
+	for _, p := range invalid {
+		if _, err := GeneratePassword(p); err == nil {
+			t.Errorf("GeneratePassword(%+v) should return an error", p)
+		}
+	}
+}
+
+func TestGeneratePassphrase(t *testing.T) {
+	words := []string{"correct", "horse", "battery", "staple", "orange", "lemon", "zebra"}
+	policy := PasswordPolicy{Mode: PasswordModePassphrase, Words: words, WordCount: 4, Separator: ".", MinUpper: 2,
+		MinNumber: 2, Exclude: "z"}
+	for i := 0; i < 50; i++ {
+		got, err := GeneratePassword(policy)
+		if err != nil {
+			t.Fatalf("GeneratePassword() error = %v", err)
+		}
+		parts := strings.Split(got, ".")
+		if len(parts) != 4 || strings.Contains(got, "zebra") {
+			t.Fatalf("GeneratePassword() = %v", got)
+		}
+		_, upper, number, _ := countClasses(got)
+		if upper != 2 || number != 2 {
+			t.Fatalf("GeneratePassword() = %v, %d uppercase letters and %d numbers", got, upper, number)
+		}
+		for _, p := range parts {
+			w := strings.ToLower(strings.TrimRight(p, numberCharset))
+			if !strings.Contains(strings.Join(words, " "), w) {
+				t.Fatalf("GeneratePassword() = %v contains unknown word %v", got, p)
+			}
+		}
+	}
+
+	got, err := GeneratePassword(PasswordPolicy{Mode: PasswordModePassphrase, Words: words})
+	if err != nil || len(strings.Split(got, "-")) != 6 {
+		t.Errorf("GeneratePassword() = %v, error = %v", got, err)
+	}
+
+	invalid := []PasswordPolicy{
+		{Mode: PasswordModePassphrase},
+		{Mode: PasswordModePassphrase, Words: []string{"one", "one"}},
+		{Mode: PasswordModePassphrase, Words: words, WordCount: 2, MinUpper: 3},
+		{Mode: PasswordModePassphrase, Words: []string{"1one", "2two"}, MinUpper: 1},
+	}
+	for _, p := range invalid {
+		if _, err := GeneratePassword(p); err == nil {
+			t.Errorf("GeneratePassword(%+v) should return an error", p)
+		}
+	}
+}
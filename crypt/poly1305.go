@@ -0,0 +1,135 @@
+package crypt
+
+import (
+	"crypto/subtle"
+	"encoding/binary"
+	"math/bits"
+)
+
+// Poly1305 is a one-time authenticator designed by D. J. Bernstein, see RFC 8439 section 2.5.
+// The 32-byte one-time key is split into r(16 bytes, clamped) and s(16 bytes), the message is processed in 16-byte
+// blocks as little-endian numbers with an extra 1-bit appended, the accumulator h = (h + block) * r mod 2^130-5, and
+// the tag is (h + s) mod 2^128. A key must never be used to authenticate more than one message.
+// Here h is kept in three 64-bit limbs(h2 only has a few bits) and r in two 64-bit limbs.
+
+const poly1305TagSize = 16
+
+// poly1305 a streaming Poly1305 authenticator
+type poly1305 struct {
+	h   [3]uint64
+	r   [2]uint64
+	s   [2]uint64
+	buf [16]byte
+	n   int
+}
+
+// newPoly1305 return a Poly1305 authenticator with the one-time key
+func newPoly1305(key *[32]byte) *poly1305 {
+	p := &poly1305{}
+	p.r[0] = binary.LittleEndian.Uint64(key[0:8]) & 0x0FFFFFFC0FFFFFFF
+	p.r[1] = binary.LittleEndian.Uint64(key[8:16]) & 0x0FFFFFFC0FFFFFFC
+	p.s[0] = binary.LittleEndian.Uint64(key[16:24])
+	p.s[1] = binary.LittleEndian.Uint64(key[24:32])
+	return p
+}
+
+// Write add the message to the authenticator, it never returns an error
+func (p *poly1305) Write(msg []byte) (int, error) {
+	n := len(msg)
+	if p.n > 0 {
+		c := copy(p.buf[p.n:], msg)
+		p.n += c
+		msg = msg[c:]
+		if p.n < 16 {
+			return n, nil
+		}
+		p.blocks(p.buf[:], 1)
+		p.n = 0
+	}
+	if full := len(msg) &^ 15; full > 0 {
+		p.blocks(msg[:full], 1)
+		msg = msg[full:]
+	}
+	p.n = copy(p.buf[:], msg)
+	return n, nil
+}
+
+// Sum write the tag to out
+func (p *poly1305) Sum(out *[poly1305TagSize]byte) {
+	if p.n > 0 {
+		// The last partial block is padded with 0x01 and zeros, and without the extra 1-bit
+		var last [16]byte
+		copy(last[:], p.buf[:p.n])
+		last[p.n] = 1
+		p.blocks(last[:], 0)
+		p.n = 0
+	}
+
+	// Compute h - p = h - (2^130 - 5), select it if there is no borrow(h >= p)
+	h0, h1, h2 := p.h[0], p.h[1], p.h[2]
+	t0, b := bits.Sub64(h0, 0xFFFFFFFFFFFFFFFB, 0)
+	t1, b := bits.Sub64(h1, 0xFFFFFFFFFFFFFFFF, b)
+	_, b = bits.Sub64(h2, 3, b)
+	mask := b - 1 // all ones if no borrow
+	h0 = h0&^mask | t0&mask
+	h1 = h1&^mask | t1&mask
+
+	h0, c := bits.Add64(h0, p.s[0], 0)
+	h1, _ = bits.Add64(h1, p.s[1], c)
+	binary.LittleEndian.PutUint64(out[0:8], h0)
+	binary.LittleEndian.PutUint64(out[8:16], h1)
+}
+
+// blocks process the full 16-byte blocks of msg, hibit is the extra bit appended to every block
+func (p *poly1305) blocks(msg []byte, hibit uint64) {
+	h0, h1, h2 := p.h[0], p.h[1], p.h[2]
+	r0, r1 := p.r[0], p.r[1]
+
+	for len(msg) >= 16 {
+		// h += m
+		var c uint64
+		h0, c = bits.Add64(h0, binary.LittleEndian.Uint64(msg[0:8]), 0)
+		h1, c = bits.Add64(h1, binary.LittleEndian.Uint64(msg[8:16]), c)
+		h2 += c + hibit
+
+		// h * r = t0 + t1*2^64 + t2*2^128 + t3*2^192, h2 is small so h2*r0 and h2*r1 fit in 64 bits
+		m0hi, m0lo := bits.Mul64(h0, r0)
+		m1hi, m1lo := bits.Mul64(h1, r0)
+		m2hi, m2lo := bits.Mul64(h0, r1)
+		m3hi, m3lo := bits.Mul64(h1, r1)
+
+		t0 := m0lo
+		t1, c1 := bits.Add64(m0hi, m1lo, 0)
+		t2, c2 := bits.Add64(m1hi, m3lo, c1)
+		t3, _ := bits.Add64(m3hi, 0, c2)
+		t1, c1 = bits.Add64(t1, m2lo, 0)
+		t2, c2 = bits.Add64(t2, m2hi, c1)
+		t3, _ = bits.Add64(t3, 0, c2)
+		t2, c2 = bits.Add64(t2, h2*r0, 0)
+		t3, _ = bits.Add64(t3, h2*r1, c2)
+
+		// 2^130 = 5 mod p, so h = (h mod 2^130) + 5 * (h >> 130) = (h mod 2^130) + 4*(h >> 130) + (h >> 130),
+		// and (t2 &^ 3, t3) is exactly 4*(h >> 130)
+		h0, h1, h2 = t0, t1, t2&3
+		cc0, cc1 := t2&^3, t3
+		h0, c = bits.Add64(h0, cc0, 0)
+		h1, c = bits.Add64(h1, cc1, c)
+		h2 += c
+		cc0, cc1 = cc0>>2|cc1<<62, cc1>>2
+		h0, c = bits.Add64(h0, cc0, 0)
+		h1, c = bits.Add64(h1, cc1, c)
+		h2 += c
+
+		msg = msg[16:]
+	}
+	p.h[0], p.h[1], p.h[2] = h0, h1, h2
+}
+
+// poly1305Verify return whether tag is the valid Poly1305 tag of msg under the one-time key, in constant time
+func poly1305Verify(tag []byte, msg []byte, key *[32]byte) bool {
+	var sum [poly1305TagSize]byte
+	p := newPoly1305(key)
+	p.Write(msg)
+	p.Sum(&sum)
+	return subtle.ConstantTimeCompare(tag, sum[:]) == 1
+}
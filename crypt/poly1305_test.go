@@ -0,0 +1,71 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"testing"
+)
+
+func TestPoly1305(t *testing.T) {
+	tests := []struct {
+		name string
+		key  string
+		msg  []byte
+		want string
+	}{
+		{
+			name: "RFC8439_2.5.2",
+			key:  "85d6be7857556d337f4452fe42d506a80103808afb0db2fd4abff6af4149f51b",
+			msg:  []byte("Cryptographic Forum Research Group"),
+			want: "a8061dc1305136c6c22b8baf0c0127a9",
+		},
+		{
+			name: "ZeroKey",
+			key:  "0000000000000000000000000000000000000000000000000000000000000000",
+			msg:  make([]byte, 64),
+			want: "00000000000000000000000000000000",
+		},
+		{
+			// RFC 8439 A.3 #5: h reaches p exactly and must be reduced
+			name: "RFC8439_A.3.5",
+			key:  "0200000000000000000000000000000000000000000000000000000000000000",
+			msg:  []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff},
+			want: "03000000000000000000000000000000",
+		},
+		{
+			name: "RFC8439_A.3.6",
+			key:  "02000000000000000000000000000000ffffffffffffffffffffffffffffffff",
+			msg:  []byte{0x02, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0},
+			want: "03000000000000000000000000000000",
+		},
+		{
+			name: "RFC8439_A.3.11",
+			key:  "0100000000000000000000000000000000000000000000000000000000000000",
+			msg: []byte{0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff,
+				0xfb, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe, 0xfe,
+				0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01, 0x01},
+			want: "00000000000000000000000000000000",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key [32]byte
+			k, _ := hex.DecodeString(tt.key)
+			copy(key[:], k)
+			want, _ := hex.DecodeString(tt.want)
+
+			// write the message byte by byte to cover the buffering
+			var sum [poly1305TagSize]byte
+			p := newPoly1305(&key)
+			for i := range tt.msg {
+				p.Write(tt.msg[i : i+1])
+			}
+			p.Sum(&sum)
+			if got := hex.EncodeToString(sum[:]); got != tt.want {
+				t.Errorf("poly1305 Sum() = %v, want %v", got, tt.want)
+			}
+			if !poly1305Verify(want, tt.msg, &key) {
+				t.Errorf("poly1305Verify() = false, want true")
+			}
+		})
+	}
+}
@@ -0,0 +1,220 @@
+package crypt
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"errors"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Rand is a seeded pseudo-random generator, the same seed and RandType always generate the same sequence, so it is
+// useful for the reproducible tests、simulations and sampling. It is NOT cryptographically secure.
+// There are two algorithms:
+//  RtXoshiro256: xoshiro256** of Blackman and Vigna, the seed is expanded to the state by splitmix64
+//  RtPCG:        PCG-DXSM 128/64 of O'Neill, the same as PCG in math/rand/v2, the seed is expanded by splitmix64
+// A Rand is not safe for concurrent use, the high-throughput concurrent callers can use a RandPool.
+
+// Rand a seeded pseudo-random generator, it implements Source
+type Rand struct {
+	rt  RandType
+	src Source
+}
+
+// NewRand return a Rand of the algorithm rt seeded by seed
+func NewRand(seed uint64, rt RandType) (*Rand, error) {
+	r := &Rand{rt: rt}
+	switch rt {
+	case RtXoshiro256:
+		r.src = &xoshiro256{}
+	case RtPCG:
+		r.src = &pcg{}
+	default:
+		return nil, errors.New(sErrRandTypeInvalid)
+	}
+	r.Seed(seed)
+	return r, nil
+}
+
+// Seed reset the state of r by seed, r generates the same sequence as NewRand(seed, rt)
+func (r *Rand) Seed(seed uint64) {
+	switch src := r.src.(type) {
+	case *xoshiro256:
+		src.seed(seed)
+	case *pcg:
+		src.seed(seed)
+	}
+}
+
+// Type return the algorithm of r
+func (r *Rand) Type() RandType {
+	return r.rt
+}
+
+// Uint64 return a random 64-bit value, it implements Source
+func (r *Rand) Uint64() uint64 {
+	return r.src.Uint64()
+}
+
+// Int return a non_negative random integer,range:[0, ∞)
+func (r *Rand) Int() int {
+	return randInt(r.src)
+}
+
+// IntN return a non_negative random integer,range:[0, n-1]
+func (r *Rand) IntN(n int) int {
+	return randIntN(r.src, n)
+}
+
+// IntRange return a non_negative random integer,range:[min, max-1]
+// min and max are not necessarily in ascending order
+func (r *Rand) IntRange(min, max int) int {
+	return randIntRange(r.src, min, max)
+}
+
+// Float64 return a random float,range:[0, 1)
+func (r *Rand) Float64() float64 {
+	return randFloat64(r.src)
+}
+
+// Floats return a non_negative random float,range:[min, max)
+// min and max are not necessarily in ascending order
+func (r *Rand) Floats(min, max float64) float64 {
+	return randFloats(r.src, min, max)
+}
+
+// Bytes return a random byte slice of the specified length, each byte has a value range of [0x00,0xff]
+func (r *Rand) Bytes(length uint32) []byte {
+	return randBytes(r.src, length)
+}
+
+// String return a random string of specified length, the characters are chosen from st
+func (r *Rand) String(length int, st ScopeType) string {
+	return randString(r.src, length, st)
+}
+
+// RandPool a pool of Rand for the high-throughput concurrent callers. Every goroutine gets a Rand from the
+// per-processor cache of sync.Pool, so there is no lock contention in the common case. Each Rand in the pool has a
+// different seed derived from crypto/rand, so the sequences are not reproducible.
+// RandPool is safe for concurrent use, and it implements Source, such as: SetRandSource(NewRandPool(RtXoshiro256))
+type RandPool struct {
+	pool    sync.Pool
+	seed    uint64
+	counter uint64
+}
+
+// NewRandPool return a pool of Rand of the algorithm rt
+func NewRandPool(rt RandType) (*RandPool, error) {
+	if rt != RtXoshiro256 && rt != RtPCG {
+		return nil, errors.New(sErrRandTypeInvalid)
+	}
+
+	p := &RandPool{}
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		p.seed = uint64(time.Now().UnixNano())
+	} else {
+		p.seed = binary.LittleEndian.Uint64(b[:])
+	}
+	p.pool.New = func() interface{} {
+		// Every Rand has a distinct seed, the seeds are further mixed by splitmix64 in Seed
+		r, _ := NewRand(mix64(p.seed+atomic.AddUint64(&p.counter, 1)*splitmixGamma), rt)
+		return r
+	}
+	return p, nil
+}
+
+// Get return a Rand of the pool, it must not be used by other goroutines and should be put back by Put
+func (p *RandPool) Get() *Rand {
+	return p.pool.Get().(*Rand)
+}
+
+// Put put r back to the pool, r must not be used after Put
+func (p *RandPool) Put(r *Rand) {
+	if r != nil {
+		p.pool.Put(r)
+	}
+}
+
+// Uint64 return a random 64-bit value of a Rand of the pool, it implements Source
+func (p *RandPool) Uint64() uint64 {
+	r := p.Get()
+	v := r.Uint64()
+	p.Put(r)
+	return v
+}
+
+// splitmixGamma the increment of splitmix64, 2^64 / golden ratio
+const splitmixGamma = 0x9e3779b97f4a7c15
+
+// splitmix64 return the next value of splitmix64 and advance the state, it is used to expand the seeds
+func splitmix64(state *uint64) uint64 {
+	*state += splitmixGamma
+	return mix64(*state)
+}
+
+// xoshiro256 the state of xoshiro256**
+type xoshiro256 struct {
+	s [4]uint64
+}
+
+// seed expand seed to the state by splitmix64, the state is never all zero
+func (x *xoshiro256) seed(seed uint64) {
+	for i := range x.s {
+		x.s[i] = splitmix64(&seed)
+	}
+	if x.s[0]|x.s[1]|x.s[2]|x.s[3] == 0 {
+		x.s[0] = splitmixGamma
+	}
+}
+
+// Uint64 implement Source
+func (x *xoshiro256) Uint64() uint64 {
+	s := &x.s
+	result := bits.RotateLeft64(s[1]*5, 7) * 9
+	t := s[1] << 17
+	s[2] ^= s[0]
+	s[3] ^= s[1]
+	s[1] ^= s[2]
+	s[0] ^= s[3]
+	s[2] ^= t
+	s[3] = bits.RotateLeft64(s[3], 45)
+	return result
+}
+
+// pcg the 128 bits state of PCG-DXSM
+type pcg struct {
+	hi, lo uint64
+}
+
+// seed expand seed to the state by splitmix64
+func (p *pcg) seed(seed uint64) {
+	p.hi = splitmix64(&seed)
+	p.lo = splitmix64(&seed)
+}
+
+// Uint64 implement Source
+func (p *pcg) Uint64() uint64 {
+	// The 128 bits LCG: state = state * mul + inc
+	const (
+		mulHi = 0x2360ed051fc65da4
+		mulLo = 0x4385df649fccf645
+		incHi = 0x5851f42d4c957f2d
+		incLo = 0x14057b7ef767814f
+	)
+	hi, lo := bits.Mul64(p.lo, mulLo)
+	hi += p.hi*mulLo + p.lo*mulHi
+	lo, c := bits.Add64(lo, incLo, 0)
+	hi, _ = bits.Add64(hi, incHi, c)
+	p.hi, p.lo = hi, lo
+
+	// DXSM: double xorshift multiply
+	const cheapMul = 0xda942042e4dd58b5
+	hi ^= hi >> 32
+	hi *= cheapMul
+	hi ^= hi >> 48
+	hi *= lo | 1
+	return hi
+}
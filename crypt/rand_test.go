@@ -0,0 +1,143 @@
+package crypt
+
+import (
+	"sync"
+	"testing"
+)
+
+func TestXoshiro256(t *testing.T) {
+	// The reference outputs of xoshiro256** with the state {1, 2, 3, 4}
+	x := &xoshiro256{s: [4]uint64{1, 2, 3, 4}}
+	want := []uint64{11520, 0, 1509978240, 1215971899390074240, 1216172134540287360}
+	for i, w := range want {
+		if got := x.Uint64(); got != w {
+			t.Errorf("xoshiro256 output %d = %v, want %v", i, got, w)
+		}
+	}
+}
+
+func TestPCG(t *testing.T) {
+	// The outputs of PCG in math/rand/v2 with NewPCG(1, 2)
+	p := &pcg{hi: 1, lo: 2}
+	want := []uint64{0xc4f5a58656eef510, 0x9dcec3ad077dec6c, 0xc8d04605312f8088, 0xcbedc0dcb63ac19a,
+		0x3bf98798cae97950}
+	for i, w := range want {
+		if got := p.Uint64(); got != w {
+			t.Errorf("pcg output %d = %#x, want %#x", i, got, w)
+		}
+	}
+}
+
+func TestNewRand(t *testing.T) {
+	tests := []struct {
+		name    string
+		rt      RandType
+		want    []uint64
+		wantErr bool
+	}{
+		{name: "Xoshiro256", rt: RtXoshiro256, want: []uint64{0x15780b2e0c2ec716, 0x6104d9866d113a7e, 0xae17533239e499a1}},
+		{name: "PCG", rt: RtPCG, want: []uint64{0x61c88529c9612c1b, 0x2608d8a3075aa168, 0x5418c9208d9f1374}},
+		{name: "InvalidType", rt: 0, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewRand(42, tt.rt)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewRand() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if r.Type() != tt.rt {
+				t.Errorf("Type() = %v, want %v", r.Type(), tt.rt)
+			}
+			for i, w := range tt.want {
+				if got := r.Uint64(); got != w {
+					t.Errorf("Uint64() %d = %#x, want %#x", i, got, w)
+				}
+			}
+			// Seed restarts the sequence
+			r.Seed(42)
+			if got := r.Uint64(); got != tt.want[0] {
+				t.Errorf("Uint64() after Seed = %#x, want %#x", got, tt.want[0])
+			}
+		})
+	}
+}
+
+func TestRandMethods(t *testing.T) {
+	for _, rt := range []RandType{RtXoshiro256, RtPCG} {
+		r1, _ := NewRand(2024, rt)
+		r2, _ := NewRand(2024, rt)
+		if r1.Int() != r2.Int() || r1.IntN(100) != r2.IntN(100) || r1.IntRange(10, 20) != r2.IntRange(10, 20) ||
+			r1.Float64() != r2.Float64() || r1.Floats(1, 2) != r2.Floats(1, 2) ||
+			string(r1.Bytes(13)) != string(r2.Bytes(13)) || r1.String(20, AllLetter) != r2.String(20, AllLetter) {
+			t.Errorf("the Rands of type %v with the same seed generate different sequences", rt)
+		}
+
+		for i := 0; i < 1000; i++ {
+			if v := r1.IntN(7); v < 0 || v >= 7 {
+				t.Fatalf("IntN(7) = %v", v)
+			}
+			if v := r1.IntRange(20, 10); v < 10 || v >= 20 {
+				t.Fatalf("IntRange(20, 10) = %v", v)
+			}
+			if v := r1.Floats(1, 2); v < 1 || v >= 2 {
+				t.Fatalf("Floats(1, 2) = %v", v)
+			}
+		}
+		if s := r1.String(32, OnlyNumber); len(s) != 32 || !compareOnlyNumber(s) {
+			t.Errorf("String(32, OnlyNumber) = %v", s)
+		}
+		if b := r1.Bytes(17); len(b) != 17 {
+			t.Errorf("len(Bytes(17)) = %v", len(b))
+		}
+	}
+}
+
+func TestRandPool(t *testing.T) {
+	if _, err := NewRandPool(RandType(100)); err == nil {
+		t.Errorf("NewRandPool() with invalid type should return an error")
+	}
+
+	p, err := NewRandPool(RtPCG)
+	if err != nil {
+		t.Fatalf("NewRandPool() error = %v", err)
+	}
+	r := p.Get()
+	if r.Type() != RtPCG {
+		t.Errorf("Type() = %v, want %v", r.Type(), RtPCG)
+	}
+	p.Put(r)
+	p.Put(nil)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 1000; j++ {
+				if v := randIntN(p, 10); v < 0 || v >= 10 {
+					t.Errorf("randIntN() = %v", v)
+					return
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	defer SetRandSource(nil)
+	SetRandSource(p)
+	if got := RandString(16, AlphaAndNumber); len(got) != 16 || !compareAlphaAndNumber(got) {
+		t.Errorf("RandString() with RandPool = %v", got)
+	}
+}
+
+func BenchmarkRandPool(b *testing.B) {
+	p, _ := NewRandPool(RtXoshiro256)
+	b.RunParallel(func(pb *testing.PB) {
+		for pb.Next() {
+			p.Uint64()
+		}
+	})
+}
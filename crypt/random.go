@@ -1,6 +1,9 @@
 package crypt
 
 import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"io"
 	"math/rand"
 	"time"
 )
@@ -9,22 +12,122 @@ func init() {
 	rand.Seed(time.Now().UnixNano())
 }
 
+// The Rand* functions use the source set by SetRandSource, the default is FastSource which is NOT cryptographically
+// secure. Use the SecureRand* functions, or SetRandSource(SecureSource), to generate tokens、keys and passwords.
+
 // RandInt return a non_negative random integer,range:[0, ∞)
 func RandInt() int {
-	return rand.Int()
+	return randInt(randSource())
 }
 
 // RandIntN return a non_negative random integer,range:[0, n-1]
 func RandIntN(n int) int {
-	if n <= 0 {
-		return 0
-	}
-	return rand.Intn(n)
+	return randIntN(randSource(), n)
 }
 
 // RandIntRange return a non_negative random integer,range:[min, max-1]
 // min and max are not necessarily in ascending order
 func RandIntRange(min, max int) int {
+	return randIntRange(randSource(), min, max)
+}
+
+// RandFloats return a non_negative random float,range:[min, max)
+// min and max are not necessarily in ascending order
+func RandFloats(min, max float64) float64 {
+	return randFloats(randSource(), min, max)
+}
+
+// RandBytes return a random byte slice of the specified length, each byte has a value range of [0x00,0xff]
+func RandBytes(length uint32) []byte {
+	return randBytes(randSource(), length)
+}
+
+// RandString return a random string of specified length
+// The content of the string consists of uppercase and lowercase letters, numbers, and special characters
+func RandString(length int, st ScopeType) string {
+	return randString(randSource(), length, st)
+}
+
+// SecureRandIntN return a non_negative random integer,range:[0, n-1], it is read from crypto/rand and unbiased
+func SecureRandIntN(n int) (int, error) {
+	if n <= 0 {
+		return 0, nil
+	}
+	var b [8]byte
+	threshold := uint64Threshold(uint64(n))
+	for {
+		if _, err := io.ReadFull(crand.Reader, b[:]); err != nil {
+			return 0, err
+		}
+		if v := binary.LittleEndian.Uint64(b[:]); v >= threshold {
+			return int(v % uint64(n)), nil
+		}
+	}
+}
+
+// SecureRandBytes return a random byte slice of the specified length read from crypto/rand, it can be used as keys
+func SecureRandBytes(length uint32) ([]byte, error) {
+	b := make([]byte, length)
+	if _, err := io.ReadFull(crand.Reader, b); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// SecureRandString return a random string of specified length read from crypto/rand, every character is chosen
+// uniformly from the characters of st, it can be used as tokens and passwords
+func SecureRandString(length int, st ScopeType) (string, error) {
+	if length <= 0 {
+		return "", nil
+	}
+	charset := scopeCharset(st)
+	// The bytes not less than threshold are rejected, so that every character has the same probability
+	threshold := 256 - 256%len(charset)
+
+	res := make([]byte, 0, length)
+	buf := make([]byte, length+length/4+8)
+	for len(res) < length {
+		if _, err := io.ReadFull(crand.Reader, buf); err != nil {
+			return "", err
+		}
+		for _, b := range buf {
+			if int(b) < threshold {
+				res = append(res, charset[int(b)%len(charset)])
+				if len(res) == length {
+					break
+				}
+			}
+		}
+	}
+	return string(res), nil
+}
+
+// randInt return a non_negative random integer of src
+func randInt(src Source) int {
+	return int(uint(src.Uint64()) << 1 >> 1)
+}
+
+// randIntN return a random integer in [0, n-1] of src by the unbiased rejection sampling, 0 if n <= 0
+func randIntN(src Source, n int) int {
+	if n <= 0 {
+		return 0
+	}
+	threshold := uint64Threshold(uint64(n))
+	for {
+		if v := src.Uint64(); v >= threshold {
+			return int(v % uint64(n))
+		}
+	}
+}
+
+// uint64Threshold return 2^64 mod n, the number of the values in [threshold, 2^64) is a multiple of n, so v mod n
+// is uniform if the values less than threshold are rejected
+func uint64Threshold(n uint64) uint64 {
+	return -n % n
+}
+
+// randIntRange return a random integer in [min, max-1] of src
+func randIntRange(src Source, min, max int) int {
 	tmpMin, tmpMax := min, max
 	if max < min {
 		tmpMin, tmpMax = max, min
@@ -36,12 +139,16 @@ func RandIntRange(min, max int) int {
 		tmpMin = 0
 	}
 
-	return RandIntN(tmpMax-tmpMin) + tmpMin
+	return randIntN(src, tmpMax-tmpMin) + tmpMin
 }
 
-// RandFloats return a non_negative random float,range:[min, max)
-// min and max are not necessarily in ascending order
-func RandFloats(min, max float64) float64 {
+// randFloat64 return a random float in [0, 1) of src, with 53 random bits
+func randFloat64(src Source) float64 {
+	return float64(src.Uint64()>>11) / (1 << 53)
+}
+
+// randFloats return a random float in [min, max) of src
+func randFloats(src Source, min, max float64) float64 {
 	tmpMin, tmpMax := min, max
 	if max < min {
 		tmpMin, tmpMax = max, min
@@ -51,63 +158,68 @@ func RandFloats(min, max float64) float64 {
 	} else if tmpMin < 0 {
 		tmpMin = 0
 	}
-	return tmpMin + rand.Float64()*(tmpMax-tmpMin)
+	return tmpMin + randFloat64(src)*(tmpMax-tmpMin)
 }
 
-// RandBytes return a random byte slice of the specified length, each byte has a value range of [0x00,0xff]
-func RandBytes(length uint32) []byte {
+// randBytes return length random bytes of src
+func randBytes(src Source, length uint32) []byte {
 	b := make([]byte, length)
-	for i := range b {
-		b[i] = byte(RandInt() % 256)
+	for i := 0; i < len(b); i += 8 {
+		var v [8]byte
+		binary.LittleEndian.PutUint64(v[:], src.Uint64())
+		copy(b[i:], v[:])
 	}
 	return b
 }
 
-// RandString return a random string of specified length
-// The content of the string consists of uppercase and lowercase letters, numbers, and special characters
-func RandString(length int, st ScopeType) string {
+// randString return a random string of src
+func randString(src Source, length int, st ScopeType) string {
 	if length <= 0 {
 		return ""
 	}
 
-	var charset string
+	charset := scopeCharset(st)
+	charsetLen := len(charset)
+
+	b := make([]byte, length)
+	for i := range b {
+		b[i] = charset[randIntN(src, charsetLen)]
+	}
+	return string(b)
+}
+
+// scopeCharset return the characters of st, the default is uppercase and lowercase letters
+func scopeCharset(st ScopeType) string {
 	switch st {
 	case OnlyLowerCase:
-		charset = lowerAlphaCharset
+		return lowerAlphaCharset
 	case OnlyUpperCase:
-		charset = upperAlphaCharset
+		return upperAlphaCharset
 	case OnlyNumber:
-		charset = numberCharset
+		return numberCharset
 	case AlphaLetter:
-		charset = lowerAlphaCharset + upperAlphaCharset
+		return lowerAlphaCharset + upperAlphaCharset
 	case LowerCaseAndNumber:
-		charset = lowerAlphaCharset + numberCharset
+		return lowerAlphaCharset + numberCharset
 	case LowerCaseAndSpecial:
-		charset = lowerAlphaCharset + specialCharset
+		return lowerAlphaCharset + specialCharset
 	case UpperCaseAndNumber:
-		charset = upperAlphaCharset + numberCharset
+		return upperAlphaCharset + numberCharset
 	case UpperCaseAndSpecial:
-		charset = upperAlphaCharset + specialCharset
+		return upperAlphaCharset + specialCharset
 	case NumberAndSpecial:
-		charset = numberCharset + specialCharset
+		return numberCharset + specialCharset
 	case AlphaAndNumber:
-		charset = lowerAlphaCharset + upperAlphaCharset + numberCharset
+		return lowerAlphaCharset + upperAlphaCharset + numberCharset
 	case AlphaAndSpecial:
-		charset = lowerAlphaCharset + upperAlphaCharset + specialCharset
+		return lowerAlphaCharset + upperAlphaCharset + specialCharset
 	case NoUpperCase:
-		charset = lowerAlphaCharset + numberCharset + specialCharset
+		return lowerAlphaCharset + numberCharset + specialCharset
 	case NoLowerCase:
-		charset = upperAlphaCharset + numberCharset + specialCharset
+		return upperAlphaCharset + numberCharset + specialCharset
 	case AllLetter:
-		charset = lowerAlphaCharset + upperAlphaCharset + numberCharset + specialCharset
+		return lowerAlphaCharset + upperAlphaCharset + numberCharset + specialCharset
 	default:
-		charset = lowerAlphaCharset + upperAlphaCharset
-	}
-	charsetLen := len(charset)
-
-	b := make([]byte, length)
-	for i := range b {
-		b[i] = charset[RandIntN(charsetLen)]
+		return lowerAlphaCharset + upperAlphaCharset
 	}
-	return string(b)
 }
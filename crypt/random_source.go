@@ -0,0 +1,70 @@
+package crypt
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"io"
+	"math/rand"
+	"sync/atomic"
+)
+
+// Source a source of uniformly distributed random 64-bit values, the Rand* functions are built on it.
+// FastSource is the global generator of math/rand, fast but predictable, it is the default.
+// SecureSource is crypto/rand, unpredictable but slower, use it for tokens、keys and passwords
+type Source interface {
+	Uint64() uint64
+}
+
+var (
+	// FastSource the global generator of math/rand, it is seeded by the time in init and safe for concurrent use
+	FastSource Source = fastSource{}
+	// SecureSource the cryptographically secure source backed by crypto/rand, it is safe for concurrent use.
+	// Uint64 panics if crypto/rand fails, which means the random generator of the OS is broken
+	SecureSource Source = secureSource{}
+)
+
+// sourceHolder keep the concrete type stored in atomic.Value the same
+type sourceHolder struct {
+	Source
+}
+
+// defaultSource the source of the Rand* functions
+var defaultSource atomic.Value
+
+func init() {
+	defaultSource.Store(sourceHolder{FastSource})
+}
+
+// SetRandSource set the source of the package level Rand* functions, such as: SetRandSource(SecureSource).
+// src must be safe for concurrent use, nil restores FastSource
+func SetRandSource(src Source) {
+	if src == nil {
+		src = FastSource
+	}
+	defaultSource.Store(sourceHolder{src})
+}
+
+// randSource return the source of the Rand* functions
+func randSource() Source {
+	return defaultSource.Load().(sourceHolder).Source
+}
+
+// fastSource the global generator of math/rand
+type fastSource struct{}
+
+// Uint64 implement Source
+func (fastSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// secureSource crypto/rand
+type secureSource struct{}
+
+// Uint64 implement Source, it panics if crypto/rand fails
+func (secureSource) Uint64() uint64 {
+	var b [8]byte
+	if _, err := io.ReadFull(crand.Reader, b[:]); err != nil {
+		panic("crypt: crypto/rand failed: " + err.Error())
+	}
+	return binary.LittleEndian.Uint64(b[:])
+}
@@ -0,0 +1,64 @@
+package crypt
+
+import (
+	"testing"
+)
+
+// counterSource return n, n+1, n+2, ... , it is not safe for concurrent use
+type counterSource struct {
+	n uint64
+}
+
+func (s *counterSource) Uint64() uint64 {
+	v := s.n
+	s.n++
+	return v
+}
+
+func TestSetRandSource(t *testing.T) {
+	defer SetRandSource(nil)
+
+	// 1000 is greater than 2^64 mod n for the small n, so no value is rejected
+	SetRandSource(&counterSource{n: 1000})
+	if got := RandIntN(10); got != 0 {
+		t.Errorf("RandIntN() = %v, want 0", got)
+	}
+	if got := RandIntN(10); got != 1 {
+		t.Errorf("RandIntN() = %v, want 1", got)
+	}
+	if got := RandBytes(10); string(got) != "\xea\x03\x00\x00\x00\x00\x00\x00\xeb\x03" {
+		t.Errorf("RandBytes() = %v", got)
+	}
+	if got := RandString(3, OnlyNumber); got != "456" {
+		t.Errorf("RandString() = %v, want 456", got)
+	}
+	if got := RandFloats(0, 1); got != 0 {
+		t.Errorf("RandFloats() = %v, want 0", got)
+	}
+
+	SetRandSource(SecureSource)
+	if got := RandString(16, AlphaAndNumber); len(got) != 16 || !compareAlphaAndNumber(got) {
+		t.Errorf("RandString() with SecureSource = %v", got)
+	}
+
+	SetRandSource(nil)
+	if _, ok := randSource().(fastSource); !ok {
+		t.Errorf("SetRandSource(nil) source = %T, want fastSource", randSource())
+	}
+}
+
+func TestRandIntNUnbiased(t *testing.T) {
+	// The values less than 2^64 mod n are rejected
+	n := uint64(3)
+	threshold := uint64Threshold(n)
+	if threshold != 1 {
+		t.Fatalf("uint64Threshold(3) = %v, want 1", threshold)
+	}
+	src := &counterSource{}
+	if got := randIntN(src, int(n)); got != 1 || src.n != 2 {
+		t.Errorf("randIntN() = %v after %d values, want 1 after 2 values", got, src.n)
+	}
+	if got := uint64Threshold(1 << 10); got != 0 {
+		t.Errorf("uint64Threshold(1024) = %v, want 0", got)
+	}
+}
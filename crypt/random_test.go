@@ -324,3 +324,58 @@ func TestRandIntRange(t *testing.T) {
 		})
 	}
 }
+
+func TestSecureRandString(t *testing.T) {
+	tests := []struct {
+		name   string
+		length int
+		scope  ScopeType
+	}{
+		{"LengthInvalid", 0, OnlyLowerCase},
+		{"OnlyNumber", 64, OnlyNumber},
+		{"AlphaAndNumber", 64, AlphaAndNumber},
+		{"AllLetter", 1000, AllLetter},
+		{"Default", 16, 1000},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SecureRandString(tt.length, tt.scope)
+			if err != nil || len(got) != tt.length || !compareRandString(got, tt.scope) {
+				t.Errorf("SecureRandString() = %v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestSecureRandIntN(t *testing.T) {
+	if got, err := SecureRandIntN(0); err != nil || got != 0 {
+		t.Errorf("SecureRandIntN(0) = %v, %v, want 0", got, err)
+	}
+	counts := make([]int, 6)
+	for i := 0; i < 6000; i++ {
+		got, err := SecureRandIntN(len(counts))
+		if err != nil || got < 0 || got >= len(counts) {
+			t.Fatalf("SecureRandIntN() = %v, %v", got, err)
+		}
+		counts[got]++
+	}
+	for i, c := range counts {
+		if c < 800 || c > 1200 {
+			t.Errorf("SecureRandIntN() count of %d = %d, want about 1000", i, c)
+		}
+	}
+}
+
+func TestSecureRandBytes(t *testing.T) {
+	for _, n := range []uint32{0, 1, 16, 33} {
+		got, err := SecureRandBytes(n)
+		if err != nil || len(got) != int(n) {
+			t.Errorf("SecureRandBytes(%d) = %v, %v", n, got, err)
+		}
+	}
+	a, _ := SecureRandBytes(32)
+	b, _ := SecureRandBytes(32)
+	if string(a) == string(b) {
+		t.Errorf("SecureRandBytes() returned the same bytes twice")
+	}
+}
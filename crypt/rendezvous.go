@@ -0,0 +1,136 @@
+package crypt
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// Rendezvous hashing, also called highest random weight(HRW) hashing, computes a score for every (node, key) pair
+// and the key belongs to the node with the highest score. When a node is removed, only its keys are moved to the
+// nodes with the second highest score, and a new node only takes the keys for which it gets the highest score.
+// Weighted score: -weight / ln(h), h is the hash of (node, key) normalized to (0,1), see
+// "Weighted Distributed Hash Tables" (Schindelhauer and Schomaker, 2005).
+// Get is O(n), n is the number of nodes, use Maglev for O(1) lookups.
+
+// Rendezvous rendezvous(HRW) hashing with weighted nodes, it is safe for concurrent use
+type Rendezvous struct {
+	mu    sync.RWMutex
+	ht    HashType
+	nodes []rendezvousNode
+}
+
+// rendezvousNode a node with its weight and the hash of its name
+type rendezvousNode struct {
+	name   string
+	weight float64
+	hash   uint64
+}
+
+// NewRendezvous return a rendezvous hashing which uses ht to hash the nodes and keys
+func NewRendezvous(ht HashType) (*Rendezvous, error) {
+	if _, err := hashSum64(nil, ht); err != nil {
+		return nil, err
+	}
+	return &Rendezvous{ht: ht}, nil
+}
+
+// Add add a node with weight, if the node already exists, its weight is updated
+// If weight is less than or equal to 0, the default value of 1 is used
+func (r *Rendezvous) Add(node string, weight float64) {
+	if weight <= 0 || math.IsNaN(weight) || math.IsInf(weight, 0) {
+		weight = 1
+	}
+	h, _ := hashSum64([]byte(node), r.ht)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.nodes {
+		if r.nodes[i].name == node {
+			r.nodes[i].weight = weight
+			return
+		}
+	}
+	r.nodes = append(r.nodes, rendezvousNode{name: node, weight: weight, hash: h})
+}
+
+// Remove remove a node
+func (r *Rendezvous) Remove(node string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range r.nodes {
+		if r.nodes[i].name == node {
+			r.nodes = append(r.nodes[:i], r.nodes[i+1:]...)
+			return
+		}
+	}
+}
+
+// Nodes return all nodes in ascending order
+func (r *Rendezvous) Nodes() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	nodes := make([]string, 0, len(r.nodes))
+	for _, n := range r.nodes {
+		nodes = append(nodes, n.name)
+	}
+	sort.Strings(nodes)
+	return nodes
+}
+
+// Get return the node with the highest score for the key, if there is no node, return ""
+func (r *Rendezvous) Get(key string) string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	kh, _ := hashSum64([]byte(key), r.ht)
+	best, bestScore := "", math.Inf(-1)
+	for _, n := range r.nodes {
+		if s := n.score(kh); s > bestScore || (s == bestScore && n.name < best) {
+			best, bestScore = n.name, s
+		}
+	}
+	return best
+}
+
+// GetN return at most n nodes in descending order of score for the key, the first one is the same as Get.
+// It is usually used to select the replicas of the key
+func (r *Rendezvous) GetN(key string, n int) []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	if n <= 0 || len(r.nodes) == 0 {
+		return nil
+	}
+
+	kh, _ := hashSum64([]byte(key), r.ht)
+	type scored struct {
+		name  string
+		score float64
+	}
+	list := make([]scored, len(r.nodes))
+	for i, node := range r.nodes {
+		list[i] = scored{node.name, node.score(kh)}
+	}
+	sort.Slice(list, func(i, j int) bool {
+		if list[i].score != list[j].score {
+			return list[i].score > list[j].score
+		}
+		return list[i].name < list[j].name
+	})
+
+	if n > len(list) {
+		n = len(list)
+	}
+	nodes := make([]string, n)
+	for i := range nodes {
+		nodes[i] = list[i].name
+	}
+	return nodes
+}
+
+// score return the weighted score of the node for the key hash
+func (n rendezvousNode) score(keyHash uint64) float64 {
+	// Use the high 53 bits as the mantissa, h is in the range of (0,1)
+	h := (float64(mix64(keyHash^n.hash)>>11) + 0.5) / (1 << 53)
+	return -n.weight / math.Log(h)
+}
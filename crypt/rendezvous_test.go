@@ -0,0 +1,87 @@
+package crypt
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+func TestNewRendezvous(t *testing.T) {
+	if _, err := NewRendezvous(HtFnvA64); err != nil {
+		t.Errorf("NewRendezvous() error = %v", err)
+	}
+	if _, err := NewRendezvous(0); err == nil {
+		t.Errorf("NewRendezvous() expect error for unsupported type")
+	}
+}
+
+func TestRendezvous_Remove(t *testing.T) {
+	r, _ := NewRendezvous(HtFnvA64)
+	if got := r.Get("key"); got != "" {
+		t.Errorf("Get() with no node = %v", got)
+	}
+	for i := 0; i < 5; i++ {
+		r.Add("node"+strconv.Itoa(i), 1)
+	}
+
+	before := make(map[string]string)
+	for i := 0; i < 1000; i++ {
+		key := "key" + strconv.Itoa(i)
+		before[key] = r.Get(key)
+	}
+
+	r.Remove("node3")
+	if got := r.Nodes(); !reflect.DeepEqual(got, []string{"node0", "node1", "node2", "node4"}) {
+		t.Errorf("Nodes() = %v", got)
+	}
+	for key, node := range before {
+		got := r.Get(key)
+		if got == "node3" || (node != "node3" && got != node) {
+			t.Errorf("Get(%s) = %v, before remove %v", key, got, node)
+		}
+	}
+}
+
+func TestRendezvous_Weight(t *testing.T) {
+	r, _ := NewRendezvous(HtCrc32)
+	r.Add("heavy", 3)
+	r.Add("light", 1)
+
+	cnt := make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		cnt[r.Get("key"+strconv.Itoa(i))]++
+	}
+	if cnt["heavy"] < 7000 || cnt["heavy"] > 8000 {
+		t.Errorf("distribution = %v, expect about 3:1", cnt)
+	}
+
+	// Update the weight of the existing node
+	r.Add("light", 3)
+	cnt = make(map[string]int)
+	for i := 0; i < 10000; i++ {
+		cnt[r.Get("key"+strconv.Itoa(i))]++
+	}
+	if cnt["heavy"] < 4500 || cnt["heavy"] > 5500 {
+		t.Errorf("distribution = %v, expect about 1:1", cnt)
+	}
+}
+
+func TestRendezvous_GetN(t *testing.T) {
+	r, _ := NewRendezvous(HtMD5)
+	if got := r.GetN("key", 2); got != nil {
+		t.Errorf("GetN() with no node = %v", got)
+	}
+	r.Add("a", 1)
+	r.Add("b", 2)
+	r.Add("c", 1)
+	for i := 0; i < 100; i++ {
+		key := "key" + strconv.Itoa(i)
+		got := r.GetN(key, 2)
+		if len(got) != 2 || got[0] == got[1] || got[0] != r.Get(key) {
+			t.Errorf("GetN(%s, 2) = %v", key, got)
+		}
+		if got = r.GetN(key, 10); len(got) != 3 {
+			t.Errorf("GetN(%s, 10) = %v", key, got)
+		}
+	}
+}
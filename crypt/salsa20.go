@@ -0,0 +1,106 @@
+package crypt
+
+import (
+	"encoding/binary"
+	"math/bits"
+)
+
+// Salsa20 is a stream cipher designed by D. J. Bernstein, the predecessor of ChaCha20.
+// The 16-word state is: the constant words("expand 32-byte k") at 0、5、10、15, the key words at 1..4 and 11..14,
+// the 8-byte nonce at 6..7 and the 64-bit block counter at 8..9, all words are little-endian. A 64-byte keystream
+// block is the state after 20 rounds added to the original state.
+// HSalsa20 derives a subkey from a key and a 16-byte input, XSalsa20 uses it to extend the nonce to 24 bytes:
+// the subkey is HSalsa20(key, nonce[0:16]) and the keystream is Salsa20(subkey, nonce[16:24]), see the paper
+// "Extending the Salsa20 nonce". XSalsa20 is the stream cipher of NaCl secretbox.
+
+const (
+	salsaKeySize    = 32
+	xsalsaNonceSize = 24
+	salsaBlockSize  = 64
+)
+
+// salsaQuarterRound the Salsa20 quarter round
+func salsaQuarterRound(a, b, c, d uint32) (uint32, uint32, uint32, uint32) {
+	b ^= bits.RotateLeft32(a+d, 7)
+	c ^= bits.RotateLeft32(b+a, 9)
+	d ^= bits.RotateLeft32(c+b, 13)
+	a ^= bits.RotateLeft32(d+c, 18)
+	return a, b, c, d
+}
+
+// salsaRounds apply the 20 rounds to the state
+func salsaRounds(x *[16]uint32) {
+	for i := 0; i < 10; i++ {
+		// column rounds
+		x[0], x[4], x[8], x[12] = salsaQuarterRound(x[0], x[4], x[8], x[12])
+		x[5], x[9], x[13], x[1] = salsaQuarterRound(x[5], x[9], x[13], x[1])
+		x[10], x[14], x[2], x[6] = salsaQuarterRound(x[10], x[14], x[2], x[6])
+		x[15], x[3], x[7], x[11] = salsaQuarterRound(x[15], x[3], x[7], x[11])
+		// row rounds
+		x[0], x[1], x[2], x[3] = salsaQuarterRound(x[0], x[1], x[2], x[3])
+		x[5], x[6], x[7], x[4] = salsaQuarterRound(x[5], x[6], x[7], x[4])
+		x[10], x[11], x[8], x[9] = salsaQuarterRound(x[10], x[11], x[8], x[9])
+		x[15], x[12], x[13], x[14] = salsaQuarterRound(x[15], x[12], x[13], x[14])
+	}
+}
+
+// salsaInitState return the initial state of key and the 16 bytes input(nonce and counter, or the HSalsa20 input)
+func salsaInitState(key *[salsaKeySize]byte, input []byte) [16]uint32 {
+	var s [16]uint32
+	s[0], s[5], s[10], s[15] = chachaConstants[0], chachaConstants[1], chachaConstants[2], chachaConstants[3]
+	for i := 0; i < 4; i++ {
+		s[1+i] = binary.LittleEndian.Uint32(key[i*4:])
+		s[11+i] = binary.LittleEndian.Uint32(key[16+i*4:])
+		s[6+i] = binary.LittleEndian.Uint32(input[i*4:])
+	}
+	return s
+}
+
+// hSalsa20 return the subkey derived from key and the 16-byte input
+func hSalsa20(key *[salsaKeySize]byte, input []byte) [salsaKeySize]byte {
+	x := salsaInitState(key, input)
+	salsaRounds(&x)
+
+	var subkey [salsaKeySize]byte
+	for i, j := range [8]int{0, 5, 10, 15, 6, 7, 8, 9} {
+		binary.LittleEndian.PutUint32(subkey[i*4:], x[j])
+	}
+	return subkey
+}
+
+// salsa20XORKeyStream XOR src with the Salsa20 keystream of (key, 8-byte nonce) starting at block counter, and write
+// the result to dst. dst and src may overlap entirely or not at all
+func salsa20XORKeyStream(dst, src []byte, key *[salsaKeySize]byte, nonce []byte, counter uint64) {
+	var input [16]byte
+	copy(input[:8], nonce)
+	binary.LittleEndian.PutUint64(input[8:], counter)
+	state := salsaInitState(key, input[:])
+
+	var block [salsaBlockSize]byte
+	for len(src) > 0 {
+		x := state
+		salsaRounds(&x)
+		for i := range x {
+			binary.LittleEndian.PutUint32(block[i*4:], x[i]+state[i])
+		}
+		n := len(src)
+		if n > salsaBlockSize {
+			n = salsaBlockSize
+		}
+		for i := 0; i < n; i++ {
+			dst[i] = src[i] ^ block[i]
+		}
+		dst, src = dst[n:], src[n:]
+		// the 64-bit counter is in word 8(low) and word 9(high)
+		state[8]++
+		if state[8] == 0 {
+			state[9]++
+		}
+	}
+}
+
+// xsalsa20XORKeyStream XOR src with the XSalsa20 keystream of (key, 24-byte nonce) starting at block 0
+func xsalsa20XORKeyStream(dst, src []byte, key *[salsaKeySize]byte, nonce []byte) {
+	subkey := hSalsa20(key, nonce[:16])
+	salsa20XORKeyStream(dst, src, &subkey, nonce[16:], 0)
+}
@@ -0,0 +1,69 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+func TestHSalsa20(t *testing.T) {
+	tests := []struct {
+		name  string
+		key   string
+		input string
+		want  string
+	}{
+		{
+			// NaCl tests/core1.c: the box key of the shared secret of Alice and Bob
+			name:  "NaClCore1",
+			key:   "4a5d9d5ba4ce2de1728e3bf480350f25e07e21c947d19e3376f09b3c1e161742",
+			input: "00000000000000000000000000000000",
+			want:  "1b27556473e985d462cd51197a9a46c76009549eac6474f206c4ee0844f68389",
+		},
+		{
+			// NaCl tests/core2.c: the XSalsa20 subkey of the first 16 bytes of the nonce
+			name:  "NaClCore2",
+			key:   "1b27556473e985d462cd51197a9a46c76009549eac6474f206c4ee0844f68389",
+			input: "69696ee955b62b73cd62bda875fc73d6",
+			want:  "dc908dda0b9344a953629b733820778880f3ceb421bb61b91cbd4c3e66256ce4",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var key [salsaKeySize]byte
+			k, _ := hex.DecodeString(tt.key)
+			copy(key[:], k)
+			input, _ := hex.DecodeString(tt.input)
+			if got := hSalsa20(&key, input); hex.EncodeToString(got[:]) != tt.want {
+				t.Errorf("hSalsa20() = %x, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSalsa20XORKeyStream(t *testing.T) {
+	var key [salsaKeySize]byte
+	copy(key[:], commonKey32)
+	nonce := []byte("12345678")
+
+	// The keystream of 3 blocks from the counter 2^32-1 crosses the boundary of the low counter word, it must be the
+	// same as the blocks generated one by one
+	got := make([]byte, 3*salsaBlockSize)
+	salsa20XORKeyStream(got, got, &key, nonce, 1<<32-1)
+	for i := 0; i < 3; i++ {
+		block := make([]byte, salsaBlockSize)
+		salsa20XORKeyStream(block, block, &key, nonce, 1<<32-1+uint64(i))
+		if !bytes.Equal(got[i*salsaBlockSize:(i+1)*salsaBlockSize], block) {
+			t.Errorf("salsa20XORKeyStream() block %d = %x, want %x", i, got[i*salsaBlockSize:], block)
+		}
+	}
+
+	// XOR twice return the original data
+	src := []byte("Salsa20 is a stream cipher, XOR the keystream twice return the original data")
+	dst := make([]byte, len(src))
+	salsa20XORKeyStream(dst, src, &key, nonce, 0)
+	salsa20XORKeyStream(dst, dst, &key, nonce, 0)
+	if !bytes.Equal(dst, src) {
+		t.Errorf("salsa20XORKeyStream() twice = %s, want %s", dst, src)
+	}
+}
@@ -0,0 +1,324 @@
+package crypt
+
+import (
+	"errors"
+	"math"
+	"sort"
+)
+
+// The sampling functions use the source of the Rand* functions(see SetRandSource), the methods of Rand use the
+// seeded Rand, so the samples are reproducible. They work on the indexes, such as:
+//  Shuffle(len(users), func(i, j int) { users[i], users[j] = users[j], users[i] })
+//  for _, i := range SampleIndexes(len(users), 10) { ... users[i] ... }
+// A weighted choice is O(n) by WeightedChoice, or O(1) by an AliasTable built in O(n) by the Vose's alias method.
+// StratifiedSplit splits the items into the groups in the same proportions within every stratum, so that the groups
+// of an experiment have the same mixture of the strata, such as the countries or the platforms.
+
+// Shuffle shuffle n elements by Fisher-Yates, swap swaps the elements with indexes i and j
+func Shuffle(n int, swap func(i, j int)) {
+	shuffle(randSource(), n, swap)
+}
+
+// Perm return a random permutation of [0, n)
+func Perm(n int) []int {
+	return perm(randSource(), n)
+}
+
+// SampleIndexes return k different random indexes of [0, n) in random order, the sampling without replacement.
+// k is set to n if k > n
+func SampleIndexes(n, k int) []int {
+	return sampleIndexes(randSource(), n, k)
+}
+
+// WeightedChoice return a random index i with the probability weights[i]/sum(weights).
+// The weights must be non-negative finite numbers and not all zero
+func WeightedChoice(weights []float64) (int, error) {
+	return weightedChoice(randSource(), weights)
+}
+
+// StratifiedSplit split the items into len(weights) groups, strata[i] is the stratum of the item i. Within every
+// stratum, the items are shuffled and the group sizes are proportional to the weights. Return the group of every item
+func StratifiedSplit(strata []string, weights []float64) ([]int, error) {
+	return stratifiedSplit(randSource(), strata, weights)
+}
+
+// Shuffle shuffle n elements by Fisher-Yates, swap swaps the elements with indexes i and j
+func (r *Rand) Shuffle(n int, swap func(i, j int)) {
+	shuffle(r.src, n, swap)
+}
+
+// Perm return a random permutation of [0, n)
+func (r *Rand) Perm(n int) []int {
+	return perm(r.src, n)
+}
+
+// SampleIndexes return k different random indexes of [0, n) in random order
+func (r *Rand) SampleIndexes(n, k int) []int {
+	return sampleIndexes(r.src, n, k)
+}
+
+// WeightedChoice return a random index i with the probability weights[i]/sum(weights)
+func (r *Rand) WeightedChoice(weights []float64) (int, error) {
+	return weightedChoice(r.src, weights)
+}
+
+// StratifiedSplit split the items into len(weights) groups in the same proportions within every stratum
+func (r *Rand) StratifiedSplit(strata []string, weights []float64) ([]int, error) {
+	return stratifiedSplit(r.src, strata, weights)
+}
+
+// AliasTable pick the weighted random indexes in O(1) by the Vose's alias method, it is safe for concurrent use if
+// the source is
+type AliasTable struct {
+	prob  []float64
+	alias []int
+}
+
+// NewAliasTable return an alias table of the weights, the weights must be non-negative finite numbers and not all zero
+func NewAliasTable(weights []float64) (*AliasTable, error) {
+	sum, err := checkWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	n := len(weights)
+	t := &AliasTable{prob: make([]float64, n), alias: make([]int, n)}
+	// The scaled weights, the average is 1
+	scaled := make([]float64, n)
+	var small, large []int
+	for i, w := range weights {
+		scaled[i] = w * float64(n) / sum
+		if scaled[i] < 1 {
+			small = append(small, i)
+		} else {
+			large = append(large, i)
+		}
+	}
+	// Every column is filled to 1 by a small weight and a part of a large weight
+	for len(small) > 0 && len(large) > 0 {
+		s, l := small[len(small)-1], large[len(large)-1]
+		small, large = small[:len(small)-1], large[:len(large)-1]
+		t.prob[s], t.alias[s] = scaled[s], l
+		scaled[l] += scaled[s] - 1
+		if scaled[l] < 1 {
+			small = append(small, l)
+		} else {
+			large = append(large, l)
+		}
+	}
+	// The remaining are 1 except the floating-point errors
+	for _, i := range append(small, large...) {
+		t.prob[i], t.alias[i] = 1, i
+	}
+	return t, nil
+}
+
+// Len return the number of the weights
+func (t *AliasTable) Len() int {
+	return len(t.prob)
+}
+
+// Pick return a random index i with the probability weights[i]/sum(weights)
+func (t *AliasTable) Pick() int {
+	return t.PickFrom(randSource())
+}
+
+// PickFrom is like Pick but uses src, such as a seeded Rand
+func (t *AliasTable) PickFrom(src Source) int {
+	i := randIntN(src, len(t.prob))
+	if randFloat64(src) < t.prob[i] {
+		return i
+	}
+	return t.alias[i]
+}
+
+// Reservoir keep a uniform random sample of k items of a stream of unknown length by the reservoir sampling, every
+// item added has the same probability k/n to be in the sample. It is not safe for concurrent use
+type Reservoir struct {
+	k     int
+	count int
+	items []interface{}
+	src   Source
+}
+
+// NewReservoir return a reservoir of k items, src is the random source, nil means the source of the Rand* functions
+func NewReservoir(k int, src Source) *Reservoir {
+	if k < 0 {
+		k = 0
+	}
+	return &Reservoir{k: k, items: make([]interface{}, 0, k), src: src}
+}
+
+// Add add an item of the stream
+func (r *Reservoir) Add(item interface{}) {
+	r.count++
+	if len(r.items) < r.k {
+		r.items = append(r.items, item)
+		return
+	}
+	src := r.src
+	if src == nil {
+		src = randSource()
+	}
+	// Replace a random item with the probability k/count
+	if j := randIntN(src, r.count); j < r.k {
+		r.items[j] = item
+	}
+}
+
+// Items return the sampled items, it has min(k, Count()) items
+func (r *Reservoir) Items() []interface{} {
+	return append([]interface{}{}, r.items...)
+}
+
+// Count return the number of the items added
+func (r *Reservoir) Count() int {
+	return r.count
+}
+
+// shuffle shuffle n elements by Fisher-Yates with src
+func shuffle(src Source, n int, swap func(i, j int)) {
+	for i := n - 1; i > 0; i-- {
+		swap(i, randIntN(src, i+1))
+	}
+}
+
+// perm return a random permutation of [0, n) with src
+func perm(src Source, n int) []int {
+	if n <= 0 {
+		return []int{}
+	}
+	p := make([]int, n)
+	// The inside-out Fisher-Yates
+	for i := range p {
+		j := randIntN(src, i+1)
+		p[i] = p[j]
+		p[j] = i
+	}
+	return p
+}
+
+// sampleIndexes return k different random indexes of [0, n) with src, by the Floyd's algorithm if k is much smaller
+// than n, otherwise by a partial Fisher-Yates
+func sampleIndexes(src Source, n, k int) []int {
+	if k > n {
+		k = n
+	}
+	if k <= 0 {
+		return []int{}
+	}
+	if k > n/4 {
+		p := make([]int, n)
+		for i := range p {
+			p[i] = i
+		}
+		for i := 0; i < k; i++ {
+			j := i + randIntN(src, n-i)
+			p[i], p[j] = p[j], p[i]
+		}
+		return p[:k]
+	}
+
+	res := make([]int, 0, k)
+	seen := make(map[int]bool, k)
+	for j := n - k; j < n; j++ {
+		t := randIntN(src, j+1)
+		if seen[t] {
+			t = j
+		}
+		seen[t] = true
+		res = append(res, t)
+	}
+	// The set of the Floyd's algorithm is uniform, but the order is not
+	shuffle(src, len(res), func(i, j int) { res[i], res[j] = res[j], res[i] })
+	return res
+}
+
+// weightedChoice return a random index i with the probability weights[i]/sum(weights) with src
+func weightedChoice(src Source, weights []float64) (int, error) {
+	sum, err := checkWeights(weights)
+	if err != nil {
+		return 0, err
+	}
+	x := randFloat64(src) * sum
+	last := 0
+	for i, w := range weights {
+		if w == 0 {
+			continue
+		}
+		if x < w {
+			return i, nil
+		}
+		x -= w
+		last = i
+	}
+	// The floating-point errors
+	return last, nil
+}
+
+// stratifiedSplit split the items of the strata into the groups of the weights with src
+func stratifiedSplit(src Source, strata []string, weights []float64) ([]int, error) {
+	sum, err := checkWeights(weights)
+	if err != nil {
+		return nil, err
+	}
+
+	var order []string
+	members := make(map[string][]int)
+	for i, s := range strata {
+		if _, ok := members[s]; !ok {
+			order = append(order, s)
+		}
+		members[s] = append(members[s], i)
+	}
+
+	groups := make([]int, len(strata))
+	for _, s := range order {
+		items := members[s]
+		shuffle(src, len(items), func(i, j int) { items[i], items[j] = items[j], items[i] })
+
+		// The largest remainder method, the ties are broken randomly so no group is favored
+		m := float64(len(items))
+		quotas := make([]int, len(weights))
+		remainders := make([]float64, len(weights))
+		assigned := 0
+		for g, w := range weights {
+			exact := m * w / sum
+			quotas[g] = int(math.Floor(exact))
+			remainders[g] = exact - float64(quotas[g])
+			assigned += quotas[g]
+		}
+		byRemainder := perm(src, len(weights))
+		sort.SliceStable(byRemainder, func(i, j int) bool {
+			return remainders[byRemainder[i]] > remainders[byRemainder[j]]
+		})
+		for i := 0; assigned < len(items); i++ {
+			quotas[byRemainder[i%len(byRemainder)]]++
+			assigned++
+		}
+
+		pos := 0
+		for g, q := range quotas {
+			for _, item := range items[pos : pos+q] {
+				groups[item] = g
+			}
+			pos += q
+		}
+	}
+	return groups, nil
+}
+
+// checkWeights return the sum of the weights, an error if a weight is negative、NaN or infinite, or all are zero
+func checkWeights(weights []float64) (float64, error) {
+	var sum float64
+	for _, w := range weights {
+		if w < 0 || math.IsNaN(w) || math.IsInf(w, 0) {
+			return 0, errors.New(sErrWeightsInvalid)
+		}
+		sum += w
+	}
+	if sum <= 0 || math.IsInf(sum, 0) {
+		return 0, errors.New(sErrWeightsInvalid)
+	}
+	return sum, nil
+}
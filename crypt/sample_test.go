@@ -0,0 +1,272 @@
+package crypt
+
+import (
+	"math"
+	"sort"
+	"testing"
+)
+
+// isPerm return true if p is a permutation of [0, len(p))
+func isPerm(p []int) bool {
+	s := append([]int{}, p...)
+	sort.Ints(s)
+	for i, v := range s {
+		if v != i {
+			return false
+		}
+	}
+	return true
+}
+
+func TestShuffle(t *testing.T) {
+	s := []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}
+	Shuffle(len(s), func(i, j int) { s[i], s[j] = s[j], s[i] })
+	if !isPerm(s) {
+		t.Errorf("Shuffle() = %v", s)
+	}
+	Shuffle(0, func(i, j int) { t.Errorf("swap should not be called") })
+
+	// Every element is at every position with the same probability
+	var counts [3][3]int
+	for i := 0; i < 30000; i++ {
+		s := []int{0, 1, 2}
+		Shuffle(3, func(i, j int) { s[i], s[j] = s[j], s[i] })
+		for pos, v := range s {
+			counts[v][pos]++
+		}
+	}
+	for v := range counts {
+		for pos, c := range counts[v] {
+			if c < 9500 || c > 10500 {
+				t.Errorf("%d at position %d: %d times, want about 10000", v, pos, c)
+			}
+		}
+	}
+}
+
+func TestPerm(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 10, 100} {
+		if p := Perm(n); len(p) != n || !isPerm(p) {
+			t.Errorf("Perm(%d) = %v", n, p)
+		}
+	}
+	if p := Perm(-1); len(p) != 0 {
+		t.Errorf("Perm(-1) = %v", p)
+	}
+
+	r1, _ := NewRand(1, RtPCG)
+	r2, _ := NewRand(1, RtPCG)
+	p1, p2 := r1.Perm(20), r2.Perm(20)
+	for i := range p1 {
+		if p1[i] != p2[i] {
+			t.Fatalf("the Perm of the same seed = %v, %v", p1, p2)
+		}
+	}
+}
+
+func TestSampleIndexes(t *testing.T) {
+	tests := []struct {
+		name    string
+		n, k    int
+		wantLen int
+	}{
+		{name: "Floyd", n: 1000, k: 10, wantLen: 10},
+		{name: "FisherYates", n: 10, k: 8, wantLen: 8},
+		{name: "All", n: 10, k: 10, wantLen: 10},
+		{name: "KGreaterThanN", n: 5, k: 10, wantLen: 5},
+		{name: "Zero", n: 10, k: 0, wantLen: 0},
+		{name: "Negative", n: 10, k: -1, wantLen: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := SampleIndexes(tt.n, tt.k)
+			if len(got) != tt.wantLen {
+				t.Fatalf("SampleIndexes() = %v, want %d indexes", got, tt.wantLen)
+			}
+			seen := make(map[int]bool)
+			for _, i := range got {
+				if i < 0 || i >= tt.n || seen[i] {
+					t.Fatalf("SampleIndexes() = %v", got)
+				}
+				seen[i] = true
+			}
+		})
+	}
+
+	// Every index is sampled with the probability k/n, and is at every position with the same probability
+	for _, k := range []int{2, 5} {
+		var counts [20]int
+		var first [20]int
+		for i := 0; i < 20000; i++ {
+			s := SampleIndexes(20, k)
+			for _, idx := range s {
+				counts[idx]++
+			}
+			first[s[0]]++
+		}
+		want := 20000 * k / 20
+		for idx, c := range counts {
+			if math.Abs(float64(c-want)) > float64(want)/10 {
+				t.Errorf("k = %d, index %d is sampled %d times, want about %d", k, idx, c, want)
+			}
+			if math.Abs(float64(first[idx]-1000)) > 150 {
+				t.Errorf("k = %d, index %d is the first %d times, want about 1000", k, idx, first[idx])
+			}
+		}
+	}
+}
+
+func TestWeightedChoice(t *testing.T) {
+	weights := []float64{1, 2, 3, 0, 4}
+	table, err := NewAliasTable(weights)
+	if err != nil {
+		t.Fatalf("NewAliasTable() error = %v", err)
+	}
+	if table.Len() != len(weights) {
+		t.Errorf("Len() = %v, want %v", table.Len(), len(weights))
+	}
+	r, _ := NewRand(3, RtXoshiro256)
+
+	pickers := map[string]func() int{
+		"WeightedChoice": func() int { i, _ := WeightedChoice(weights); return i },
+		"AliasTable":     table.Pick,
+		"AliasTableRand": func() int { return table.PickFrom(r) },
+	}
+	for name, pick := range pickers {
+		t.Run(name, func(t *testing.T) {
+			const n = 100000
+			counts := make([]int, len(weights))
+			for i := 0; i < n; i++ {
+				counts[pick()]++
+			}
+			for i, w := range weights {
+				want := n * w / 10
+				if math.Abs(float64(counts[i])-want) > n*0.01 {
+					t.Errorf("index %d is picked %d times, want about %v", i, counts[i], want)
+				}
+			}
+		})
+	}
+
+	invalid := [][]float64{nil, {0, 0}, {1, -1}, {1, math.NaN()}, {math.Inf(1)}, {math.MaxFloat64, math.MaxFloat64}}
+	for _, w := range invalid {
+		if _, err := WeightedChoice(w); err == nil {
+			t.Errorf("WeightedChoice(%v) should return an error", w)
+		}
+		if _, err := NewAliasTable(w); err == nil {
+			t.Errorf("NewAliasTable(%v) should return an error", w)
+		}
+	}
+
+	single, _ := NewAliasTable([]float64{0, 5, 0})
+	for i := 0; i < 100; i++ {
+		if got := single.Pick(); got != 1 {
+			t.Fatalf("Pick() = %v, want 1", got)
+		}
+	}
+}
+
+func TestReservoir(t *testing.T) {
+	r := NewReservoir(10, nil)
+	for i := 0; i < 5; i++ {
+		r.Add(i)
+	}
+	if items := r.Items(); len(items) != 5 || r.Count() != 5 {
+		t.Errorf("Items() = %v, Count() = %v", items, r.Count())
+	}
+	for i := 5; i < 1000; i++ {
+		r.Add(i)
+	}
+	items := r.Items()
+	seen := make(map[int]bool)
+	for _, item := range items {
+		v := item.(int)
+		if v < 0 || v >= 1000 || seen[v] {
+			t.Fatalf("Items() = %v", items)
+		}
+		seen[v] = true
+	}
+	if len(items) != 10 || r.Count() != 1000 {
+		t.Errorf("Items() = %v, Count() = %v", items, r.Count())
+	}
+
+	// Every item is in the sample with the probability k/n
+	src, _ := NewRand(5, RtPCG)
+	var counts [20]int
+	for i := 0; i < 20000; i++ {
+		r := NewReservoir(5, src)
+		for j := 0; j < 20; j++ {
+			r.Add(j)
+		}
+		for _, item := range r.Items() {
+			counts[item.(int)]++
+		}
+	}
+	for i, c := range counts {
+		if c < 4500 || c > 5500 {
+			t.Errorf("item %d is sampled %d times, want about 5000", i, c)
+		}
+	}
+
+	if empty := NewReservoir(-1, nil); len(empty.Items()) != 0 {
+		t.Errorf("Items() of NewReservoir(-1) = %v", empty.Items())
+	} else {
+		empty.Add(1)
+		if len(empty.Items()) != 0 || empty.Count() != 1 {
+			t.Errorf("Items() = %v, Count() = %v", empty.Items(), empty.Count())
+		}
+	}
+}
+
+func TestStratifiedSplit(t *testing.T) {
+	var strata []string
+	sizes := map[string]int{"cn": 101, "us": 50, "jp": 7, "de": 1}
+	for s, n := range sizes {
+		for i := 0; i < n; i++ {
+			strata = append(strata, s)
+		}
+	}
+	Shuffle(len(strata), func(i, j int) { strata[i], strata[j] = strata[j], strata[i] })
+
+	weights := []float64{0.5, 0.3, 0.2}
+	groups, err := StratifiedSplit(strata, weights)
+	if err != nil {
+		t.Fatalf("StratifiedSplit() error = %v", err)
+	}
+	if len(groups) != len(strata) {
+		t.Fatalf("len(StratifiedSplit()) = %v, want %v", len(groups), len(strata))
+	}
+	counts := make(map[string][]int)
+	for i, g := range groups {
+		if counts[strata[i]] == nil {
+			counts[strata[i]] = make([]int, len(weights))
+		}
+		counts[strata[i]][g]++
+	}
+	for s, n := range sizes {
+		for g, w := range weights {
+			// Every group of every stratum has the floor or the ceil of the exact size
+			exact := float64(n) * w
+			if c := float64(counts[s][g]); c < math.Floor(exact) || c > math.Ceil(exact) {
+				t.Errorf("stratum %v group %d has %v items, want about %v", s, g, c, exact)
+			}
+		}
+	}
+
+	r1, _ := NewRand(9, RtXoshiro256)
+	r2, _ := NewRand(9, RtXoshiro256)
+	g1, _ := r1.StratifiedSplit(strata, []float64{1, 1})
+	g2, _ := r2.StratifiedSplit(strata, []float64{1, 1})
+	for i := range g1 {
+		if g1[i] != g2[i] {
+			t.Fatalf("the StratifiedSplit of the same seed are different")
+		}
+	}
+
+	if _, err := StratifiedSplit(strata, []float64{0, 0}); err == nil {
+		t.Errorf("StratifiedSplit() with invalid weights should return an error")
+	}
+	if groups, err := StratifiedSplit(nil, []float64{1}); err != nil || len(groups) != 0 {
+		t.Errorf("StratifiedSplit(nil) = %v, error = %v", groups, err)
+	}
+}
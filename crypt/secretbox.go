@@ -0,0 +1,161 @@
+package crypt
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"errors"
+	"io"
+)
+
+// The secretbox and box of NaCl/libsodium.
+// secretbox(crypto_secretbox_xsalsa20poly1305) encrypts and authenticates a message with a 32-byte secret key and a
+// 24-byte nonce: the one-time Poly1305 key is the first 32 bytes of the XSalsa20 keystream, the message is encrypted
+// with the rest of the keystream, and the 16-byte tag authenticates the ciphertext.
+// box(crypto_box_curve25519xsalsa20poly1305) computes the shared secret by X25519 of the own private key and the
+// peer's public key, derives the secretbox key by HSalsa20(shared secret, 16 zero bytes), then works as secretbox.
+// The functions here always use a random nonce and prepend it to the output, the result is
+//  nonce(24 bytes) || tag(16 bytes) || ciphertext
+// which is the nonce followed by the output of crypto_secretbox_easy/crypto_box_easy of libsodium(and
+// nacl.secretbox/nacl.box of TweetNaCl-js, nacl.secret.SecretBox/nacl.public.Box of PyNaCl).
+// XSalsa20 and X25519 are implemented in salsa20.go and curve25519.go rather than taken from golang.org/x/crypto,
+// which would raise the go 1.16 requirement of the module(x/crypto requires go 1.17 or later). The field arithmetic
+// and the Montgomery ladder are constant time, and the tests use the vectors of NaCl and RFC 7748.
+
+const (
+	secretBoxKeySize  = 32
+	secretBoxOverhead = xsalsaNonceSize + poly1305TagSize
+)
+
+// SecretBoxSeal Encrypts and authenticates plaintext with the 32-byte key and a random nonce, the result is
+// nonce(24 bytes) || tag(16 bytes) || ciphertext, it is compatible with crypto_secretbox_easy of libsodium
+func SecretBoxSeal(plaintext, key []byte) ([]byte, error) {
+	if len(key) != secretBoxKeySize {
+		return nil, errors.New(sErrKeySizeInvalid)
+	}
+	out := make([]byte, secretBoxOverhead+len(plaintext))
+	if _, err := io.ReadFull(rand.Reader, out[:xsalsaNonceSize]); err != nil {
+		return nil, err
+	}
+	var k [secretBoxKeySize]byte
+	copy(k[:], key)
+	secretBoxSeal(out[xsalsaNonceSize:], plaintext, out[:xsalsaNonceSize], &k)
+	return out, nil
+}
+
+// SecretBoxOpen Verifies and decrypts the result of SecretBoxSeal
+func SecretBoxOpen(box, key []byte) ([]byte, error) {
+	if len(key) != secretBoxKeySize {
+		return nil, errors.New(sErrKeySizeInvalid)
+	}
+	if len(box) < secretBoxOverhead {
+		return nil, errors.New(sErrDataInvalid)
+	}
+	var k [secretBoxKeySize]byte
+	copy(k[:], key)
+	return secretBoxOpen(box[xsalsaNonceSize:], box[:xsalsaNonceSize], &k)
+}
+
+// BoxGenKey Generate a Curve25519 key pair for box. Return value is public key(32 bytes),private key(32 bytes),error
+func BoxGenKey() ([]byte, []byte, error) {
+	var privateKey [x25519Size]byte
+	if _, err := io.ReadFull(rand.Reader, privateKey[:]); err != nil {
+		return nil, nil, err
+	}
+	publicKey := x25519(&privateKey, &x25519Basepoint)
+	return publicKey[:], privateKey[:], nil
+}
+
+// BoxPublicKey return the public key of the 32-byte private key
+func BoxPublicKey(privateKey []byte) ([]byte, error) {
+	if len(privateKey) != x25519Size {
+		return nil, errors.New(sErrPrivateKeyErr)
+	}
+	var priv [x25519Size]byte
+	copy(priv[:], privateKey)
+	publicKey := x25519(&priv, &x25519Basepoint)
+	return publicKey[:], nil
+}
+
+// BoxSharedKey return the 32-byte secretbox key shared by the owner of privateKey and the owner of peersPublicKey,
+// it is the same as crypto_box_beforenm of libsodium. The messages of BoxSeal can be opened by SecretBoxOpen with
+// the shared key, so the key exchange is done only once when many messages are sent to the same peer
+func BoxSharedKey(peersPublicKey, privateKey []byte) ([]byte, error) {
+	k, err := boxSharedKey(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return k[:], nil
+}
+
+// BoxSeal Encrypts and authenticates plaintext for the owner of peersPublicKey with the own privateKey and a random
+// nonce, the result is nonce(24 bytes) || tag(16 bytes) || ciphertext, it is compatible with crypto_box_easy of
+// libsodium
+func BoxSeal(plaintext, peersPublicKey, privateKey []byte) ([]byte, error) {
+	k, err := boxSharedKey(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return SecretBoxSeal(plaintext, k[:])
+}
+
+// BoxOpen Verifies and decrypts the result of BoxSeal with the sender's public key and the own private key
+func BoxOpen(box, peersPublicKey, privateKey []byte) ([]byte, error) {
+	k, err := boxSharedKey(peersPublicKey, privateKey)
+	if err != nil {
+		return nil, err
+	}
+	return SecretBoxOpen(box, k[:])
+}
+
+// boxSharedKey return HSalsa20(X25519(privateKey, peersPublicKey), 0)
+func boxSharedKey(peersPublicKey, privateKey []byte) ([secretBoxKeySize]byte, error) {
+	var pub, priv [x25519Size]byte
+	if len(peersPublicKey) != x25519Size {
+		return [secretBoxKeySize]byte{}, errors.New(sErrPublicKeyErr)
+	}
+	if len(privateKey) != x25519Size {
+		return [secretBoxKeySize]byte{}, errors.New(sErrPrivateKeyErr)
+	}
+	copy(pub[:], peersPublicKey)
+	copy(priv[:], privateKey)
+
+	shared := x25519(&priv, &pub)
+	// A public key of small order makes the shared secret all zeros, libsodium rejects it too
+	var zero [x25519Size]byte
+	if subtle.ConstantTimeCompare(shared[:], zero[:]) == 1 {
+		return [secretBoxKeySize]byte{}, errors.New(sErrPublicKeyErr)
+	}
+	return hSalsa20(&shared, zero[:16]), nil
+}
+
+// secretBoxSeal write tag || ciphertext of plaintext to out, len(out) must be len(plaintext)+16
+func secretBoxSeal(out, plaintext, nonce []byte, key *[secretBoxKeySize]byte) {
+	// The first 32 bytes of the keystream are the Poly1305 key
+	buf := make([]byte, 32+len(plaintext))
+	copy(buf[32:], plaintext)
+	xsalsa20XORKeyStream(buf, buf, key, nonce)
+
+	var polyKey [32]byte
+	copy(polyKey[:], buf[:32])
+	var tag [poly1305TagSize]byte
+	p := newPoly1305(&polyKey)
+	p.Write(buf[32:])
+	p.Sum(&tag)
+	copy(out, tag[:])
+	copy(out[poly1305TagSize:], buf[32:])
+}
+
+// secretBoxOpen verify and decrypt tag || ciphertext
+func secretBoxOpen(box, nonce []byte, key *[secretBoxKeySize]byte) ([]byte, error) {
+	tag, ciphertext := box[:poly1305TagSize], box[poly1305TagSize:]
+	buf := make([]byte, 32+len(ciphertext))
+	copy(buf[32:], ciphertext)
+	xsalsa20XORKeyStream(buf, buf, key, nonce)
+
+	var polyKey [32]byte
+	copy(polyKey[:], buf[:32])
+	if !poly1305Verify(tag, ciphertext, &polyKey) {
+		return nil, errors.New(sErrAuthFailed)
+	}
+	return buf[32:], nil
+}
@@ -0,0 +1,153 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/hex"
+	"testing"
+)
+
+// The test vectors of NaCl tests/box.c
+var (
+	naclAliceSk, _ = hex.DecodeString("77076d0a7318a57d3c16c17251b26645df4c2f87ebc0992ab177fba51db92c2a")
+	naclAlicePk, _ = hex.DecodeString("8520f0098930a754748b7ddcb43ef75a0dbf3a0d26381af4eba4a98eaa9b4e6a")
+	naclBobSk, _   = hex.DecodeString("5dab087e624a8a4b79e17f8b83800ee66f3bb1292618b6fd1c2f8b27ff88e0eb")
+	naclBobPk, _   = hex.DecodeString("de9edb7d7b7dc1b4d35b61c2ece435373f8343c85b78674dadfc7e146f882b4f")
+	naclNonce, _   = hex.DecodeString("69696ee955b62b73cd62bda875fc73d68219e0036b7a0b37")
+	naclMessage, _ = hex.DecodeString("be075fc53c81f2d5cf141316ebeb0c7b5228c52a4c62cbd44b66849b64244ffce5ecbaaf33bd" +
+		"751a1ac728d45e6c61296cdc3c01233561f41db66cce314adb310e3be8250c46f06dceea3a7fa1348057e2f6556ad6b1318a024a838f21" +
+		"af1fde048977eb48f59ffd4924ca1c60902e52f0a089bc76897040e082f937763848645e0705")
+	naclBox, _ = hex.DecodeString("f3ffc7703f9400e52a7dfb4b3d3305d98e993b9f48681273c29650ba32fc76ce48332ea7164d96a4" +
+		"476fb8c531a1186ac0dfc17c98dce87b4da7f011ec48c97271d2c20f9b928fe2270d6fb863d51738b48eeee314a7cc8ab932164548e5" +
+		"26ae90224368517acfeabd6bb3732bc0e9da99832b61ca01b6de56244a9e88d5f9b37973f622a43d14a6599b1f654cb45a74e355a5")
+)
+
+func TestBoxNaClVector(t *testing.T) {
+	pk, err := BoxPublicKey(naclAliceSk)
+	if err != nil || !bytes.Equal(pk, naclAlicePk) {
+		t.Errorf("BoxPublicKey() = %x, %v, want %x", pk, err, naclAlicePk)
+	}
+	aliceKey, _ := BoxSharedKey(naclBobPk, naclAliceSk)
+	bobKey, _ := BoxSharedKey(naclAlicePk, naclBobSk)
+	want := "1b27556473e985d462cd51197a9a46c76009549eac6474f206c4ee0844f68389"
+	if hex.EncodeToString(aliceKey) != want || hex.EncodeToString(bobKey) != want {
+		t.Errorf("BoxSharedKey() = %x and %x, want %v", aliceKey, bobKey, want)
+	}
+
+	// crypto_box_easy(message, nonce, bob pk, alice sk)
+	var key [secretBoxKeySize]byte
+	copy(key[:], aliceKey)
+	out := make([]byte, poly1305TagSize+len(naclMessage))
+	secretBoxSeal(out, naclMessage, naclNonce, &key)
+	if !bytes.Equal(out, naclBox) {
+		t.Errorf("secretBoxSeal() = %x, want %x", out, naclBox)
+	}
+
+	// Bob opens the box with the nonce prepended
+	box := append(append([]byte{}, naclNonce...), naclBox...)
+	got, err := BoxOpen(box, naclAlicePk, naclBobSk)
+	if err != nil || !bytes.Equal(got, naclMessage) {
+		t.Errorf("BoxOpen() = %x, %v, want %x", got, err, naclMessage)
+	}
+}
+
+func TestSecretBoxSeal(t *testing.T) {
+	type args struct {
+		data []byte
+		key  []byte
+	}
+	tests := []struct {
+		name    string
+		args    args
+		wantErr bool
+	}{
+		{
+			name:    "KeyInvalidLen",
+			args:    args{data: commonOriginData, key: commonKey24},
+			wantErr: true,
+		},
+		{
+			name: "DataEmpty",
+			args: args{data: []byte{}, key: commonKey32},
+		},
+		{
+			name: "Normal",
+			args: args{data: commonOriginData, key: commonKey32},
+		},
+		{
+			name: "MultiBlock",
+			args: args{data: bytes.Repeat(commonOriginData, 10), key: commonKey32},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			box, err := SecretBoxSeal(tt.args.data, tt.args.key)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("SecretBoxSeal() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(box) != len(tt.args.data)+secretBoxOverhead {
+				t.Errorf("SecretBoxSeal() len = %v, want %v", len(box), len(tt.args.data)+secretBoxOverhead)
+			}
+			got, err := SecretBoxOpen(box, tt.args.key)
+			if err != nil || !bytes.Equal(got, tt.args.data) {
+				t.Errorf("SecretBoxOpen() = %v, %v, want %v", got, err, tt.args.data)
+			}
+			box[len(box)-1] ^= 1
+			if _, err = SecretBoxOpen(box, tt.args.key); err == nil {
+				t.Errorf("SecretBoxOpen() tampered box error = nil, want error")
+			}
+			if _, err = SecretBoxOpen(box[:secretBoxOverhead-1], tt.args.key); err == nil {
+				t.Errorf("SecretBoxOpen() short box error = nil, want error")
+			}
+		})
+	}
+}
+
+func TestBoxSeal(t *testing.T) {
+	alicePk, aliceSk, err := BoxGenKey()
+	if err != nil {
+		t.Fatalf("BoxGenKey() error = %v", err)
+	}
+	bobPk, bobSk, _ := BoxGenKey()
+
+	box, err := BoxSeal(commonOriginData, bobPk, aliceSk)
+	if err != nil {
+		t.Fatalf("BoxSeal() error = %v", err)
+	}
+	got, err := BoxOpen(box, alicePk, bobSk)
+	if err != nil || !bytes.Equal(got, commonOriginData) {
+		t.Errorf("BoxOpen() = %v, %v, want %v", got, err, commonOriginData)
+	}
+	// The shared key opens the box as a secretbox
+	shared, _ := BoxSharedKey(alicePk, bobSk)
+	if got, err = SecretBoxOpen(box, shared); err != nil || !bytes.Equal(got, commonOriginData) {
+		t.Errorf("SecretBoxOpen() with shared key = %v, %v, want %v", got, err, commonOriginData)
+	}
+	// A third party can not open it
+	_, eveSk, _ := BoxGenKey()
+	if _, err = BoxOpen(box, alicePk, eveSk); err == nil {
+		t.Errorf("BoxOpen() with other private key error = nil, want error")
+	}
+
+	invalidKeys := []struct {
+		name      string
+		publicKey []byte
+		privKey   []byte
+	}{
+		{"PublicKeyInvalidLen", bobPk[:31], aliceSk},
+		{"PrivateKeyInvalidLen", bobPk, aliceSk[:31]},
+		{"SmallOrderPublicKey", make([]byte, 32), aliceSk},
+	}
+	for _, tt := range invalidKeys {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := BoxSeal(commonOriginData, tt.publicKey, tt.privKey); err == nil {
+				t.Errorf("BoxSeal() error = nil, want error")
+			}
+			if _, err := BoxOpen(box, tt.publicKey, tt.privKey); err == nil {
+				t.Errorf("BoxOpen() error = nil, want error")
+			}
+		})
+	}
+}
@@ -0,0 +1,169 @@
+package crypt
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"io"
+)
+
+// Shamir's secret sharing splits a secret into n shares, any k of them can recover the secret, while k-1 shares
+// reveal nothing about it. Every byte of the secret is the constant term of a random polynomial of degree k-1 over
+// GF(256), the share i holds the values of the polynomials at x = i, and the secret is recovered by the Lagrange
+// interpolation at x = 0. GF(256) uses the AES polynomial x^8+x^4+x^3+x+1, the arithmetic is constant time.
+// A share is encoded as:
+//  version(1 byte, 0x01) || identifier(4 bytes) || threshold k(1 byte) || x(1 byte) || y || CRC-32C(4 bytes)
+// y is as long as the secret, and the CRC-32C is computed over all the bytes before it.
+// The identifier is random for every split, so the shares of different splits can not be mixed. The checksum detects
+// the corrupted shares. If more than k shares are given to ShamirCombine, the extra shares must agree with the
+// polynomials of the first k shares, otherwise the shares are inconsistent.
+
+const (
+	shamirVersion    = 1
+	shamirHeaderSize = 7
+	shamirCRCSize    = 4
+	shamirMaxShares  = 255
+)
+
+// ShamirSplit split secret into n shares, any k of them can recover the secret by ShamirCombine.
+// 2 <= k <= n <= 255, and secret must not be empty
+func ShamirSplit(secret []byte, n, k int) ([][]byte, error) {
+	if len(secret) == 0 {
+		return nil, errors.New(sErrDataEmpty)
+	}
+	if k < 2 || n < k || n > shamirMaxShares {
+		return nil, errors.New(sErrShamirParamInvalid)
+	}
+
+	var id [4]byte
+	if _, err := io.ReadFull(rand.Reader, id[:]); err != nil {
+		return nil, err
+	}
+	shares := make([][]byte, n)
+	for i := range shares {
+		share := make([]byte, shamirHeaderSize+len(secret)+shamirCRCSize)
+		share[0] = shamirVersion
+		copy(share[1:5], id[:])
+		share[5] = byte(k)
+		share[6] = byte(i + 1)
+		shares[i] = share
+	}
+
+	// coefficients[0] is the secret byte, the others are random
+	coefficients := make([]byte, k)
+	for j, b := range secret {
+		coefficients[0] = b
+		if _, err := io.ReadFull(rand.Reader, coefficients[1:]); err != nil {
+			return nil, err
+		}
+		for i := range shares {
+			shares[i][shamirHeaderSize+j] = gf256Eval(coefficients, byte(i+1))
+		}
+	}
+	for j := range coefficients {
+		coefficients[j] = 0
+	}
+
+	for _, share := range shares {
+		binary.BigEndian.PutUint32(share[len(share)-shamirCRCSize:],
+			HashUInt32(share[:len(share)-shamirCRCSize], HtCrc32C))
+	}
+	return shares, nil
+}
+
+// ShamirCombine recover the secret from the shares of ShamirSplit, at least k shares are required. An error is
+// returned if a share is corrupted, the shares come from different splits, or the extra shares are inconsistent
+func ShamirCombine(shares [][]byte) ([]byte, error) {
+	if len(shares) == 0 {
+		return nil, errors.New(sErrShamirNotEnough)
+	}
+
+	first := shares[0]
+	seen := make(map[byte]bool, len(shares))
+	for _, share := range shares {
+		if len(share) <= shamirHeaderSize+shamirCRCSize || share[0] != shamirVersion ||
+			binary.BigEndian.Uint32(share[len(share)-shamirCRCSize:]) !=
+				HashUInt32(share[:len(share)-shamirCRCSize], HtCrc32C) || share[5] < 2 || share[6] == 0 {
+			return nil, errors.New(sErrShamirShareInvalid)
+		}
+		// The same identifier、threshold and length, and the different x
+		if len(share) != len(first) || string(share[1:6]) != string(first[1:6]) || seen[share[6]] {
+			return nil, errors.New(sErrShamirInconsistent)
+		}
+		seen[share[6]] = true
+	}
+	k := int(first[5])
+	if len(shares) < k {
+		return nil, errors.New(sErrShamirNotEnough)
+	}
+
+	size := len(first) - shamirHeaderSize - shamirCRCSize
+	xs := make([]byte, k)
+	for i := range xs {
+		xs[i] = shares[i][6]
+	}
+	ys := make([]byte, k)
+	secret := make([]byte, size)
+	for j := range secret {
+		for i := range ys {
+			ys[i] = shares[i][shamirHeaderSize+j]
+		}
+		secret[j] = gf256Interpolate(xs, ys, 0)
+		for _, extra := range shares[k:] {
+			if gf256Interpolate(xs, ys, extra[6]) != extra[shamirHeaderSize+j] {
+				return nil, errors.New(sErrShamirInconsistent)
+			}
+		}
+	}
+	return secret, nil
+}
+
+// gf256Mul return a * b in GF(256), in constant time
+func gf256Mul(a, b byte) byte {
+	var p byte
+	for i := 0; i < 8; i++ {
+		p ^= a & -(b & 1)
+		// a = a * x mod x^8+x^4+x^3+x+1
+		a = a<<1 ^ 0x1B&-(a>>7)
+		b >>= 1
+	}
+	return p
+}
+
+// gf256Inv return 1/a = a^254 in GF(256), 1/0 is 0
+func gf256Inv(a byte) byte {
+	// 254 = 0b11111110
+	r := byte(1)
+	for i := 0; i < 7; i++ {
+		a = gf256Mul(a, a)
+		r = gf256Mul(r, a)
+	}
+	return r
+}
+
+// gf256Eval return the value of the polynomial at x, coefficients[i] is the coefficient of x^i
+func gf256Eval(coefficients []byte, x byte) byte {
+	var y byte
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		y = gf256Mul(y, x) ^ coefficients[i]
+	}
+	return y
+}
+
+// gf256Interpolate return the value at x of the polynomial through the points (xs[i], ys[i]), the xs must be different
+func gf256Interpolate(xs, ys []byte, x byte) byte {
+	var y byte
+	for i := range xs {
+		// L_i(x) = prod (x - x_j) / (x_i - x_j), the subtraction is XOR in GF(256)
+		num, den := byte(1), byte(1)
+		for j := range xs {
+			if i == j {
+				continue
+			}
+			num = gf256Mul(num, x^xs[j])
+			den = gf256Mul(den, xs[i]^xs[j])
+		}
+		y ^= gf256Mul(ys[i], gf256Mul(num, gf256Inv(den)))
+	}
+	return y
+}
@@ -0,0 +1,112 @@
+package crypt
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+)
+
+func TestGf256(t *testing.T) {
+	// FIPS-197 section 4.2
+	if got := gf256Mul(0x57, 0x83); got != 0xC1 {
+		t.Errorf("gf256Mul(0x57, 0x83) = %#x, want 0xc1", got)
+	}
+	if got := gf256Mul(0x57, 0x13); got != 0xFE {
+		t.Errorf("gf256Mul(0x57, 0x13) = %#x, want 0xfe", got)
+	}
+	if got := gf256Inv(0x53); got != 0xCA {
+		t.Errorf("gf256Inv(0x53) = %#x, want 0xca", got)
+	}
+	for a := 1; a < 256; a++ {
+		if got := gf256Mul(byte(a), gf256Inv(byte(a))); got != 1 {
+			t.Fatalf("gf256Mul(%#x, gf256Inv(%#x)) = %#x, want 1", a, a, got)
+		}
+	}
+}
+
+func TestShamirSplit(t *testing.T) {
+	tests := []struct {
+		name    string
+		secret  []byte
+		n       int
+		k       int
+		wantErr bool
+	}{
+		{name: "2of2", secret: commonKey32, n: 2, k: 2},
+		{name: "3of5", secret: commonKey32, n: 5, k: 3},
+		{name: "5of5", secret: commonOriginData, n: 5, k: 5},
+		{name: "OneByte", secret: []byte{0}, n: 3, k: 2},
+		{name: "SecretEmpty", secret: []byte{}, n: 3, k: 2, wantErr: true},
+		{name: "ThresholdTooSmall", secret: commonKey32, n: 3, k: 1, wantErr: true},
+		{name: "ThresholdGreaterThanN", secret: commonKey32, n: 3, k: 4, wantErr: true},
+		{name: "TooManyShares", secret: commonKey32, n: 256, k: 3, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			shares, err := ShamirSplit(tt.secret, tt.n, tt.k)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ShamirSplit() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if len(shares) != tt.n {
+				t.Fatalf("ShamirSplit() len = %v, want %v", len(shares), tt.n)
+			}
+			// every subset of at least k shares recovers the secret, fewer shares fail
+			for mask := 1; mask < 1<<tt.n; mask++ {
+				var subset [][]byte
+				for i := 0; i < tt.n; i++ {
+					if mask&(1<<i) != 0 {
+						subset = append(subset, shares[i])
+					}
+				}
+				got, err := ShamirCombine(subset)
+				if len(subset) < tt.k {
+					if err == nil {
+						t.Errorf("ShamirCombine() %d shares error = nil, want error", len(subset))
+					}
+					continue
+				}
+				if err != nil || !bytes.Equal(got, tt.secret) {
+					t.Errorf("ShamirCombine() %b = %v, %v, want %v", mask, got, err, tt.secret)
+				}
+			}
+		})
+	}
+}
+
+func TestShamirCombineInvalid(t *testing.T) {
+	shares, _ := ShamirSplit(commonKey32, 5, 3)
+	others, _ := ShamirSplit(commonKey32, 5, 3)
+	clone := func(s []byte) []byte { return append([]byte{}, s...) }
+
+	corrupted := clone(shares[2])
+	corrupted[10] ^= 1
+
+	// a share with a valid checksum but a wrong value, it is detected by the extra share
+	forged := clone(shares[3])
+	forged[10] ^= 1
+	binary.BigEndian.PutUint32(forged[len(forged)-shamirCRCSize:],
+		HashUInt32(forged[:len(forged)-shamirCRCSize], HtCrc32C))
+
+	tests := []struct {
+		name   string
+		shares [][]byte
+	}{
+		{"Empty", nil},
+		{"NotEnough", [][]byte{shares[0], shares[1]}},
+		{"Corrupted", [][]byte{shares[0], shares[1], corrupted}},
+		{"Truncated", [][]byte{shares[0], shares[1], shares[2][:8]}},
+		{"Duplicate", [][]byte{shares[0], shares[1], shares[1]}},
+		{"DifferentSplits", [][]byte{shares[0], shares[1], others[2]}},
+		{"ForgedExtraShare", [][]byte{shares[0], shares[1], shares[2], forged}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := ShamirCombine(tt.shares); err == nil {
+				t.Errorf("ShamirCombine() = %v, want error", got)
+			}
+		})
+	}
+}
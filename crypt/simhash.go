@@ -0,0 +1,57 @@
+package crypt
+
+import (
+	"math/bits"
+)
+
+// SimHash (Charikar, 2002) maps a document to a 64-bit fingerprint so that similar documents have fingerprints with
+// a small Hamming distance: every feature is hashed to 64 bits, for each bit position the weight of the feature is
+// added if the bit is 1, otherwise subtracted, and the bit of the fingerprint is 1 if the sum is positive.
+// For web pages, the documents whose fingerprints differ in at most 3 bits are usually near-duplicates
+// (Manku et al., "Detecting Near-Duplicates for Web Crawling", 2007).
+
+// SimHash return the 64-bit SimHash fingerprint of the text, the tokens are split by tokenizer and weighted by their
+// frequency, then hashed by ht. If tokenizer is nil, WordTokenizer is used
+func SimHash(text string, tokenizer Tokenizer, ht HashType) (uint64, error) {
+	if tokenizer == nil {
+		tokenizer = WordTokenizer
+	}
+	features := make(map[string]float64)
+	for _, token := range tokenizer(text) {
+		features[token]++
+	}
+	return SimHashWeighted(features, ht)
+}
+
+// SimHashWeighted return the 64-bit SimHash fingerprint of the weighted features, such as the TF-IDF of the words
+func SimHashWeighted(features map[string]float64, ht HashType) (uint64, error) {
+	if _, err := hashSum64(nil, ht); err != nil {
+		return 0, err
+	}
+
+	var v [64]float64
+	for feature, weight := range features {
+		h, _ := hashSum64([]byte(feature), ht)
+		h = mix64(h)
+		for i := 0; i < 64; i++ {
+			if h&(1<<uint(i)) != 0 {
+				v[i] += weight
+			} else {
+				v[i] -= weight
+			}
+		}
+	}
+
+	var fingerprint uint64
+	for i := 0; i < 64; i++ {
+		if v[i] > 0 {
+			fingerprint |= 1 << uint(i)
+		}
+	}
+	return fingerprint, nil
+}
+
+// HammingDistance return the number of different bits between a and b
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
@@ -0,0 +1,62 @@
+package crypt
+
+import (
+	"testing"
+)
+
+var simHashDocs = []string{
+	"The quick brown fox jumps over the lazy dog near the bank of the river on a sunny afternoon",
+	"The quick brown fox jumped over the lazy dog near the bank of the river on a sunny afternoon",
+	"Stock markets rallied today as investors welcomed the central bank decision to hold interest rates",
+}
+
+func TestSimHash(t *testing.T) {
+	a, err := SimHash(simHashDocs[0], nil, HtFnvA64)
+	if err != nil {
+		t.Fatalf("SimHash() error = %v", err)
+	}
+	b, _ := SimHash(simHashDocs[1], nil, HtFnvA64)
+	c, _ := SimHash(simHashDocs[2], nil, HtFnvA64)
+	if d := HammingDistance(a, b); d > 10 {
+		t.Errorf("HammingDistance(near-duplicate) = %d", d)
+	}
+	if d := HammingDistance(a, c); d < 20 {
+		t.Errorf("HammingDistance(different) = %d", d)
+	}
+
+	again, _ := SimHash(simHashDocs[0], WordTokenizer, HtFnvA64)
+	if again != a {
+		t.Errorf("SimHash() = %v, want %v", again, a)
+	}
+	if _, err = SimHash(simHashDocs[0], nil, 0); err == nil {
+		t.Errorf("SimHash() expect error for unsupported type")
+	}
+}
+
+func TestSimHashWeighted(t *testing.T) {
+	h, _ := SimHashWeighted(map[string]float64{"only": 1}, HtMD5)
+	md5, _ := hashSum64([]byte("only"), HtMD5)
+	if want := mix64(md5); h != want {
+		t.Errorf("SimHashWeighted() = %x, want %x", h, want)
+	}
+	if h, _ = SimHashWeighted(nil, HtMD5); h != 0 {
+		t.Errorf("SimHashWeighted(nil) = %x, want 0", h)
+	}
+}
+
+func TestHammingDistance(t *testing.T) {
+	tests := []struct {
+		a, b uint64
+		want int
+	}{
+		{0, 0, 0},
+		{0, 0xff, 8},
+		{0xf0f0, 0x0ff0, 8},
+		{0, ^uint64(0), 64},
+	}
+	for _, tt := range tests {
+		if got := HammingDistance(tt.a, tt.b); got != tt.want {
+			t.Errorf("HammingDistance(%x, %x) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
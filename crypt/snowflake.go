@@ -0,0 +1,134 @@
+package crypt
+
+import (
+	"errors"
+	"sync"
+	"time"
+)
+
+// Snowflake is a 63-bit ID of Twitter, the IDs generated by the same worker are increasing:
+//  0(1 bit) || timestamp in milliseconds since the epoch || worker ID || sequence
+// The widths of the worker ID and the sequence are configurable, the timestamp takes the remaining bits. With the
+// default 10 bits worker ID and 12 bits sequence, there are 1024 workers, each worker can generate 4096 IDs per
+// millisecond, and the 41 bits timestamp lasts for 69 years.
+// If the clock goes backwards, NextID waits for the clock to catch up when the rollback is not greater than
+// MaxClockBackward, otherwise an error is returned, so a duplicate ID is never generated.
+
+const (
+	snowflakeTimeBits        = 63
+	snowflakeDefaultWorker   = 10
+	snowflakeDefaultSequence = 12
+)
+
+// SnowflakeDefaultEpoch the default epoch of Snowflake, 2020-01-01 00:00:00 UTC
+var SnowflakeDefaultEpoch = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// SnowflakeConfig the configuration of Snowflake, the zero values are replaced by the defaults
+type SnowflakeConfig struct {
+	Epoch            time.Time     // the start of the timestamp, it must not be in the future, default SnowflakeDefaultEpoch
+	WorkerIDBits     uint8         // the bits of the worker ID, default 10
+	SequenceBits     uint8         // the bits of the sequence, default 12
+	WorkerID         int64         // the ID of this worker, range:[0, 2^WorkerIDBits-1]
+	MaxClockBackward time.Duration // the maximum clock rollback waited by NextID, 0 means an error is returned at once
+}
+
+// SnowflakeParts the parts of a Snowflake ID returned by Decompose
+type SnowflakeParts struct {
+	Time     time.Time
+	WorkerID int64
+	Sequence int64
+}
+
+// Snowflake the generator of the Snowflake IDs, it is safe for concurrent use
+type Snowflake struct {
+	mu           sync.Mutex
+	epoch        int64 // milliseconds
+	workerBits   uint8
+	sequenceBits uint8
+	workerID     int64
+	maxBackward  int64 // milliseconds
+	lastTime     int64
+	sequence     int64
+	now          func() time.Time
+}
+
+// NewSnowflake return a Snowflake generator of the configuration
+func NewSnowflake(cfg SnowflakeConfig) (*Snowflake, error) {
+	if cfg.Epoch.IsZero() {
+		cfg.Epoch = SnowflakeDefaultEpoch
+	}
+	if cfg.WorkerIDBits == 0 {
+		cfg.WorkerIDBits = snowflakeDefaultWorker
+	}
+	if cfg.SequenceBits == 0 {
+		cfg.SequenceBits = snowflakeDefaultSequence
+	}
+	// At least 31 bits are left to the timestamp, which lasts for 24 days
+	if int(cfg.WorkerIDBits)+int(cfg.SequenceBits) > snowflakeTimeBits-31 || cfg.WorkerID < 0 ||
+		cfg.WorkerID >= 1<<cfg.WorkerIDBits || cfg.MaxClockBackward < 0 || cfg.Epoch.After(time.Now()) {
+		return nil, errors.New(sErrSnowflakeConfigInvalid)
+	}
+	return &Snowflake{
+		epoch:        toMillis(cfg.Epoch),
+		workerBits:   cfg.WorkerIDBits,
+		sequenceBits: cfg.SequenceBits,
+		workerID:     cfg.WorkerID,
+		maxBackward:  int64(cfg.MaxClockBackward / time.Millisecond),
+		lastTime:     -1,
+		now:          time.Now,
+	}, nil
+}
+
+// NextID return the next ID. If the sequence of the current millisecond is used up, it waits for the next
+// millisecond. An error is returned if the clock goes backwards more than MaxClockBackward or the timestamp overflows
+func (s *Snowflake) NextID() (int64, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	ts := toMillis(s.now()) - s.epoch
+	if ts < s.lastTime {
+		if s.lastTime-ts > s.maxBackward {
+			return 0, errors.New(sErrSnowflakeClockBackwards)
+		}
+		ts = s.waitUntil(s.lastTime)
+	}
+	if ts == s.lastTime {
+		s.sequence = (s.sequence + 1) & (1<<s.sequenceBits - 1)
+		if s.sequence == 0 {
+			ts = s.waitUntil(s.lastTime + 1)
+		}
+	} else {
+		s.sequence = 0
+	}
+	if ts >= 1<<(snowflakeTimeBits-s.workerBits-s.sequenceBits) {
+		return 0, errors.New(sErrSnowflakeTimeOverflow)
+	}
+	s.lastTime = ts
+	return ts<<(s.workerBits+s.sequenceBits) | s.workerID<<s.sequenceBits | s.sequence, nil
+}
+
+// Decompose return the creation time、the worker ID and the sequence of the ID generated by s
+func (s *Snowflake) Decompose(id int64) SnowflakeParts {
+	ms := id>>(s.workerBits+s.sequenceBits) + s.epoch
+	return SnowflakeParts{
+		Time:     time.Unix(ms/1000, ms%1000*int64(time.Millisecond)),
+		WorkerID: id >> s.sequenceBits & (1<<s.workerBits - 1),
+		Sequence: id & (1<<s.sequenceBits - 1),
+	}
+}
+
+// waitUntil wait until the timestamp is not less than ts, return the timestamp
+func (s *Snowflake) waitUntil(ts int64) int64 {
+	for {
+		now := toMillis(s.now()) - s.epoch
+		if now >= ts {
+			return now
+		}
+		time.Sleep(time.Duration(ts-now) * time.Millisecond)
+	}
+}
+
+// toMillis return the Unix timestamp in milliseconds of t
+func toMillis(t time.Time) int64 {
+	return t.UnixNano() / int64(time.Millisecond)
+}
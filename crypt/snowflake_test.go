@@ -0,0 +1,153 @@
+package crypt
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestNewSnowflake(t *testing.T) {
+	tests := []struct {
+		name    string
+		cfg     SnowflakeConfig
+		wantErr bool
+	}{
+		{name: "Default", cfg: SnowflakeConfig{}},
+		{name: "Custom", cfg: SnowflakeConfig{Epoch: time.Unix(1288834974, 657000000), WorkerIDBits: 5, SequenceBits: 8, WorkerID: 31}},
+		{name: "WorkerIDTooLarge", cfg: SnowflakeConfig{WorkerID: 1024}, wantErr: true},
+		{name: "NegativeWorkerID", cfg: SnowflakeConfig{WorkerID: -1}, wantErr: true},
+		{name: "TooManyBits", cfg: SnowflakeConfig{WorkerIDBits: 20, SequenceBits: 13}, wantErr: true},
+		{name: "FutureEpoch", cfg: SnowflakeConfig{Epoch: time.Now().Add(time.Hour)}, wantErr: true},
+		{name: "NegativeBackward", cfg: SnowflakeConfig{MaxClockBackward: -time.Millisecond}, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewSnowflake(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("NewSnowflake() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSnowflakeNextID(t *testing.T) {
+	s, _ := NewSnowflake(SnowflakeConfig{WorkerID: 5})
+	start := time.Now().Add(-time.Millisecond)
+
+	var mu sync.Mutex
+	seen := make(map[int64]bool)
+	var wg sync.WaitGroup
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prev := int64(-1)
+			for j := 0; j < 5000; j++ {
+				id, err := s.NextID()
+				if err != nil || id <= prev {
+					t.Errorf("NextID() = %v after %v, error = %v", id, prev, err)
+					return
+				}
+				prev = id
+				mu.Lock()
+				if seen[id] {
+					t.Errorf("NextID() = %v is duplicated", id)
+				}
+				seen[id] = true
+				mu.Unlock()
+			}
+		}()
+	}
+	wg.Wait()
+
+	id, _ := s.NextID()
+	parts := s.Decompose(id)
+	if parts.WorkerID != 5 || parts.Time.Before(start) || parts.Time.After(time.Now().Add(time.Second)) {
+		t.Errorf("Decompose() = %+v", parts)
+	}
+}
+
+func TestSnowflakeDecompose(t *testing.T) {
+	epoch := time.Unix(1288834974, 657000000)
+	s, _ := NewSnowflake(SnowflakeConfig{Epoch: epoch, WorkerIDBits: 10, SequenceBits: 12})
+	// 1000 ms since the epoch, worker 3, sequence 7
+	parts := s.Decompose(1000<<22 | 3<<12 | 7)
+	if !parts.Time.Equal(epoch.Add(time.Second)) || parts.WorkerID != 3 || parts.Sequence != 7 {
+		t.Errorf("Decompose() = %+v", parts)
+	}
+}
+
+// fakeClock return the times in order, the last one is repeated
+type fakeClock struct {
+	times []time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	t := c.times[0]
+	if len(c.times) > 1 {
+		c.times = c.times[1:]
+	}
+	return t
+}
+
+func TestSnowflakeSequence(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+	t0 := epoch.Add(time.Minute)
+	s, _ := NewSnowflake(SnowflakeConfig{Epoch: epoch, SequenceBits: 1})
+	clock := &fakeClock{times: []time.Time{t0, t0, t0, t0.Add(time.Millisecond)}}
+	s.now = clock.now
+
+	want := []int64{0, 1, 0}
+	for i, seq := range want {
+		id, err := s.NextID()
+		if err != nil {
+			t.Fatalf("NextID() error = %v", err)
+		}
+		parts := s.Decompose(id)
+		if parts.Sequence != seq {
+			t.Errorf("the sequence of ID %d = %v, want %v", i, parts.Sequence, seq)
+		}
+		// The third ID waits for the next millisecond
+		if wantTime := t0.Add(time.Duration(i/2) * time.Millisecond).Truncate(time.Millisecond); !parts.Time.Equal(wantTime) {
+			t.Errorf("the time of ID %d = %v, want %v", i, parts.Time, wantTime)
+		}
+	}
+}
+
+func TestSnowflakeClockBackward(t *testing.T) {
+	epoch := time.Now().Add(-time.Hour)
+	t0 := epoch.Add(time.Minute)
+
+	s, _ := NewSnowflake(SnowflakeConfig{Epoch: epoch})
+	s.now = (&fakeClock{times: []time.Time{t0, t0.Add(-5 * time.Millisecond)}}).now
+	if _, err := s.NextID(); err != nil {
+		t.Fatalf("NextID() error = %v", err)
+	}
+	if _, err := s.NextID(); err == nil {
+		t.Errorf("NextID() should return an error when the clock goes backwards")
+	}
+
+	s, _ = NewSnowflake(SnowflakeConfig{Epoch: epoch, MaxClockBackward: 10 * time.Millisecond})
+	s.now = (&fakeClock{times: []time.Time{t0, t0.Add(-5 * time.Millisecond), t0}}).now
+	id1, _ := s.NextID()
+	id2, err := s.NextID()
+	if err != nil || id2 <= id1 {
+		t.Errorf("NextID() = %v after %v, error = %v", id2, id1, err)
+	}
+
+	s, _ = NewSnowflake(SnowflakeConfig{Epoch: epoch, MaxClockBackward: 10 * time.Millisecond})
+	s.now = (&fakeClock{times: []time.Time{t0, t0.Add(-20 * time.Millisecond)}}).now
+	s.NextID()
+	if _, err := s.NextID(); err == nil {
+		t.Errorf("NextID() should return an error when the clock goes backwards more than MaxClockBackward")
+	}
+}
+
+func TestSnowflakeTimeOverflow(t *testing.T) {
+	// 31 bits timestamp lasts for about 24.8 days
+	epoch := time.Now().Add(-30 * 24 * time.Hour)
+	s, _ := NewSnowflake(SnowflakeConfig{Epoch: epoch, WorkerIDBits: 20, SequenceBits: 12})
+	if _, err := s.NextID(); err == nil {
+		t.Errorf("NextID() should return an error when the timestamp overflows")
+	}
+}
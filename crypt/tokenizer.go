@@ -0,0 +1,67 @@
+package crypt
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Tokenizer split the text into tokens(features), used by SimHash and MinHasher
+type Tokenizer func(text string) []string
+
+// WordTokenizer split the text into lowercase words, the characters other than letters and numbers are separators.
+// It is suitable for the languages separated by spaces, use NewShingleTokenizer for Chinese、Japanese, etc.
+func WordTokenizer(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// NewShingleTokenizer return a Tokenizer which split the lowercase text into the character k-grams(shingles),
+// the consecutive spaces are treated as one space. If k is less than or equal to 0, the default value of 3 is used.
+// The text shorter than k is returned as one token
+// example:
+//  NewShingleTokenizer(3)("Hello  World") = ["hel", "ell", "llo", "lo ", "o w", " wo", "wor", "orl", "rld"]
+func NewShingleTokenizer(k int) Tokenizer {
+	if k <= 0 {
+		k = 3
+	}
+	return func(text string) []string {
+		runes := []rune(strings.Join(strings.Fields(strings.ToLower(text)), " "))
+		if len(runes) == 0 {
+			return nil
+		}
+		if len(runes) <= k {
+			return []string{string(runes)}
+		}
+		tokens := make([]string, 0, len(runes)-k+1)
+		for i := 0; i+k <= len(runes); i++ {
+			tokens = append(tokens, string(runes[i:i+k]))
+		}
+		return tokens
+	}
+}
+
+// NewWordShingleTokenizer return a Tokenizer which split the text into the word k-grams by WordTokenizer, the words
+// in a k-gram are joined by a space. If k is less than or equal to 0, the default value of 2 is used.
+// The text with fewer than k words is returned as one token
+// example:
+//  NewWordShingleTokenizer(2)("The quick brown fox") = ["the quick", "quick brown", "brown fox"]
+func NewWordShingleTokenizer(k int) Tokenizer {
+	if k <= 0 {
+		k = 2
+	}
+	return func(text string) []string {
+		words := WordTokenizer(text)
+		if len(words) == 0 {
+			return nil
+		}
+		if len(words) <= k {
+			return []string{strings.Join(words, " ")}
+		}
+		tokens := make([]string, 0, len(words)-k+1)
+		for i := 0; i+k <= len(words); i++ {
+			tokens = append(tokens, strings.Join(words[i:i+k], " "))
+		}
+		return tokens
+	}
+}
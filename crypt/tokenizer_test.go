@@ -0,0 +1,68 @@
+package crypt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestWordTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"Empty", "", []string{}},
+		{"Words", "Hello, World! It's 2021.", []string{"hello", "world", "it", "s", "2021"}},
+		{"Chinese", "你好 世界", []string{"你好", "世界"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := WordTokenizer(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("WordTokenizer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewShingleTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		k    int
+		text string
+		want []string
+	}{
+		{"Empty", 3, "  ", nil},
+		{"Short", 3, "Hi", []string{"hi"}},
+		{"Spaces", 3, "Hello  World", []string{"hel", "ell", "llo", "lo ", "o w", " wo", "wor", "orl", "rld"}},
+		{"Chinese", 2, "你好世界", []string{"你好", "好世", "世界"}},
+		{"Default", 0, "abcd", []string{"abc", "bcd"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewShingleTokenizer(tt.k)(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewShingleTokenizer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewWordShingleTokenizer(t *testing.T) {
+	tests := []struct {
+		name string
+		k    int
+		text string
+		want []string
+	}{
+		{"Empty", 2, "", nil},
+		{"Short", 3, "quick fox", []string{"quick fox"}},
+		{"Words", 2, "The quick brown fox", []string{"the quick", "quick brown", "brown fox"}},
+		{"Default", 0, "a b c", []string{"a b", "b c"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewWordShingleTokenizer(tt.k)(tt.text); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("NewWordShingleTokenizer() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
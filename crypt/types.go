@@ -2,18 +2,66 @@ package crypt
 
 // error string
 const (
-	sErrDataInvalid    = "data is invalid"
-	sErrDataEmpty      = "data is empty"
-	sErrDataLenInvalid = "data padding len is invalid"
-	sErrBlockNotFull   = "input not full blocks"
-	sErrAesModeInvalid = "aes work mode invalid"
-	sErrPublicKeyErr   = "public key error"
-	sErrPrivateKeyErr  = "private key error"
+	sErrDataInvalid             = "data is invalid"
+	sErrDataEmpty               = "data is empty"
+	sErrDataLenInvalid          = "data padding len is invalid"
+	sErrBlockNotFull            = "input not full blocks"
+	sErrAesModeInvalid          = "aes work mode invalid"
+	sErrPublicKeyErr            = "public key error"
+	sErrPrivateKeyErr           = "private key error"
+	sErrCrcWidthInvalid         = "crc width invalid"
+	sErrHashTypeInvalid         = "hash type not supported"
+	sErrHashParamInvalid        = "hash type, name or constructor invalid"
+	sErrHashRegistered          = "hash type or name already registered"
+	sErrMaglevSizeInvalid       = "maglev table size must be a prime not greater than 1<<24"
+	sErrBloomNotCompatible      = "bloom filters are not compatible"
+	sErrHllPrecisionInvalid     = "hyperloglog precision must be in [4,18]"
+	sErrHllNotCompatible        = "hyperloglog sketches are not compatible"
+	sErrMinHashParamInvalid     = "minhash parameter or signature length invalid"
+	sErrMerkleIndexInvalid      = "merkle leaf index or tree size invalid"
+	sErrKeySizeInvalid          = "key size invalid"
+	sErrAuthFailed              = "message authentication failed"
+	sErrKeyUnwrapFailed         = "key unwrap integrity check failed"
+	sErrFpeAlphabetInvalid      = "fpe alphabet must have 2 to 65536 unique characters"
+	sErrFpeTweakInvalid         = "fpe tweak length invalid"
+	sErrFpeTextInvalid          = "fpe text length or character invalid"
+	sErrKeyringKeyExists        = "keyring key id already exists"
+	sErrKeyringKeyNotFound      = "keyring key id not found"
+	sErrKeyringNoPrimary        = "keyring has no primary key"
+	sErrKeyringPrimaryRemove    = "keyring primary key can not be removed"
+	sErrShamirParamInvalid      = "shamir threshold or number of shares invalid"
+	sErrShamirShareInvalid      = "shamir share invalid or corrupted"
+	sErrShamirInconsistent      = "shamir shares are inconsistent"
+	sErrShamirNotEnough         = "not enough shamir shares"
+	sErrRandTypeInvalid         = "rand type is invalid"
+	sErrUUIDInvalid             = "uuid is invalid"
+	sErrUUIDScanType            = "unsupported type to scan into uuid"
+	sErrULIDInvalid             = "ulid is invalid"
+	sErrULIDOverflow            = "ulid entropy overflows in the same millisecond"
+	sErrKSUIDInvalid            = "ksuid is invalid"
+	sErrSnowflakeConfigInvalid  = "snowflake config is invalid"
+	sErrSnowflakeClockBackwards = "clock moved backwards"
+	sErrSnowflakeTimeOverflow   = "snowflake timestamp overflows"
+	sErrNanoIDAlphabetInvalid   = "nanoid alphabet is invalid"
+	sErrNanoIDSizeInvalid       = "nanoid size is invalid"
+	sErrPasswordPolicyInvalid   = "password policy is invalid"
+	sErrWeightsInvalid          = "weights are invalid"
+	sErrBase32Invalid           = "invalid base32 data"
+	sErrBase32CheckMismatch     = "base32 check symbol mismatch"
+	sErrBase58Invalid           = "invalid base58 data"
+	sErrBase58Checksum          = "base58check checksum mismatch"
+	sErrBase62Invalid           = "invalid base62 data"
+	sErrZ85Invalid              = "invalid z85 data"
+	sErrBase64ModeInvalid       = "invalid base64 mode"
+	sErrKeyringModeInvalid      = "keyring mode invalid"
+	sErrAscii85Invalid          = "invalid ascii85 data"
 )
 
 // -------------------------------------------------------------------------------------
 
 // HashType Hash algorithm type. use in hash.go
+// More hash algorithms can be registered by RegisterHash, the custom HashType must be greater than HtUser,
+// such as: HtUser + 1
 type HashType int
 
 const (
@@ -34,6 +82,12 @@ const (
 	HtCrc32
 	HtCrc64ISO
 	HtCrc64ECMA
+	HtCrc32C      // CRC-32C(Castagnoli)
+	HtCrc16Modbus // CRC-16/MODBUS
+	HtCrc16CCITT  // CRC-16/CCITT-FALSE
+	HtCrc16XModem // CRC-16/XMODEM
+
+	HtUser HashType = 1000 // the custom HashType registered by RegisterHash must be greater than HtUser
 )
 
 // -------------------------------------------------------------------------------------
@@ -76,4 +130,49 @@ const (
 	AesModeECB
 	AesModeCTR
 	AesModeOFB
+	AesModeSIV // deterministic authenticated encryption, see AESSIVEncrypt
+)
+
+// -------------------------------------------------------------------------------------
+
+// RandType the algorithm of the seeded generator Rand. use in rand.go
+type RandType int
+
+const (
+	RtXoshiro256 RandType = iota + 1 // xoshiro256**, 256 bits state, it is the default
+	RtPCG                            // PCG-DXSM 128/64, 128 bits state
+)
+
+// -------------------------------------------------------------------------------------
+
+// PasswordMode the mode of GeneratePassword. use in password.go
+type PasswordMode int
+
+const (
+	PasswordModeRandom        PasswordMode = iota // random characters of the character classes, it is the default
+	PasswordModePronounceable                     // alternate consonants and vowels, easy to read and type
+	PasswordModePassphrase                        // random words of a word list, such as: correct-horse-battery-staple
+)
+
+// -------------------------------------------------------------------------------------
+
+// Base64Mode the variant of the base64 streams. use in base64.go
+type Base64Mode int
+
+const (
+	Base64ModeStd    Base64Mode = iota // the standard base64 with the padding, it is the default
+	Base64ModeUrl                      // the url-safe base64 with the padding
+	Base64ModeRawStd                   // the standard base64 without the padding
+	Base64ModeRawUrl                   // the url-safe base64 without the padding
+	Base64ModeMime                     // the standard base64 with the padding, broken into the lines of 76 characters
+)
+
+// -------------------------------------------------------------------------------------
+
+// KeyringMode the authenticated encryption of Keyring. use in keyring.go
+type KeyringMode int
+
+const (
+	KeyringModeGCM KeyringMode = iota + 1 // AES-GCM with a random nonce, the key is 16, 24 or 32 bytes
+	KeyringModeSIV                        // deterministic AES-SIV, the key is 32, 48 or 64 bytes, see AESSIVEncrypt
 )
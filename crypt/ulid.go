@@ -0,0 +1,191 @@
+package crypt
+
+import (
+	crand "crypto/rand"
+	"errors"
+	"io"
+	"sync"
+	"time"
+)
+
+// ULID(Universally Unique Lexicographically Sortable Identifier) is a 48-bit Unix timestamp in milliseconds followed
+// by 80 random bits, it is encoded as 26 characters of the Crockford's Base32, such as: 01ARZ3NDEKTSV4RRFFQ69G5FAV.
+// The strings are sorted by the creation time. In the monotonic mode, the ULIDs generated in the same millisecond
+// increase the random bits of the last ULID by 1 instead of reading new random bits, so they are strictly increasing.
+
+// ULID a 128-bit lexicographically sortable identifier
+type ULID [16]byte
+
+const (
+	ulidStrLen = 26
+	// crockfordAlphabet the alphabet of the Crockford's Base32, without I、L、O and U
+	crockfordAlphabet = "0123456789ABCDEFGHJKMNPQRSTVWXYZ"
+)
+
+// crockfordDecodeMap map the characters to the values of the Crockford's Base32, 0xFF is invalid
+var crockfordDecodeMap = func() [256]byte {
+	var m [256]byte
+	for i := range m {
+		m[i] = 0xFF
+	}
+	for i := 0; i < len(crockfordAlphabet); i++ {
+		c := crockfordAlphabet[i]
+		m[c] = byte(i)
+		if c >= 'A' && c <= 'Z' {
+			m[c+'a'-'A'] = byte(i)
+		}
+	}
+	return m
+}()
+
+// ULIDGenerator generate the ULIDs, it is safe for concurrent use
+type ULIDGenerator struct {
+	mu        sync.Mutex
+	monotonic bool
+	last      ULID
+}
+
+// NewULIDGenerator return a ULID generator, the ULIDs are strictly increasing in the monotonic mode even if they are
+// generated in the same millisecond or the clock goes backwards
+func NewULIDGenerator(monotonic bool) *ULIDGenerator {
+	return &ULIDGenerator{monotonic: monotonic}
+}
+
+// defaultULIDGenerator the monotonic generator of NewULID
+var defaultULIDGenerator = NewULIDGenerator(true)
+
+// NewULID return a ULID of the current time in the monotonic mode
+func NewULID() (ULID, error) {
+	return defaultULIDGenerator.New()
+}
+
+// New return a ULID of the current time. In the monotonic mode, an error is returned if more than 2^80 ULIDs are
+// generated in the same millisecond
+func (g *ULIDGenerator) New() (ULID, error) {
+	return g.newULID(uint64(time.Now().UnixNano() / int64(time.Millisecond)))
+}
+
+// newULID return a ULID of the timestamp ms
+func (g *ULIDGenerator) newULID(ms uint64) (ULID, error) {
+	var u ULID
+	if !g.monotonic {
+		if _, err := io.ReadFull(crand.Reader, u[6:]); err != nil {
+			return ULID{}, err
+		}
+		u.setTimestamp(ms)
+		return u, nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if last := g.last.Timestamp(); ms <= last && !g.last.isZero() {
+		// The same millisecond or the clock goes backwards, increase the random bits of the last ULID
+		u = g.last
+		i := len(u) - 1
+		for ; i >= 6; i-- {
+			if u[i]++; u[i] != 0 {
+				break
+			}
+		}
+		if i < 6 {
+			return ULID{}, errors.New(sErrULIDOverflow)
+		}
+	} else {
+		if _, err := io.ReadFull(crand.Reader, u[6:]); err != nil {
+			return ULID{}, err
+		}
+		u.setTimestamp(ms)
+	}
+	g.last = u
+	return u, nil
+}
+
+// ParseULID parse the string of a ULID, the characters are case-insensitive
+func ParseULID(s string) (ULID, error) {
+	var u ULID
+	// 26 characters are 130 bits, the first character must be less than 8
+	if len(s) != ulidStrLen || crockfordDecodeMap[s[0]] > 7 {
+		return u, errors.New(sErrULIDInvalid)
+	}
+	var acc uint
+	var bitCnt, j int
+	for i := 0; i < len(s); i++ {
+		v := crockfordDecodeMap[s[i]]
+		if v == 0xFF {
+			return ULID{}, errors.New(sErrULIDInvalid)
+		}
+		acc = acc<<5 | uint(v)
+		if bitCnt += 5; i > 0 && bitCnt >= 8 {
+			bitCnt -= 8
+			u[j] = byte(acc >> uint(bitCnt))
+			j++
+		} else if i == 0 {
+			// Skip the 2 padding bits at the start
+			bitCnt -= 2
+		}
+	}
+	return u, nil
+}
+
+// String return the 26 characters of u
+func (u ULID) String() string {
+	var buf [ulidStrLen]byte
+	u.encode(buf[:])
+	return string(buf[:])
+}
+
+// Timestamp return the Unix timestamp in milliseconds of u
+func (u ULID) Timestamp() uint64 {
+	return uint64(u[0])<<40 | uint64(u[1])<<32 | uint64(u[2])<<24 | uint64(u[3])<<16 | uint64(u[4])<<8 | uint64(u[5])
+}
+
+// Time return the creation time of u
+func (u ULID) Time() time.Time {
+	ms := int64(u.Timestamp())
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond))
+}
+
+// Entropy return the 80 random bits of u
+func (u ULID) Entropy() []byte {
+	return append([]byte{}, u[6:]...)
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (u ULID) MarshalText() ([]byte, error) {
+	buf := make([]byte, ulidStrLen)
+	u.encode(buf)
+	return buf, nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (u *ULID) UnmarshalText(text []byte) error {
+	v, err := ParseULID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// setTimestamp set the timestamp of u to ms
+func (u *ULID) setTimestamp(ms uint64) {
+	u[0], u[1], u[2], u[3], u[4], u[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+}
+
+// isZero return true if all bits of u are zero
+func (u ULID) isZero() bool {
+	return u == ULID{}
+}
+
+// encode write the 26 characters of u to dst, the 128 bits are left padded with 2 zero bits
+func (u ULID) encode(dst []byte) {
+	var acc uint
+	bitCnt, j := 2, 0
+	for _, b := range u {
+		acc = acc<<8 | uint(b)
+		for bitCnt += 8; bitCnt >= 5; j++ {
+			bitCnt -= 5
+			dst[j] = crockfordAlphabet[acc>>uint(bitCnt)&0x1F]
+		}
+	}
+}
@@ -0,0 +1,117 @@
+package crypt
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestParseULID(t *testing.T) {
+	tests := []struct {
+		name    string
+		s       string
+		want    string
+		wantErr bool
+	}{
+		{name: "Spec", s: "01ARZ3NDEKTSV4RRFFQ69G5FAV", want: "01563e3ab5d3d6764c61efb99302bd5b"},
+		{name: "LowerCase", s: "01arz3ndektsv4rrffq69g5fav", want: "01563e3ab5d3d6764c61efb99302bd5b"},
+		{name: "Max", s: "7ZZZZZZZZZZZZZZZZZZZZZZZZZ", want: "ffffffffffffffffffffffffffffffff"},
+		{name: "Zero", s: "00000000000000000000000000", want: "00000000000000000000000000000000"},
+		{name: "Overflow", s: "80000000000000000000000000", wantErr: true},
+		{name: "InvalidChar", s: "01ARZ3NDEKTSV4RRFFQ69G5FAU", wantErr: true},
+		{name: "Short", s: "01ARZ3NDEKTSV4RRFFQ69G5FA", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseULID(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseULID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if hex.EncodeToString(got[:]) != tt.want {
+				t.Errorf("ParseULID() = %x, want %v", got, tt.want)
+			}
+			if s := got.String(); s != tt.s && s != "01ARZ3NDEKTSV4RRFFQ69G5FAV" {
+				t.Errorf("String() = %v, want %v", s, tt.s)
+			}
+		})
+	}
+
+	u, _ := ParseULID("01ARZ3NDEKTSV4RRFFQ69G5FAV")
+	if u.Timestamp() != 1469922850259 || !u.Time().Equal(time.Unix(1469922850, 259000000)) {
+		t.Errorf("Timestamp() = %v, Time() = %v", u.Timestamp(), u.Time())
+	}
+	if hex.EncodeToString(u.Entropy()) != "d6764c61efb99302bd5b" {
+		t.Errorf("Entropy() = %x", u.Entropy())
+	}
+}
+
+func TestNewULID(t *testing.T) {
+	start := time.Now().Add(-time.Millisecond)
+	prev, _ := NewULID()
+	for i := 0; i < 10000; i++ {
+		u, err := NewULID()
+		if err != nil {
+			t.Fatalf("NewULID() error = %v", err)
+		}
+		if u.String() <= prev.String() {
+			t.Fatalf("NewULID() = %v is not greater than %v", u, prev)
+		}
+		prev = u
+	}
+	if ts := prev.Time(); ts.Before(start) || ts.After(time.Now().Add(time.Second)) {
+		t.Errorf("Time() = %v", ts)
+	}
+
+	g := NewULIDGenerator(false)
+	u1, _ := g.New()
+	u2, _ := g.New()
+	if u1 == u2 {
+		t.Errorf("New() of the non-monotonic generator returns the same ULID %v", u1)
+	}
+}
+
+func TestULIDMonotonic(t *testing.T) {
+	g := NewULIDGenerator(true)
+	u1, _ := g.newULID(1000)
+	u2, _ := g.newULID(1000)
+	// The clock goes backwards
+	u3, _ := g.newULID(999)
+	if u2.Timestamp() != 1000 || u3.Timestamp() != 1000 || u1.String() >= u2.String() || u2.String() >= u3.String() {
+		t.Errorf("newULID() = %v, %v, %v", u1, u2, u3)
+	}
+	u4, _ := g.newULID(1001)
+	if u4.Timestamp() != 1001 {
+		t.Errorf("Timestamp() = %v, want 1001", u4.Timestamp())
+	}
+
+	// The random bits are all 1 in the same millisecond
+	for i := 6; i < len(g.last); i++ {
+		g.last[i] = 0xFF
+	}
+	if _, err := g.newULID(1001); err == nil {
+		t.Errorf("newULID() should return an error when the entropy overflows")
+	}
+	g.last[len(g.last)-1] = 0xFE
+	if u, err := g.newULID(1001); err != nil || u.Entropy()[9] != 0xFF {
+		t.Errorf("newULID() = %v, error = %v", u, err)
+	}
+}
+
+func TestULIDMarshal(t *testing.T) {
+	u, _ := NewULID()
+	data, err := json.Marshal(map[string]ULID{"id": u})
+	if err != nil || string(data) != `{"id":"`+u.String()+`"}` {
+		t.Errorf("json.Marshal() = %s, error = %v", data, err)
+	}
+	var got map[string]ULID
+	if err := json.Unmarshal(data, &got); err != nil || got["id"] != u {
+		t.Errorf("json.Unmarshal() = %v, error = %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"invalid"}`), &got); err == nil {
+		t.Errorf("json.Unmarshal() with invalid ulid should return an error")
+	}
+}
@@ -0,0 +1,249 @@
+package crypt
+
+import (
+	crand "crypto/rand"
+	"database/sql/driver"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+)
+
+// UUID of RFC 9562(RFC 4122), there are three versions:
+//  v4: 122 random bits read from crypto/rand
+//  v5: the SHA-1 of a namespace and a name, the same namespace and name always generate the same UUID
+//  v7: a 48-bit Unix timestamp in milliseconds followed by a 12-bit counter and 62 random bits, so the UUIDs are
+//      ordered by the creation time, they are suitable for the database keys
+// ParseUUID accepts the canonical form xxxxxxxx-xxxx-xxxx-xxxx-xxxxxxxxxxxx, the braced form {...} and the URN
+// form urn:uuid:..., the hex digits are case-insensitive. UUID implements encoding.TextMarshaler(so it is a JSON
+// string)、encoding.BinaryMarshaler、sql.Scanner and driver.Valuer.
+
+// UUID a 128-bit universally unique identifier
+type UUID [16]byte
+
+const (
+	uuidStrLen    = 36
+	uuidURNPrefix = "urn:uuid:"
+)
+
+var (
+	// UUIDNil the nil UUID, all bits are zero
+	UUIDNil UUID
+	// UUIDNamespaceDNS the namespace of the fully qualified domain names for NewUUIDv5
+	UUIDNamespaceDNS = UUID{0x6b, 0xa7, 0xb8, 0x10, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	// UUIDNamespaceURL the namespace of the URLs for NewUUIDv5
+	UUIDNamespaceURL = UUID{0x6b, 0xa7, 0xb8, 0x11, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	// UUIDNamespaceOID the namespace of the ISO OIDs for NewUUIDv5
+	UUIDNamespaceOID = UUID{0x6b, 0xa7, 0xb8, 0x12, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+	// UUIDNamespaceX500 the namespace of the X.500 DNs for NewUUIDv5
+	UUIDNamespaceX500 = UUID{0x6b, 0xa7, 0xb8, 0x14, 0x9d, 0xad, 0x11, 0xd1, 0x80, 0xb4, 0x00, 0xc0, 0x4f, 0xd4, 0x30, 0xc8}
+)
+
+// NewUUIDv4 return a random UUID of version 4
+func NewUUIDv4() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(crand.Reader, u[:]); err != nil {
+		return UUIDNil, err
+	}
+	u.setVersion(4)
+	return u, nil
+}
+
+// NewUUIDv5 return the UUID of version 5 of the name in the namespace, such as: NewUUIDv5(UUIDNamespaceDNS, name)
+func NewUUIDv5(namespace UUID, name []byte) UUID {
+	data := make([]byte, 0, len(namespace)+len(name))
+	data = append(append(data, namespace[:]...), name...)
+	var u UUID
+	copy(u[:], HashBytes(data, HtSha1))
+	u.setVersion(5)
+	return u
+}
+
+// uuidV7State the timestamp and the counter of the last UUID of version 7, they keep the UUIDs generated by this
+// process strictly increasing
+var uuidV7State struct {
+	sync.Mutex
+	ms      uint64
+	counter uint16
+}
+
+// NewUUIDv7 return a time-ordered UUID of version 7. The UUIDs generated in the same millisecond are ordered by a
+// 12-bit counter, if the counter overflows, the timestamp is advanced by 1ms, so the UUIDs are strictly increasing
+// even if the clock goes backwards
+func NewUUIDv7() (UUID, error) {
+	var u UUID
+	if _, err := io.ReadFull(crand.Reader, u[6:]); err != nil {
+		return UUIDNil, err
+	}
+
+	ms := uint64(time.Now().UnixNano() / int64(time.Millisecond))
+	uuidV7State.Lock()
+	if ms > uuidV7State.ms {
+		// A new millisecond, the counter starts from a random value in [0, 2^11), half of the space is left for the
+		// UUIDs in the same millisecond
+		uuidV7State.ms, uuidV7State.counter = ms, binary.BigEndian.Uint16(u[6:8])&0x07FF
+	} else if uuidV7State.counter++; uuidV7State.counter > 0x0FFF {
+		uuidV7State.ms++
+		uuidV7State.counter = 0
+	}
+	ms, counter := uuidV7State.ms, uuidV7State.counter
+	uuidV7State.Unlock()
+
+	u[0], u[1], u[2], u[3], u[4], u[5] = byte(ms>>40), byte(ms>>32), byte(ms>>24), byte(ms>>16), byte(ms>>8), byte(ms)
+	binary.BigEndian.PutUint16(u[6:8], counter)
+	u.setVersion(7)
+	return u, nil
+}
+
+// ParseUUID parse the canonical、braced or URN form of a UUID
+func ParseUUID(s string) (UUID, error) {
+	switch {
+	case len(s) == uuidStrLen+2 && s[0] == '{' && s[len(s)-1] == '}':
+		s = s[1 : len(s)-1]
+	case len(s) == uuidStrLen+len(uuidURNPrefix) && strings.EqualFold(s[:len(uuidURNPrefix)], uuidURNPrefix):
+		s = s[len(uuidURNPrefix):]
+	}
+	if len(s) != uuidStrLen || s[8] != '-' || s[13] != '-' || s[18] != '-' || s[23] != '-' {
+		return UUIDNil, errors.New(sErrUUIDInvalid)
+	}
+
+	var u UUID
+	src := []byte(s[0:8] + s[9:13] + s[14:18] + s[19:23] + s[24:])
+	if _, err := hex.Decode(u[:], src); err != nil {
+		return UUIDNil, errors.New(sErrUUIDInvalid)
+	}
+	return u, nil
+}
+
+// MustParseUUID is like ParseUUID but panics if s is invalid, it is used to initialize the global variables
+func MustParseUUID(s string) UUID {
+	u, err := ParseUUID(s)
+	if err != nil {
+		panic("crypt: ParseUUID(" + s + "): " + err.Error())
+	}
+	return u
+}
+
+// IsValidUUID return true if s is a UUID in the canonical、braced or URN form
+func IsValidUUID(s string) bool {
+	_, err := ParseUUID(s)
+	return err == nil
+}
+
+// String return the canonical form of u, such as: 6ba7b810-9dad-11d1-80b4-00c04fd430c8
+func (u UUID) String() string {
+	var buf [uuidStrLen]byte
+	u.encode(buf[:])
+	return string(buf[:])
+}
+
+// URN return the URN form of u, such as: urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8
+func (u UUID) URN() string {
+	var buf [len(uuidURNPrefix) + uuidStrLen]byte
+	copy(buf[:], uuidURNPrefix)
+	u.encode(buf[len(uuidURNPrefix):])
+	return string(buf[:])
+}
+
+// Version return the version of u, such as 4、5 and 7
+func (u UUID) Version() int {
+	return int(u[6] >> 4)
+}
+
+// IsNil return true if u is the nil UUID
+func (u UUID) IsNil() bool {
+	return u == UUIDNil
+}
+
+// Time return the creation time of the UUID of version 7, false if u is not a UUID of version 7
+func (u UUID) Time() (time.Time, bool) {
+	if u.Version() != 7 {
+		return time.Time{}, false
+	}
+	ms := int64(u[0])<<40 | int64(u[1])<<32 | int64(u[2])<<24 | int64(u[3])<<16 | int64(u[4])<<8 | int64(u[5])
+	return time.Unix(ms/1000, ms%1000*int64(time.Millisecond)), true
+}
+
+// MarshalText implement encoding.TextMarshaler
+func (u UUID) MarshalText() ([]byte, error) {
+	buf := make([]byte, uuidStrLen)
+	u.encode(buf)
+	return buf, nil
+}
+
+// UnmarshalText implement encoding.TextUnmarshaler
+func (u *UUID) UnmarshalText(text []byte) error {
+	v, err := ParseUUID(string(text))
+	if err != nil {
+		return err
+	}
+	*u = v
+	return nil
+}
+
+// MarshalBinary implement encoding.BinaryMarshaler
+func (u UUID) MarshalBinary() ([]byte, error) {
+	return append([]byte{}, u[:]...), nil
+}
+
+// UnmarshalBinary implement encoding.BinaryUnmarshaler, data must be 16 bytes
+func (u *UUID) UnmarshalBinary(data []byte) error {
+	if len(data) != len(u) {
+		return errors.New(sErrUUIDInvalid)
+	}
+	copy(u[:], data)
+	return nil
+}
+
+// Value implement driver.Valuer, the UUID is stored as the canonical string
+func (u UUID) Value() (driver.Value, error) {
+	return u.String(), nil
+}
+
+// Scan implement sql.Scanner, src can be a string、the text or 16 raw bytes, NULL is scanned as the nil UUID
+func (u *UUID) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case nil:
+		*u = UUIDNil
+		return nil
+	case string:
+		if v == "" {
+			*u = UUIDNil
+			return nil
+		}
+		return u.UnmarshalText([]byte(v))
+	case []byte:
+		if len(v) == 0 {
+			*u = UUIDNil
+			return nil
+		}
+		if len(v) == len(u) {
+			return u.UnmarshalBinary(v)
+		}
+		return u.UnmarshalText(v)
+	default:
+		return errors.New(sErrUUIDScanType)
+	}
+}
+
+// setVersion set the version and the variant 10 of RFC 9562
+func (u *UUID) setVersion(version byte) {
+	u[6] = u[6]&0x0F | version<<4
+	u[8] = u[8]&0x3F | 0x80
+}
+
+// encode write the canonical form of u to dst, len(dst) must be 36
+func (u UUID) encode(dst []byte) {
+	hex.Encode(dst[0:8], u[0:4])
+	dst[8] = '-'
+	hex.Encode(dst[9:13], u[4:6])
+	dst[13] = '-'
+	hex.Encode(dst[14:18], u[6:8])
+	dst[18] = '-'
+	hex.Encode(dst[19:23], u[8:10])
+	dst[23] = '-'
+	hex.Encode(dst[24:], u[10:])
+}
@@ -0,0 +1,194 @@
+package crypt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestNewUUIDv4(t *testing.T) {
+	seen := make(map[UUID]bool)
+	for i := 0; i < 1000; i++ {
+		u, err := NewUUIDv4()
+		if err != nil {
+			t.Fatalf("NewUUIDv4() error = %v", err)
+		}
+		if u.Version() != 4 || u[8]&0xC0 != 0x80 {
+			t.Fatalf("NewUUIDv4() = %v, version %v", u, u.Version())
+		}
+		if seen[u] {
+			t.Fatalf("NewUUIDv4() = %v is duplicated", u)
+		}
+		seen[u] = true
+	}
+}
+
+func TestNewUUIDv5(t *testing.T) {
+	tests := []struct {
+		name      string
+		namespace UUID
+		data      string
+		want      string
+	}{
+		{name: "RFC9562", namespace: UUIDNamespaceDNS, data: "www.example.com", want: "2ed6657d-e927-568b-95e1-2665a8aea6a2"},
+		{name: "URL", namespace: UUIDNamespaceURL, data: "https://github.com/tzdq/go-utils", want: "957d170d-d820-5153-bc01-ce9a7b8c516d"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewUUIDv5(tt.namespace, []byte(tt.data)).String(); got != tt.want {
+				t.Errorf("NewUUIDv5() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewUUIDv7(t *testing.T) {
+	start := time.Now().Add(-time.Millisecond)
+	prev, _ := NewUUIDv7()
+	for i := 0; i < 10000; i++ {
+		u, err := NewUUIDv7()
+		if err != nil {
+			t.Fatalf("NewUUIDv7() error = %v", err)
+		}
+		if u.Version() != 7 || u[8]&0xC0 != 0x80 {
+			t.Fatalf("NewUUIDv7() = %v, version %v", u, u.Version())
+		}
+		// The canonical strings are strictly increasing
+		if u.String() <= prev.String() {
+			t.Fatalf("NewUUIDv7() = %v is not greater than %v", u, prev)
+		}
+		prev = u
+	}
+
+	ts, ok := prev.Time()
+	if !ok || ts.Before(start) || ts.After(time.Now().Add(time.Second)) {
+		t.Errorf("Time() = %v, %v", ts, ok)
+	}
+	if _, ok := UUIDNamespaceDNS.Time(); ok {
+		t.Errorf("Time() of version 1 should return false")
+	}
+}
+
+func TestParseUUID(t *testing.T) {
+	want := UUIDNamespaceDNS
+	tests := []struct {
+		name    string
+		s       string
+		wantErr bool
+	}{
+		{name: "Canonical", s: "6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{name: "UpperCase", s: "6BA7B810-9DAD-11D1-80B4-00C04FD430C8"},
+		{name: "Braced", s: "{6ba7b810-9dad-11d1-80b4-00c04fd430c8}"},
+		{name: "URN", s: "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{name: "URNUpperCase", s: "URN:UUID:6ba7b810-9dad-11d1-80b4-00c04fd430c8"},
+		{name: "Empty", s: "", wantErr: true},
+		{name: "NoHyphen", s: "6ba7b8109dad11d180b400c04fd430c8", wantErr: true},
+		{name: "WrongHyphen", s: "6ba7b8109-dad-11d1-80b4-00c04fd430c8", wantErr: true},
+		{name: "InvalidHex", s: "6ba7b810-9dad-11d1-80b4-00c04fd430cg", wantErr: true},
+		{name: "UnclosedBrace", s: "{6ba7b810-9dad-11d1-80b4-00c04fd430c8]", wantErr: true},
+		{name: "WrongPrefix", s: "urn:uid:6ba7b810-9dad-11d1-80b4-00c04fd430c8", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseUUID(tt.s)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseUUID() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != want {
+				t.Errorf("ParseUUID() = %v, want %v", got, want)
+			}
+			if IsValidUUID(tt.s) == tt.wantErr {
+				t.Errorf("IsValidUUID() = %v, want %v", !tt.wantErr, tt.wantErr)
+			}
+		})
+	}
+
+	if got := MustParseUUID("6ba7b811-9dad-11d1-80b4-00c04fd430c8"); got != UUIDNamespaceURL {
+		t.Errorf("MustParseUUID() = %v, want %v", got, UUIDNamespaceURL)
+	}
+	defer func() {
+		if recover() == nil {
+			t.Errorf("MustParseUUID() with invalid uuid should panic")
+		}
+	}()
+	MustParseUUID("invalid")
+}
+
+func TestUUIDString(t *testing.T) {
+	u := UUIDNamespaceDNS
+	if got := u.String(); got != "6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("String() = %v", got)
+	}
+	if got := u.URN(); got != "urn:uuid:6ba7b810-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("URN() = %v", got)
+	}
+	if u.Version() != 1 || u.IsNil() || !UUIDNil.IsNil() {
+		t.Errorf("Version() = %v, IsNil() = %v", u.Version(), u.IsNil())
+	}
+}
+
+func TestUUIDMarshal(t *testing.T) {
+	type record struct {
+		ID  UUID  `json:"id"`
+		Ref *UUID `json:"ref"`
+	}
+	u, _ := NewUUIDv4()
+	data, err := json.Marshal(record{ID: u})
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"id":"` + u.String() + `","ref":null}`; string(data) != want {
+		t.Errorf("json.Marshal() = %s, want %s", data, want)
+	}
+	var r record
+	if err := json.Unmarshal(data, &r); err != nil || r.ID != u || r.Ref != nil {
+		t.Errorf("json.Unmarshal() = %v, error = %v", r, err)
+	}
+	if err := json.Unmarshal([]byte(`{"id":"invalid"}`), &r); err == nil {
+		t.Errorf("json.Unmarshal() with invalid uuid should return an error")
+	}
+
+	bin, _ := u.MarshalBinary()
+	var b UUID
+	if err := b.UnmarshalBinary(bin); err != nil || b != u {
+		t.Errorf("UnmarshalBinary() = %v, error = %v", b, err)
+	}
+	if err := b.UnmarshalBinary(bin[1:]); err == nil {
+		t.Errorf("UnmarshalBinary() with 15 bytes should return an error")
+	}
+}
+
+func TestUUIDSQL(t *testing.T) {
+	u := UUIDNamespaceOID
+	v, err := u.Value()
+	if err != nil || v != "6ba7b812-9dad-11d1-80b4-00c04fd430c8" {
+		t.Errorf("Value() = %v, error = %v", v, err)
+	}
+
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    UUID
+		wantErr bool
+	}{
+		{name: "String", src: "6ba7b812-9dad-11d1-80b4-00c04fd430c8", want: u},
+		{name: "Text", src: []byte("6ba7b812-9dad-11d1-80b4-00c04fd430c8"), want: u},
+		{name: "Raw", src: u[:], want: u},
+		{name: "Nil", src: nil, want: UUIDNil},
+		{name: "EmptyString", src: "", want: UUIDNil},
+		{name: "InvalidString", src: "6ba7b812", wantErr: true},
+		{name: "InvalidType", src: 12, wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := UUIDNamespaceX500
+			err := got.Scan(tt.src)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Scan() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && got != tt.want {
+				t.Errorf("Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}